@@ -92,7 +92,16 @@ func New(version, release string, bootstrapFn bootstrapFunc) (*cobra.Command, er
 		},
 	}
 
-	rootCmd.AddCommand(versionCmd)
+	// sub-command to print the environment variables that override the configuration parameters
+	envVarsCmd := &cobra.Command{
+		Use:   "envvars",
+		Short: "Print the environment variables mapped to the configuration parameters",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return config.WriteEnvVars(cmd.OutOrStdout(), appEnvPrefix, &appConfig{}) //nolint:wrapcheck
+		},
+	}
+
+	rootCmd.AddCommand(versionCmd, envVarsCmd)
 
 	err := rootCmd.ParseFlags(os.Args)
 	if err != nil {
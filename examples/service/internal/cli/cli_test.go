@@ -27,6 +27,12 @@ func TestNew(t *testing.T) {
 			wantErr:    false,
 			wantOutput: matchTestVersion,
 		},
+		{
+			name:       "call envvars subcommand",
+			osArgs:     []string{AppName, "envvars"},
+			wantErr:    false,
+			wantOutput: matchTestEnvVars,
+		},
 		{
 			name:       "fails with unknown flag",
 			osArgs:     []string{AppName, "--unknown"},
@@ -143,3 +149,13 @@ func matchTestVersion(t *testing.T, out string) {
 
 	t.Errorf("A version number was expected")
 }
+
+func matchTestEnvVars(t *testing.T, out string) {
+	t.Helper()
+
+	if strings.Contains(out, "GOSRVLIBEXAMPLE_SERVERS_PUBLIC_ADDRESS") {
+		return
+	}
+
+	t.Errorf("The environment variables list was expected")
+}
@@ -2,9 +2,10 @@
 package metrics
 
 import (
+	"fmt"
+
 	"github.com/Vonage/gosrvlib/pkg/metrics"
 	prom "github.com/Vonage/gosrvlib/pkg/metrics/prometheus"
-	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -23,29 +24,43 @@ type Metrics interface {
 // Client groups the custom collectors to be shared with other packages.
 type Client struct {
 	// collectorExample is an example collector.
-	collectorExample *prometheus.CounterVec
+	collectorExample metrics.Counter
 }
 
 // New creates a new Client instance.
+// The custom collectors are no-op until the metrics client is created.
 func New() *Client {
-	return &Client{
-		collectorExample: prometheus.NewCounterVec(
-			prometheus.CounterOpts{
-				Name: NameExample,
-				Help: "Example of custom collector.",
-			},
-			[]string{labelCode},
-		),
-	}
+	m := &Client{}
+	_ = m.register(&metrics.Default{}) // the default client never fails
+
+	return m
 }
 
 // CreateMetricsClientFunc returns the metrics Client.
 func (m *Client) CreateMetricsClientFunc() (metrics.Client, error) {
-	opt := prom.WithCollector(m.collectorExample)
-	return prom.New(opt) //nolint:wrapcheck
+	c, err := prom.New()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	err = m.register(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed registering the custom collectors: %w", err)
+	}
+
+	return c, nil
 }
 
 // IncExampleCounter is an example function to increment a counter.
 func (m *Client) IncExampleCounter(code string) {
-	m.collectorExample.With(prometheus.Labels{labelCode: code}).Inc()
+	m.collectorExample.Inc(code)
+}
+
+// register creates the custom collectors with the specified metrics client.
+func (m *Client) register(c metrics.Client) error {
+	var err error
+
+	m.collectorExample, err = c.Counter(NameExample, "Example of custom collector.", labelCode)
+
+	return err //nolint:wrapcheck
 }
@@ -1,9 +1,10 @@
 package metrics
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
-	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,9 +29,20 @@ func TestIncExampleCounter(t *testing.T) {
 	t.Parallel()
 
 	m := New()
-	i := testutil.CollectAndCount(m.collectorExample, NameExample)
-	require.Equal(t, 0, i, "failed to assert right metrics: got %v want %v", i, 0)
+
+	// no-op before the metrics client is created
 	m.IncExampleCounter("test")
-	i = testutil.CollectAndCount(m.collectorExample, NameExample)
-	require.Equal(t, 1, i, "failed to assert right metrics: got %v want %v", i, 1)
+
+	c, err := m.CreateMetricsClientFunc()
+	require.NoError(t, err, "CreateMetricsClientFunc() unexpected error = %v", err)
+
+	m.IncExampleCounter("test")
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+
+	c.MetricsHandlerFunc()(rr, req)
+
+	require.Contains(t, rr.Body.String(), NameExample+`{code="test"} 1`)
 }
@@ -62,6 +62,20 @@ func Bootstrap(bindFn BindFunc, opts ...Option) error {
 		return fmt.Errorf("application bootstrap error: %w", err)
 	}
 
+	if cfg.registry != nil {
+		l.Debug("starting application components")
+
+		err = cfg.registry.Start(ctx)
+		if err != nil {
+			// the registry has already stopped its started components,
+			// but the dependants started by the bind function must be stopped too
+			l.Error("application components start error", zap.Error(err))
+			shutdown(ctx, cfg, l)
+
+			return fmt.Errorf("application components start error: %w", err)
+		}
+	}
+
 	l.Info("application started")
 
 	done := make(chan struct{})
@@ -70,25 +84,36 @@ func Bootstrap(bindFn BindFunc, opts ...Option) error {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
+	// handle reload signals
+	reload := make(chan os.Signal, 1)
+
+	if len(cfg.reloadFuncs) > 0 {
+		signal.Notify(reload, syscall.SIGHUP)
+		defer signal.Stop(reload)
+	}
+
 	go func() {
 		defer close(done)
 
-		select {
-		case <-quit:
-			l.Debug("shutdown signal received")
-		case <-ctx.Done():
-			l.Warn("context canceled")
+		for {
+			select {
+			case <-reload:
+				l.Info("reload signal received")
+				runReloadFuncs(ctx, cfg.reloadFuncs, l)
+			case <-quit:
+				l.Debug("shutdown signal received")
+				return
+			case <-ctx.Done():
+				l.Warn("context canceled")
+				return
+			}
 		}
 	}()
 
 	<-done
 	l.Info("application stopping")
 
-	// send shutdown signal to all dependants (e.g. HTTP servers)
-	close(cfg.shutdownSignalChan)
-
-	// wait for graceful shutdown of dependants
-	syncWaitGroupTimeout(cfg.shutdownWaitGroup, cfg.shutdownTimeout, l)
+	shutdown(ctx, cfg, l)
 
 	// cancel application context
 	cancel()
@@ -98,6 +123,34 @@ func Bootstrap(bindFn BindFunc, opts ...Option) error {
 	return nil
 }
 
+// shutdown signals the shutdown to the dependants (e.g. HTTP servers),
+// waits for them and stops the application components in reverse order.
+func shutdown(ctx context.Context, cfg *config, l *zap.Logger) {
+	// send shutdown signal to all dependants
+	close(cfg.shutdownSignalChan)
+
+	// wait for graceful shutdown of dependants
+	syncWaitGroupTimeout(cfg.shutdownWaitGroup, cfg.shutdownTimeout, l)
+
+	if cfg.registry != nil {
+		// the components must be stopped even if the application context has been canceled
+		err := cfg.registry.Stop(context.WithoutCancel(ctx))
+		if err != nil {
+			l.Error("application components stop error", zap.Error(err))
+		}
+	}
+}
+
+// runReloadFuncs calls the reload functions in order and logs their errors.
+func runReloadFuncs(ctx context.Context, fns []ReloadFunc, l *zap.Logger) {
+	for _, fn := range fns {
+		err := fn(ctx)
+		if err != nil {
+			l.Error("reload error", zap.Error(err))
+		}
+	}
+}
+
 // syncWaitGroupTimeout adds a timeout to the sync.WaitGroup.Wait().
 func syncWaitGroupTimeout(wg *sync.WaitGroup, timeout time.Duration, l *zap.Logger) {
 	wait := make(chan struct{})
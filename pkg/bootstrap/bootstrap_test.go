@@ -21,6 +21,17 @@ func TestBootstrap(t *testing.T) {
 	shutdownWG := &sync.WaitGroup{}
 	shutdownSG := make(chan struct{})
 
+	registry := NewRegistry()
+	componentStarted := false
+	componentStopped := false
+
+	failRegistry := NewRegistry()
+	_ = failRegistry.Register("fail", ComponentFuncs{
+		StartFunc: func(context.Context) error {
+			return errors.New("start error")
+		},
+	})
+
 	tests := []struct {
 		opts                    []Option
 		name                    string
@@ -80,6 +91,38 @@ func TestBootstrap(t *testing.T) {
 			stopAfter: 500 * time.Millisecond,
 			wantErr:   false,
 		},
+		{
+			name: "should fail due to component start",
+			opts: []Option{
+				WithShutdownTimeout(1 * time.Millisecond),
+				WithComponentRegistry(failRegistry),
+			},
+			bindFunc: func(context.Context, *zap.Logger, metrics.Client) error {
+				return nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "should succeed with components and exit with context cancel",
+			opts: []Option{
+				WithShutdownTimeout(1 * time.Millisecond),
+				WithComponentRegistry(registry),
+			},
+			bindFunc: func(context.Context, *zap.Logger, metrics.Client) error {
+				return registry.Register("component", ComponentFuncs{
+					StartFunc: func(context.Context) error {
+						componentStarted = true
+						return nil
+					},
+					StopFunc: func(context.Context) error {
+						componentStopped = true
+						return errors.New("stop error")
+					},
+				})
+			},
+			stopAfter: 100 * time.Millisecond,
+			wantErr:   false,
+		},
 		{
 			name: "should succeed and exit with SIGTERM",
 			opts: []Option{
@@ -152,6 +195,65 @@ func TestBootstrap(t *testing.T) {
 			}
 		})
 	}
+
+	require.True(t, componentStarted)
+	require.True(t, componentStopped)
+}
+
+//nolint:paralleltest
+func TestBootstrap_componentStartError(t *testing.T) {
+	// cannot run in parallel because signals are received by all parallel tests
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+	registry := NewRegistry()
+
+	var (
+		dependantStopped bool
+		componentStopped bool
+	)
+
+	opts := []Option{
+		WithContext(t.Context()),
+		WithLogger(logging.NopLogger()),
+		WithShutdownTimeout(1 * time.Second),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+		WithComponentRegistry(registry),
+	}
+
+	bindFn := func(context.Context, *zap.Logger, metrics.Client) error {
+		// dependant started by the bind function (e.g. HTTP server)
+		shutdownWG.Add(1)
+
+		go func() {
+			defer shutdownWG.Done()
+
+			<-shutdownSG
+
+			dependantStopped = true
+		}()
+
+		err := registry.Register("ok", ComponentFuncs{
+			StopFunc: func(context.Context) error {
+				componentStopped = true
+				return nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return registry.Register("fail", ComponentFuncs{
+			StartFunc: func(context.Context) error {
+				return errors.New("start error")
+			},
+		}, WithDependsOn("ok"))
+	}
+
+	err := Bootstrap(bindFn, opts...)
+	require.Error(t, err)
+	require.True(t, dependantStopped)
+	require.True(t, componentStopped)
 }
 
 func Test_syncWaitGroupTimeout(t *testing.T) {
@@ -169,3 +271,34 @@ func Test_syncWaitGroupTimeout(t *testing.T) {
 	// wait complete
 	syncWaitGroupTimeout(wg, 1*time.Second, logging.NopLogger())
 }
+
+//nolint:paralleltest
+func TestBootstrap_reload(t *testing.T) {
+	// cannot run in parallel because signals are received by all parallel tests
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	reloaded := make(chan struct{}, 1)
+
+	opts := []Option{
+		WithContext(ctx),
+		WithLogger(logging.NopLogger()),
+		WithShutdownTimeout(1 * time.Millisecond),
+		WithReloadFunc(func(context.Context) error {
+			reloaded <- struct{}{}
+			return errors.New("reload error")
+		}),
+	}
+
+	bindFn := func(context.Context, *zap.Logger, metrics.Client) error {
+		time.AfterFunc(100*time.Millisecond, func() {
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+		})
+
+		return nil
+	}
+
+	err := Bootstrap(bindFn, opts...)
+	require.NoError(t, err)
+	require.Len(t, reloaded, 1)
+}
@@ -0,0 +1,321 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/healthcheck"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// DefaultComponentStopTimeout is the default maximum time to wait for a single component to stop.
+const DefaultComponentStopTimeout = 10 * time.Second
+
+// Component is the interface of an application component with a managed lifecycle
+// (e.g. HTTP servers, database connections, periodic jobs, message consumers).
+type Component interface {
+	// Start starts the component. It should not block.
+	Start(ctx context.Context) error
+
+	// Stop gracefully stops the component within the context deadline.
+	Stop(ctx context.Context) error
+
+	// HealthCheck returns an error if the component is not healthy.
+	HealthCheck(ctx context.Context) error
+}
+
+// ComponentFuncs is an adapter to use ordinary functions as a Component.
+// Any nil function is considered successful.
+type ComponentFuncs struct {
+	// StartFunc is the function called to start the component.
+	StartFunc func(ctx context.Context) error
+
+	// StopFunc is the function called to stop the component.
+	StopFunc func(ctx context.Context) error
+
+	// HealthCheckFunc is the function called to check the component health.
+	HealthCheckFunc func(ctx context.Context) error
+}
+
+// Start calls StartFunc.
+func (c ComponentFuncs) Start(ctx context.Context) error {
+	return callComponentFunc(ctx, c.StartFunc)
+}
+
+// Stop calls StopFunc.
+func (c ComponentFuncs) Stop(ctx context.Context) error {
+	return callComponentFunc(ctx, c.StopFunc)
+}
+
+// HealthCheck calls HealthCheckFunc.
+func (c ComponentFuncs) HealthCheck(ctx context.Context) error {
+	return callComponentFunc(ctx, c.HealthCheckFunc)
+}
+
+func callComponentFunc(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	return fn(ctx)
+}
+
+// ComponentOption is a type alias for a function that configures a registered component.
+type ComponentOption func(*componentEntry)
+
+// WithDependsOn sets the IDs of the components that must be started before this one,
+// and stopped after this one.
+func WithDependsOn(ids ...string) ComponentOption {
+	return func(e *componentEntry) {
+		e.dependsOn = append(e.dependsOn, ids...)
+	}
+}
+
+// WithStopTimeout sets the maximum time to wait for the component to stop (default DefaultComponentStopTimeout).
+func WithStopTimeout(timeout time.Duration) ComponentOption {
+	return func(e *componentEntry) {
+		e.stopTimeout = timeout
+	}
+}
+
+// WithoutHealthCheck excludes the component from the health checks.
+func WithoutHealthCheck() ComponentOption {
+	return func(e *componentEntry) {
+		e.noHealthCheck = true
+	}
+}
+
+type componentEntry struct {
+	id            string
+	component     Component
+	dependsOn     []string
+	stopTimeout   time.Duration
+	noHealthCheck bool
+}
+
+// Registry manages the lifecycle of the application components.
+// The components are started in dependency order and stopped in reverse order.
+type Registry struct {
+	mu      sync.Mutex
+	entries []*componentEntry
+	index   map[string]*componentEntry
+	started []*componentEntry
+}
+
+// NewRegistry creates a new empty component registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		index: make(map[string]*componentEntry),
+	}
+}
+
+// Register adds a component to the registry with the given unique ID.
+// Components can only be registered before the registry is started.
+func (r *Registry) Register(id string, c Component, opts ...ComponentOption) error {
+	if id == "" {
+		return errors.New("the component ID is required")
+	}
+
+	if c == nil {
+		return fmt.Errorf("the component %s is nil", id)
+	}
+
+	e := &componentEntry{
+		id:          id,
+		component:   c,
+		stopTimeout: DefaultComponentStopTimeout,
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(e)
+	}
+
+	if e.stopTimeout <= 0 {
+		return fmt.Errorf("invalid stop timeout for the component %s", id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.started) > 0 {
+		return fmt.Errorf("cannot register the component %s: the registry is already started", id)
+	}
+
+	if _, ok := r.index[id]; ok {
+		return fmt.Errorf("duplicate component ID: %s", id)
+	}
+
+	r.entries = append(r.entries, e)
+	r.index[id] = e
+
+	return nil
+}
+
+// Start starts all the registered components in dependency order.
+// If a component fails to start, the components already started are stopped in reverse order.
+func (r *Registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.started) > 0 {
+		return errors.New("the component registry is already started")
+	}
+
+	order, err := r.sortEntries()
+	if err != nil {
+		return err
+	}
+
+	l := logging.FromContext(ctx)
+
+	for _, e := range order {
+		l.Debug("starting component", zap.String("component", e.id))
+
+		err := e.component.Start(ctx)
+		if err != nil {
+			l.Error("component start failed", zap.String("component", e.id), zap.Error(err))
+
+			_ = r.stopStarted(context.WithoutCancel(ctx))
+
+			return fmt.Errorf("cannot start the component %s: %w", e.id, err)
+		}
+
+		r.started = append(r.started, e)
+	}
+
+	return nil
+}
+
+// Stop stops all the started components in reverse order.
+// Each component is stopped within its own timeout and all the errors are returned.
+func (r *Registry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stopStarted(ctx)
+}
+
+// HealthChecks returns the health checks of all the registered components, in registration order.
+func (r *Registry) HealthChecks() []healthcheck.HealthCheck {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	checks := make([]healthcheck.HealthCheck, 0, len(r.entries))
+
+	for _, e := range r.entries {
+		if e.noHealthCheck {
+			continue
+		}
+
+		checks = append(checks, healthcheck.New(e.id, e.component))
+	}
+
+	return checks
+}
+
+// HealthCheckHandler returns a healthcheck.Handler checking all the registered components.
+// Additional health checks can be included with the extra parameter.
+func (r *Registry) HealthCheckHandler(extra []healthcheck.HealthCheck, opts ...healthcheck.HandlerOption) *healthcheck.Handler {
+	return healthcheck.NewHandler(append(r.HealthChecks(), extra...), opts...)
+}
+
+// stopStarted stops the started components in reverse order.
+// It must be called with the mutex held.
+func (r *Registry) stopStarted(ctx context.Context) error {
+	l := logging.FromContext(ctx)
+
+	var errs []error
+
+	for i := len(r.started) - 1; i >= 0; i-- {
+		e := r.started[i]
+
+		l.Debug("stopping component", zap.String("component", e.id))
+
+		err := stopComponent(ctx, e)
+		if err != nil {
+			l.Error("component stop failed", zap.String("component", e.id), zap.Error(err))
+
+			errs = append(errs, fmt.Errorf("cannot stop the component %s: %w", e.id, err))
+		}
+	}
+
+	r.started = nil
+
+	return errors.Join(errs...)
+}
+
+// stopComponent stops a single component, returning an error if the timeout is reached.
+func stopComponent(ctx context.Context, e *componentEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, e.stopTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- e.component.Stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("stop timeout: %w", ctx.Err())
+	}
+}
+
+// sortEntries returns the registered components in dependency order,
+// preserving the registration order among independent components.
+// It must be called with the mutex held.
+func (r *Registry) sortEntries() ([]*componentEntry, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(r.entries))
+	order := make([]*componentEntry, 0, len(r.entries))
+
+	var visit func(e *componentEntry) error
+
+	visit = func(e *componentEntry) error {
+		switch state[e.id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular dependency on the component %s", e.id)
+		}
+
+		state[e.id] = visiting
+
+		for _, dep := range e.dependsOn {
+			de, ok := r.index[dep]
+			if !ok {
+				return fmt.Errorf("the component %s depends on the unknown component %s", e.id, dep)
+			}
+
+			err := visit(de)
+			if err != nil {
+				return err
+			}
+		}
+
+		state[e.id] = visited
+		order = append(order, e)
+
+		return nil
+	}
+
+	for _, e := range r.entries {
+		err := visit(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,195 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/healthcheck"
+	"github.com/stretchr/testify/require"
+)
+
+type testLifecycle struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *testLifecycle) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, event)
+}
+
+func (l *testLifecycle) component(id string, startErr, stopErr, checkErr error) Component {
+	return ComponentFuncs{
+		StartFunc: func(_ context.Context) error {
+			l.add("start:" + id)
+			return startErr
+		},
+		StopFunc: func(_ context.Context) error {
+			l.add("stop:" + id)
+			return stopErr
+		},
+		HealthCheckFunc: func(_ context.Context) error {
+			return checkErr
+		},
+	}
+}
+
+func TestComponentFuncs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	c := ComponentFuncs{}
+	require.NoError(t, c.Start(ctx))
+	require.NoError(t, c.Stop(ctx))
+	require.NoError(t, c.HealthCheck(ctx))
+
+	errTest := errors.New("error")
+	fn := func(context.Context) error { return errTest }
+
+	c = ComponentFuncs{StartFunc: fn, StopFunc: fn, HealthCheckFunc: fn}
+	require.ErrorIs(t, c.Start(ctx), errTest)
+	require.ErrorIs(t, c.Stop(ctx), errTest)
+	require.ErrorIs(t, c.HealthCheck(ctx), errTest)
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+
+	require.NoError(t, r.Register("a", ComponentFuncs{}))
+	require.Error(t, r.Register("", ComponentFuncs{}))
+	require.Error(t, r.Register("b", nil))
+	require.Error(t, r.Register("a", ComponentFuncs{}))
+	require.Error(t, r.Register("c", ComponentFuncs{}, WithStopTimeout(0)))
+
+	require.NoError(t, r.Start(context.Background()))
+	require.Error(t, r.Register("d", ComponentFuncs{}))
+	require.Error(t, r.Start(context.Background()))
+}
+
+func TestRegistry_StartStop(t *testing.T) {
+	t.Parallel()
+
+	lc := &testLifecycle{}
+	r := NewRegistry()
+
+	require.NoError(t, r.Register("server", lc.component("server", nil, nil, nil), WithDependsOn("db", "cache")))
+	require.NoError(t, r.Register("db", lc.component("db", nil, nil, nil)))
+	require.NoError(t, r.Register("consumer", lc.component("consumer", nil, nil, nil), WithDependsOn("db")))
+	require.NoError(t, r.Register("cache", lc.component("cache", nil, nil, nil)))
+
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Stop(context.Background()))
+
+	require.Equal(t, []string{
+		"start:db",
+		"start:cache",
+		"start:server",
+		"start:consumer",
+		"stop:consumer",
+		"stop:server",
+		"stop:cache",
+		"stop:db",
+	}, lc.events)
+
+	// nothing to stop
+	require.NoError(t, r.Stop(context.Background()))
+	require.Len(t, lc.events, 8)
+}
+
+func TestRegistry_Start_errors(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("start error")
+
+	lc := &testLifecycle{}
+	r := NewRegistry()
+
+	require.NoError(t, r.Register("db", lc.component("db", nil, nil, nil)))
+	require.NoError(t, r.Register("cache", lc.component("cache", nil, errors.New("stop error"), nil)))
+	require.NoError(t, r.Register("server", lc.component("server", errTest, nil, nil), WithDependsOn("db")))
+
+	err := r.Start(context.Background())
+	require.ErrorIs(t, err, errTest)
+	require.Equal(t, []string{"start:db", "start:cache", "start:server", "stop:cache", "stop:db"}, lc.events)
+
+	r = NewRegistry()
+	require.NoError(t, r.Register("a", ComponentFuncs{}, WithDependsOn("missing")))
+	require.Error(t, r.Start(context.Background()))
+
+	r = NewRegistry()
+	require.NoError(t, r.Register("a", ComponentFuncs{}, WithDependsOn("b")))
+	require.NoError(t, r.Register("b", ComponentFuncs{}, WithDependsOn("c")))
+	require.NoError(t, r.Register("c", ComponentFuncs{}, WithDependsOn("a")))
+	require.Error(t, r.Start(context.Background()))
+}
+
+func TestRegistry_Stop_errors(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("stop error")
+
+	slow := ComponentFuncs{
+		StopFunc: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		},
+	}
+
+	lc := &testLifecycle{}
+	r := NewRegistry()
+
+	require.NoError(t, r.Register("a", lc.component("a", nil, errTest, nil)))
+	require.NoError(t, r.Register("slow", slow, WithStopTimeout(10*time.Millisecond)))
+	require.NoError(t, r.Register("b", lc.component("b", nil, nil, nil)))
+
+	require.NoError(t, r.Start(context.Background()))
+
+	err := r.Stop(context.Background())
+	require.ErrorIs(t, err, errTest)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []string{"start:a", "start:b", "stop:b", "stop:a"}, lc.events)
+}
+
+func TestRegistry_HealthCheckHandler(t *testing.T) {
+	t.Parallel()
+
+	lc := &testLifecycle{}
+	r := NewRegistry()
+
+	require.NoError(t, r.Register("db", lc.component("db", nil, nil, nil)))
+	require.NoError(t, r.Register("cache", lc.component("cache", nil, nil, errors.New("unhealthy"))))
+	require.NoError(t, r.Register("job", lc.component("job", nil, nil, errors.New("ignored")), WithoutHealthCheck()))
+
+	checks := r.HealthChecks()
+	require.Len(t, checks, 2)
+	require.Equal(t, "db", checks[0].ID)
+	require.Equal(t, "cache", checks[1].ID)
+
+	var data map[string]string
+
+	h := r.HealthCheckHandler(
+		[]healthcheck.HealthCheck{healthcheck.New("extra", lc.component("extra", nil, nil, nil))},
+		healthcheck.WithResultWriter(func(_ context.Context, w http.ResponseWriter, statusCode int, d any) {
+			data = d.(map[string]string) //nolint:forcetypeassert
+			w.WriteHeader(statusCode)
+		}),
+	)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.Equal(t, map[string]string{"db": "OK", "cache": "unhealthy", "extra": "OK"}, data)
+}
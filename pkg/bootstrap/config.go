@@ -17,6 +17,10 @@ type CreateLoggerFunc func() (*zap.Logger, error)
 // CreateMetricsClientFunc creates a new metrics client.
 type CreateMetricsClientFunc func() (metrics.Client, error)
 
+// ReloadFunc is the type of function called when the application receives the SIGHUP signal
+// (e.g. to reload the configuration).
+type ReloadFunc func(context.Context) error
+
 // BindFunc represents the function responsible to wire up all components of the application.
 type BindFunc func(context.Context, *zap.Logger, metrics.Client) error
 
@@ -27,6 +31,8 @@ type config struct {
 	shutdownTimeout         time.Duration
 	shutdownWaitGroup       *sync.WaitGroup
 	shutdownSignalChan      chan struct{}
+	registry                *Registry
+	reloadFuncs             []ReloadFunc
 }
 
 func defaultConfig() *config {
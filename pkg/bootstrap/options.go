@@ -66,3 +66,26 @@ func WithShutdownSignalChan(ch chan struct{}) Option {
 		cfg.shutdownSignalChan = ch
 	}
 }
+
+// WithComponentRegistry sets the registry of the application components.
+// The components registered by the BindFunc are started in dependency order after the binding,
+// and stopped in reverse order on shutdown, after the dependants of the shutdown wait group.
+func WithComponentRegistry(r *Registry) Option {
+	return func(cfg *config) {
+		cfg.registry = r
+	}
+}
+
+// WithReloadFunc adds a function to be called when the application receives the SIGHUP signal.
+// This can be used to reload the configuration (e.g. config.Watcher.Reload).
+// The reload functions are called in order and their errors are logged.
+// A nil function is ignored.
+func WithReloadFunc(fn ReloadFunc) Option {
+	return func(cfg *config) {
+		if fn == nil {
+			return
+		}
+
+		cfg.reloadFuncs = append(cfg.reloadFuncs, fn)
+	}
+}
@@ -91,3 +91,27 @@ func TestWithShutdownSignalChan(t *testing.T) {
 	WithShutdownSignalChan(v)(cfg)
 	require.Equal(t, v, cfg.shutdownSignalChan)
 }
+
+func TestWithComponentRegistry(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	v := NewRegistry()
+	WithComponentRegistry(v)(cfg)
+	require.Equal(t, v, cfg.registry)
+}
+
+func TestWithReloadFunc(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	fn := func(context.Context) error { return nil }
+	WithReloadFunc(fn)(cfg)
+	WithReloadFunc(fn)(cfg)
+	require.Len(t, cfg.reloadFuncs, 2)
+
+	WithReloadFunc(nil)(cfg)
+	require.Len(t, cfg.reloadFuncs, 2)
+}
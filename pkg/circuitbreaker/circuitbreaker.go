@@ -0,0 +1,362 @@
+/*
+Package circuitbreaker implements the circuit breaker pattern to stop calling a
+failing dependency and give it time to recover.
+
+The circuit breaker has three states:
+
+  - closed: the calls are allowed and their results are recorded in a rolling
+    time window. When the number of consecutive failures, or the failure rate
+    in the window, reaches the configured threshold, the breaker trips to open.
+
+  - open: the calls are rejected immediately with ErrOpen. After the open
+    timeout the breaker moves to half-open.
+
+  - half-open: a limited number of probe calls are allowed. If all of them
+    succeed the breaker closes, otherwise it opens again.
+
+The calls canceled by the caller (context.Canceled), unless classified as
+failures, are ignored: they neither count as successes nor as failures, and a
+canceled half-open probe releases its slot for a new probe.
+
+The same CircuitBreaker can guard generic functions (Execute), the
+github.com/Vonage/gosrvlib/pkg/retrier tasks (Task), and the HTTP requests
+via an http.RoundTripper (RoundTripper) to be used with
+github.com/Vonage/gosrvlib/pkg/httpclient.WithRoundTripper. The RetryIf and
+HTTPRetryIf functions stop the retriers as soon as the breaker is open.
+
+The state changes are logged and counted with the metrics.Client
+IncErrorCounter method, as well as the rejected calls.
+*/
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultConsecutiveFailures is the default number of consecutive failures that trips the breaker.
+	DefaultConsecutiveFailures = 5
+
+	// DefaultOpenTimeout is the default time the breaker stays open before allowing the probe calls.
+	DefaultOpenTimeout = 30 * time.Second
+
+	// DefaultHalfOpenMaxCalls is the default number of probe calls allowed in the half-open state.
+	DefaultHalfOpenMaxCalls = 1
+
+	// DefaultWindow is the default duration of the rolling window used to compute the failure rate.
+	DefaultWindow = 10 * time.Second
+
+	// DefaultWindowBuckets is the default number of buckets of the rolling window.
+	DefaultWindowBuckets = 10
+
+	// metricsOperation is the operation name used to count the events with the metrics client.
+	metricsOperation = "circuitbreaker"
+
+	// metricsRejected is the code used to count the rejected calls with the metrics client.
+	metricsRejected = "rejected"
+)
+
+// outcome is the recorded result of a call.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+// ErrOpen is returned when a call is rejected because the breaker is open,
+// or because the maximum number of half-open probe calls is already in flight.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the circuit breaker state.
+type State int
+
+// Circuit breaker states.
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+// String returns the state name.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	}
+
+	return "unknown"
+}
+
+// IsFailureFn is the signature of the function used to decide if a call result is a failure.
+type IsFailureFn func(err error) bool
+
+// StateChangeFn is the signature of the function called on each state change.
+type StateChangeFn func(name string, from, to State)
+
+// CircuitBreaker represents an instance of the circuit breaker.
+type CircuitBreaker struct {
+	name                string
+	consecutiveFailures uint
+	failureRate         float64
+	minCalls            uint
+	openTimeout         time.Duration
+	halfOpenMaxCalls    uint
+	isFailureFn         IsFailureFn
+	stateChangeFn       StateChangeFn
+	metric              metrics.Client
+	nowFn               func() time.Time
+
+	mux           sync.Mutex
+	state         State
+	generation    uint64
+	window        *window
+	consecutive   uint
+	openedAt      time.Time
+	halfOpenCalls uint
+	halfOpenOK    uint
+}
+
+// New creates a new circuit breaker.
+// The name identifies the guarded dependency in logs and metrics.
+func New(name string, opts ...Option) (*CircuitBreaker, error) {
+	if name == "" {
+		return nil, errors.New("the circuit breaker name is required")
+	}
+
+	cb := &CircuitBreaker{
+		name:                name,
+		consecutiveFailures: DefaultConsecutiveFailures,
+		openTimeout:         DefaultOpenTimeout,
+		halfOpenMaxCalls:    DefaultHalfOpenMaxCalls,
+		isFailureFn:         DefaultIsFailure,
+		metric:              &metrics.Default{},
+		nowFn:               time.Now,
+		window:              newWindow(DefaultWindow, DefaultWindowBuckets),
+	}
+
+	for _, applyOpt := range opts {
+		err := applyOpt(cb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cb.consecutiveFailures == 0 && cb.failureRate == 0 {
+		return nil, errors.New("at least one failure threshold is required")
+	}
+
+	return cb, nil
+}
+
+// DefaultIsFailure is the default function to check if a call result is a failure.
+// The calls canceled by the caller are not considered failures, and are ignored.
+func DefaultIsFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// Name returns the circuit breaker name.
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// State returns the current state.
+func (cb *CircuitBreaker) State() State {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	cb.checkOpenTimeout(context.Background(), cb.nowFn())
+
+	return cb.state
+}
+
+// Execute runs the function if the breaker allows it, and records the result.
+// It returns ErrOpen without calling the function if the breaker is open.
+// If the function panics, the call is recorded as a failure and the panic is propagated.
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	gen, err := cb.allow(ctx)
+	if err != nil {
+		return err
+	}
+
+	// a panicking call is recorded as a failure before the panic propagates
+	out := outcomeFailure
+
+	defer func() {
+		cb.record(ctx, gen, out)
+	}()
+
+	err = fn(ctx)
+
+	out = callOutcome(cb.isFailureFn(err), err)
+
+	return err
+}
+
+// Reset moves the breaker to the closed state and clears all the counters.
+func (cb *CircuitBreaker) Reset(ctx context.Context) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	cb.setState(ctx, StateClosed, cb.nowFn())
+}
+
+// allow checks if a call is allowed and returns the current state generation.
+func (cb *CircuitBreaker) allow(ctx context.Context) (uint64, error) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	cb.checkOpenTimeout(ctx, cb.nowFn())
+
+	switch cb.state {
+	case StateOpen:
+		cb.reject()
+		return 0, ErrOpen
+	case StateHalfOpen:
+		if cb.halfOpenCalls >= cb.halfOpenMaxCalls {
+			cb.reject()
+			return 0, ErrOpen
+		}
+
+		cb.halfOpenCalls++
+	case StateClosed:
+	}
+
+	return cb.generation, nil
+}
+
+// callOutcome returns the outcome of a call:
+// the canceled calls not classified as failures are ignored.
+func callOutcome(failed bool, err error) outcome {
+	switch {
+	case failed:
+		return outcomeFailure
+	case errors.Is(err, context.Canceled):
+		return outcomeIgnored
+	}
+
+	return outcomeSuccess
+}
+
+// record records the outcome of a call allowed in the specified state generation.
+// The results of the calls started in a previous generation are ignored.
+func (cb *CircuitBreaker) record(ctx context.Context, gen uint64, out outcome) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	if gen != cb.generation {
+		return
+	}
+
+	if out == outcomeIgnored {
+		if cb.state == StateHalfOpen {
+			// release the probe slot without deciding the state
+			cb.halfOpenCalls--
+		}
+
+		return
+	}
+
+	failed := out == outcomeFailure
+	now := cb.nowFn()
+
+	switch cb.state {
+	case StateClosed:
+		cb.window.add(now, failed)
+
+		if !failed {
+			cb.consecutive = 0
+			return
+		}
+
+		cb.consecutive++
+
+		if cb.shouldTrip(now) {
+			cb.setState(ctx, StateOpen, now)
+		}
+	case StateHalfOpen:
+		if failed {
+			cb.setState(ctx, StateOpen, now)
+			return
+		}
+
+		cb.halfOpenOK++
+
+		if cb.halfOpenOK >= cb.halfOpenMaxCalls {
+			cb.setState(ctx, StateClosed, now)
+		}
+	case StateOpen:
+	}
+}
+
+// shouldTrip returns true if any failure threshold has been reached.
+func (cb *CircuitBreaker) shouldTrip(now time.Time) bool {
+	if cb.consecutiveFailures > 0 && cb.consecutive >= cb.consecutiveFailures {
+		return true
+	}
+
+	if cb.failureRate == 0 {
+		return false
+	}
+
+	total, failures := cb.window.counts(now)
+
+	return total >= uint64(cb.minCalls) && float64(failures)/float64(total) >= cb.failureRate
+}
+
+// checkOpenTimeout moves the breaker to half-open when the open timeout expires.
+func (cb *CircuitBreaker) checkOpenTimeout(ctx context.Context, now time.Time) {
+	if cb.state == StateOpen && now.Sub(cb.openedAt) >= cb.openTimeout {
+		cb.setState(ctx, StateHalfOpen, now)
+	}
+}
+
+// setState changes the state, resets the counters and starts a new generation.
+func (cb *CircuitBreaker) setState(ctx context.Context, state State, now time.Time) {
+	prev := cb.state
+
+	cb.state = state
+	cb.generation++
+	cb.consecutive = 0
+	cb.halfOpenCalls = 0
+	cb.halfOpenOK = 0
+	cb.window.reset()
+
+	if state == StateOpen {
+		cb.openedAt = now
+	}
+
+	if prev == state {
+		return
+	}
+
+	logging.FromContext(ctx).Warn(
+		"circuit breaker state changed",
+		zap.String("circuit_breaker", cb.name),
+		zap.String("from", prev.String()),
+		zap.String("to", state.String()),
+	)
+
+	cb.metric.IncErrorCounter(cb.name, metricsOperation, state.String())
+
+	if cb.stateChangeFn != nil {
+		cb.stateChangeFn(cb.name, prev, state)
+	}
+}
+
+// reject counts a rejected call.
+func (cb *CircuitBreaker) reject() {
+	cb.metric.IncErrorCounter(cb.name, metricsOperation, metricsRejected)
+}
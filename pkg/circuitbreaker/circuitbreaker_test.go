@@ -0,0 +1,365 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+type testMetrics struct {
+	metrics.Default
+
+	mux    sync.Mutex
+	events []string
+}
+
+func (m *testMetrics) IncErrorCounter(task, operation, code string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.events = append(m.events, task+" "+operation+" "+code)
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(t *testing.T, opts ...Option) (*CircuitBreaker, *testClock) {
+	t.Helper()
+
+	cb, err := New("test", opts...)
+	require.NoError(t, err)
+
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cb.nowFn = clock.Now
+
+	return cb, clock
+}
+
+var errTest = errors.New("test error")
+
+func failFn(_ context.Context) error {
+	return errTest
+}
+
+func okFn(_ context.Context) error {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	cb, err := New("test")
+	require.NoError(t, err)
+	require.Equal(t, "test", cb.Name())
+	require.Equal(t, StateClosed, cb.State())
+	require.Equal(t, uint(DefaultConsecutiveFailures), cb.consecutiveFailures)
+	require.Equal(t, DefaultOpenTimeout, cb.openTimeout)
+
+	_, err = New("")
+	require.Error(t, err)
+
+	_, err = New("test", WithHalfOpenMaxCalls(0))
+	require.Error(t, err)
+
+	_, err = New("test", WithConsecutiveFailures(0))
+	require.Error(t, err)
+
+	_, err = New("test", WithConsecutiveFailures(0), WithFailureRate(0.5, 10))
+	require.NoError(t, err)
+}
+
+func TestState_String(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "closed", StateClosed.String())
+	require.Equal(t, "half-open", StateHalfOpen.String())
+	require.Equal(t, "open", StateOpen.String())
+	require.Equal(t, "unknown", State(-1).String())
+}
+
+func TestDefaultIsFailure(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, DefaultIsFailure(nil))
+	require.False(t, DefaultIsFailure(context.Canceled))
+	require.True(t, DefaultIsFailure(context.DeadlineExceeded))
+	require.True(t, DefaultIsFailure(errTest))
+}
+
+func TestCircuitBreaker_consecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	m := &testMetrics{}
+
+	var changes []string
+
+	cb, clock := newTestBreaker(t,
+		WithConsecutiveFailures(3),
+		WithOpenTimeout(time.Second),
+		WithHalfOpenMaxCalls(2),
+		WithMetrics(m),
+		WithStateChangeFn(func(name string, from, to State) {
+			changes = append(changes, name+": "+from.String()+" -> "+to.String())
+		}),
+	)
+
+	ctx := t.Context()
+
+	// a success resets the consecutive failures
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateClosed, cb.State())
+
+	// trip
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateOpen, cb.State())
+
+	called := false
+	err := cb.Execute(ctx, func(_ context.Context) error {
+		called = true
+		return nil
+	})
+	require.ErrorIs(t, err, ErrOpen)
+	require.False(t, called)
+
+	// half-open: a failed probe opens again
+	clock.Add(time.Second)
+	require.Equal(t, StateHalfOpen, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateOpen, cb.State())
+
+	// half-open: all the probes must succeed
+	clock.Add(time.Second)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateHalfOpen, cb.State())
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateClosed, cb.State())
+
+	require.Equal(t, []string{
+		"test: closed -> open",
+		"test: open -> half-open",
+		"test: half-open -> open",
+		"test: open -> half-open",
+		"test: half-open -> closed",
+	}, changes)
+
+	require.Equal(t, []string{
+		"test circuitbreaker open",
+		"test circuitbreaker rejected",
+		"test circuitbreaker half-open",
+		"test circuitbreaker open",
+		"test circuitbreaker half-open",
+		"test circuitbreaker closed",
+	}, m.events)
+}
+
+func TestCircuitBreaker_halfOpenMaxCalls(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestBreaker(t, WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+
+	ctx := t.Context()
+
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+
+	clock.Add(time.Second)
+
+	err := cb.Execute(ctx, func(ctx context.Context) error {
+		// the second concurrent probe is rejected
+		require.ErrorIs(t, cb.Execute(ctx, okFn), ErrOpen)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_failureRate(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestBreaker(t,
+		WithConsecutiveFailures(0),
+		WithFailureRate(0.5, 4),
+		WithWindow(4*time.Second, 4),
+	)
+
+	ctx := t.Context()
+
+	// not enough calls
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateClosed, cb.State())
+
+	// the old failures slide out of the window
+	clock.Add(4 * time.Second)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateClosed, cb.State())
+
+	clock.Add(time.Second)
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateClosed, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreaker_staleResult(t *testing.T) {
+	t.Parallel()
+
+	cb, _ := newTestBreaker(t, WithConsecutiveFailures(1))
+
+	ctx := t.Context()
+
+	err := cb.Execute(ctx, func(ctx context.Context) error {
+		// the breaker trips while this call is in flight
+		require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+		require.Equal(t, StateOpen, cb.State())
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	// the success of the call started before the trip is ignored
+	require.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreaker_Reset(t *testing.T) {
+	t.Parallel()
+
+	cb, _ := newTestBreaker(t, WithConsecutiveFailures(1))
+
+	ctx := t.Context()
+
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateOpen, cb.State())
+
+	cb.Reset(ctx)
+	require.Equal(t, StateClosed, cb.State())
+	require.NoError(t, cb.Execute(ctx, okFn))
+}
+
+func TestCircuitBreaker_isFailureFn(t *testing.T) {
+	t.Parallel()
+
+	cb, _ := newTestBreaker(t,
+		WithConsecutiveFailures(1),
+		WithIsFailureFn(func(err error) bool { return !errors.Is(err, errTest) && err != nil }),
+	)
+
+	ctx := t.Context()
+
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateClosed, cb.State())
+
+	require.ErrorIs(t, cb.Execute(ctx, func(_ context.Context) error { return context.Canceled }), context.Canceled)
+	require.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreaker_canceled(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestBreaker(t, WithConsecutiveFailures(2), WithOpenTimeout(time.Second))
+
+	ctx := t.Context()
+
+	cancelFn := func(_ context.Context) error {
+		return context.Canceled
+	}
+
+	// closed: a canceled call does not reset the consecutive failures
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.ErrorIs(t, cb.Execute(ctx, cancelFn), context.Canceled)
+	require.Equal(t, StateClosed, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateOpen, cb.State())
+
+	// half-open: a canceled probe does not close the breaker and releases its slot
+	clock.Add(time.Second)
+	require.Equal(t, StateHalfOpen, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, cancelFn), context.Canceled)
+	require.Equal(t, StateHalfOpen, cb.State())
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+	require.Equal(t, StateOpen, cb.State())
+
+	clock.Add(time.Second)
+	require.ErrorIs(t, cb.Execute(ctx, cancelFn), context.Canceled)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_panic(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestBreaker(t, WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+
+	ctx := t.Context()
+
+	panicFn := func(_ context.Context) error {
+		panic("test panic")
+	}
+
+	// closed: a panic is recorded as a failure and propagated
+	require.PanicsWithValue(t, "test panic", func() { _ = cb.Execute(ctx, panicFn) })
+	require.Equal(t, StateOpen, cb.State())
+
+	// half-open: a panicking probe reopens the breaker and releases its slot
+	clock.Add(time.Second)
+	require.Equal(t, StateHalfOpen, cb.State())
+	require.PanicsWithValue(t, "test panic", func() { _ = cb.Execute(ctx, panicFn) })
+	require.Equal(t, StateOpen, cb.State())
+
+	clock.Add(time.Second)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_concurrency(t *testing.T) {
+	t.Parallel()
+
+	cb, err := New("test", WithConsecutiveFailures(10), WithFailureRate(0.9, 10), WithOpenTimeout(time.Millisecond))
+	require.NoError(t, err)
+
+	ctx := t.Context()
+
+	var wg sync.WaitGroup
+
+	for i := range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 50 {
+				if (i+j)%2 == 0 {
+					_ = cb.Execute(ctx, failFn)
+					continue
+				}
+
+				_ = cb.Execute(ctx, okFn)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	_ = cb.State()
+}
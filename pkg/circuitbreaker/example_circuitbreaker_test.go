@@ -0,0 +1,40 @@
+package circuitbreaker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/circuitbreaker"
+)
+
+func ExampleCircuitBreaker_Execute() {
+	cb, err := circuitbreaker.New(
+		"example",
+		circuitbreaker.WithConsecutiveFailures(2),
+		circuitbreaker.WithOpenTimeout(1*time.Minute),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// example function that always fails.
+	task := func(_ context.Context) error {
+		return errors.New("ERROR")
+	}
+
+	for range 3 {
+		err = cb.Execute(context.TODO(), task)
+		fmt.Println(err)
+	}
+
+	fmt.Println(cb.State())
+
+	// Output:
+	// ERROR
+	// ERROR
+	// circuit breaker is open
+	// open
+}
@@ -0,0 +1,72 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Vonage/gosrvlib/pkg/httpretrier"
+)
+
+// IsHTTPFailureFn is the signature of the function used to decide if an HTTP response is a failure.
+type IsHTTPFailureFn func(r *http.Response, err error) bool
+
+// DefaultIsHTTPFailure is the default function to check if an HTTP response is a failure:
+// transport errors (except the canceled requests) and 5xx status codes.
+func DefaultIsHTTPFailure(r *http.Response, err error) bool {
+	if err != nil {
+		return DefaultIsFailure(err)
+	}
+
+	return r.StatusCode >= http.StatusInternalServerError
+}
+
+// RoundTripper returns an http.RoundTripper guarded by the circuit breaker,
+// to be used with github.com/Vonage/gosrvlib/pkg/httpclient.WithRoundTripper.
+// When the breaker is open the requests fail with an error wrapping ErrOpen.
+// The responses are classified with DefaultIsHTTPFailure unless a custom function is specified.
+func (cb *CircuitBreaker) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return cb.RoundTripperWithFailureFn(next, DefaultIsHTTPFailure)
+}
+
+// RoundTripperWithFailureFn is like RoundTripper but with a custom function to classify the responses.
+func (cb *CircuitBreaker) RoundTripperWithFailureFn(next http.RoundTripper, isFailureFn IsHTTPFailureFn) http.RoundTripper {
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		ctx := r.Context()
+
+		gen, err := cb.allow(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// a panicking call is recorded as a failure before the panic propagates
+		out := outcomeFailure
+
+		defer func() {
+			cb.record(ctx, gen, out)
+		}()
+
+		resp, err := next.RoundTrip(r)
+
+		out = callOutcome(isFailureFn(resp, err), err)
+
+		return resp, err //nolint:wrapcheck
+	})
+}
+
+// HTTPRetryIf wraps a github.com/Vonage/gosrvlib/pkg/httpretrier retry function
+// to stop retrying when the circuit breaker is open.
+func HTTPRetryIf(fn httpretrier.RetryIfFn) httpretrier.RetryIfFn {
+	return func(r *http.Response, err error) bool {
+		if errors.Is(err, ErrOpen) {
+			return false
+		}
+
+		return fn(r, err)
+	}
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
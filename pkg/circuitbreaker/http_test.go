@@ -0,0 +1,154 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpclient"
+	"github.com/Vonage/gosrvlib/pkg/httpretrier"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultIsHTTPFailure(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, DefaultIsHTTPFailure(nil, errTest))
+	require.False(t, DefaultIsHTTPFailure(nil, context.Canceled))
+	require.True(t, DefaultIsHTTPFailure(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	require.False(t, DefaultIsHTTPFailure(&http.Response{StatusCode: http.StatusNotFound}, nil))
+}
+
+func TestCircuitBreaker_RoundTripper(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cb, err := New("test", WithConsecutiveFailures(2), WithOpenTimeout(time.Hour))
+	require.NoError(t, err)
+
+	client := httpclient.New(httpclient.WithRoundTripper(cb.RoundTripper))
+
+	do := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+
+		return client.Do(req)
+	}
+
+	for range 2 {
+		resp, err := do()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	require.Equal(t, StateOpen, cb.State())
+
+	resp, err := do() //nolint:bodyclose
+	require.ErrorIs(t, err, ErrOpen)
+	require.Nil(t, resp)
+	require.Equal(t, 2, calls)
+}
+
+func TestCircuitBreaker_RoundTripperWithFailureFn(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cb, err := New("test", WithConsecutiveFailures(1))
+	require.NoError(t, err)
+
+	isFailure := func(r *http.Response, err error) bool {
+		return err != nil || r.StatusCode == http.StatusTooManyRequests
+	}
+
+	client := &http.Client{Transport: cb.RoundTripperWithFailureFn(http.DefaultTransport, isFailure)}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreaker_RoundTripper_canceled(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestBreaker(t, WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+
+	ctx := t.Context()
+
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+
+	clock.Add(time.Second)
+
+	next := roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+		return nil, context.Canceled
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	// the canceled probe neither closes nor opens the breaker
+	resp, err := cb.RoundTripper(next).RoundTrip(req) //nolint:bodyclose
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, resp)
+	require.Equal(t, StateHalfOpen, cb.State())
+
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_RoundTripper_panic(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestBreaker(t, WithConsecutiveFailures(1), WithOpenTimeout(time.Second))
+
+	ctx := t.Context()
+
+	require.ErrorIs(t, cb.Execute(ctx, failFn), errTest)
+
+	clock.Add(time.Second)
+
+	next := roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+		panic("test panic")
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	// the panicking probe is recorded as a failure and reopens the breaker
+	require.PanicsWithValue(t, "test panic", func() { _, _ = cb.RoundTripper(next).RoundTrip(req) }) //nolint:bodyclose
+	require.Equal(t, StateOpen, cb.State())
+
+	clock.Add(time.Second)
+	require.NoError(t, cb.Execute(ctx, okFn))
+	require.Equal(t, StateClosed, cb.State())
+}
+
+func TestHTTPRetryIf(t *testing.T) {
+	t.Parallel()
+
+	fn := HTTPRetryIf(httpretrier.RetryIfForReadRequests)
+
+	require.False(t, fn(nil, ErrOpen))
+	require.True(t, fn(nil, errors.New("error")))
+	require.True(t, fn(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	require.False(t, fn(&http.Response{StatusCode: http.StatusOK}, nil))
+}
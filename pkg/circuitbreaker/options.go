@@ -0,0 +1,127 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+)
+
+// Option is the interface that allows to set the options.
+type Option func(cb *CircuitBreaker) error
+
+// WithConsecutiveFailures sets the number of consecutive failures that trips the breaker
+// (default DefaultConsecutiveFailures). Zero disables this threshold.
+func WithConsecutiveFailures(n uint) Option {
+	return func(cb *CircuitBreaker) error {
+		cb.consecutiveFailures = n
+		return nil
+	}
+}
+
+// WithFailureRate trips the breaker when the ratio of failed calls in the
+// rolling window reaches the rate (0 < rate <= 1), with at least minCalls
+// calls in the window. The failure rate threshold is disabled by default.
+func WithFailureRate(rate float64, minCalls uint) Option {
+	return func(cb *CircuitBreaker) error {
+		if rate <= 0 || rate > 1 {
+			return errors.New("the failure rate must be greater than 0 and at most 1")
+		}
+
+		if minCalls < 1 {
+			return errors.New("the minimum number of calls must be at least 1")
+		}
+
+		cb.failureRate = rate
+		cb.minCalls = minCalls
+
+		return nil
+	}
+}
+
+// WithWindow sets the duration of the rolling window used to compute the
+// failure rate, and the number of buckets it is divided into
+// (default DefaultWindow and DefaultWindowBuckets).
+// The old results are discarded one bucket at a time.
+func WithWindow(size time.Duration, buckets uint) Option {
+	return func(cb *CircuitBreaker) error {
+		if buckets < 1 {
+			return errors.New("the number of window buckets must be at least 1")
+		}
+
+		if size < time.Duration(buckets) {
+			return errors.New("the window size is too small")
+		}
+
+		cb.window = newWindow(size, buckets)
+
+		return nil
+	}
+}
+
+// WithOpenTimeout sets the time the breaker stays open before allowing the
+// half-open probe calls (default DefaultOpenTimeout).
+func WithOpenTimeout(timeout time.Duration) Option {
+	return func(cb *CircuitBreaker) error {
+		if timeout <= 0 {
+			return errors.New("the open timeout must be greater than zero")
+		}
+
+		cb.openTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithHalfOpenMaxCalls sets the number of probe calls allowed in the half-open
+// state (default DefaultHalfOpenMaxCalls). The breaker closes when all of them
+// succeed.
+func WithHalfOpenMaxCalls(n uint) Option {
+	return func(cb *CircuitBreaker) error {
+		if n < 1 {
+			return errors.New("the number of half-open calls must be at least 1")
+		}
+
+		cb.halfOpenMaxCalls = n
+
+		return nil
+	}
+}
+
+// WithIsFailureFn sets the function used to decide if an error is a failure
+// (default DefaultIsFailure).
+func WithIsFailureFn(fn IsFailureFn) Option {
+	return func(cb *CircuitBreaker) error {
+		if fn == nil {
+			return errors.New("the isFailure function is required")
+		}
+
+		cb.isFailureFn = fn
+
+		return nil
+	}
+}
+
+// WithStateChangeFn sets a function called on each state change.
+// The function is called synchronously and must not use the circuit breaker.
+func WithStateChangeFn(fn StateChangeFn) Option {
+	return func(cb *CircuitBreaker) error {
+		cb.stateChangeFn = fn
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics client used to count the state changes and the rejected calls.
+// The events are counted with IncErrorCounter("<name>", "circuitbreaker", "<code>"),
+// where the code is the new state ("open", "half-open", "closed") or "rejected".
+func WithMetrics(m metrics.Client) Option {
+	return func(cb *CircuitBreaker) error {
+		if m == nil {
+			return errors.New("the metrics client is required")
+		}
+
+		cb.metric = m
+
+		return nil
+	}
+}
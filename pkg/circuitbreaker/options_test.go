@@ -0,0 +1,113 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	require.NoError(t, WithConsecutiveFailures(7)(cb))
+	require.Equal(t, uint(7), cb.consecutiveFailures)
+}
+
+func TestWithFailureRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rate     float64
+		minCalls uint
+		wantErr  bool
+	}{
+		{name: "valid", rate: 0.5, minCalls: 10},
+		{name: "max rate", rate: 1, minCalls: 1},
+		{name: "zero rate", rate: 0, minCalls: 10, wantErr: true},
+		{name: "rate too high", rate: 1.1, minCalls: 10, wantErr: true},
+		{name: "zero calls", rate: 0.5, minCalls: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cb := &CircuitBreaker{}
+
+			err := WithFailureRate(tt.rate, tt.minCalls)(cb)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.InDelta(t, tt.rate, cb.failureRate, 0)
+			require.Equal(t, tt.minCalls, cb.minCalls)
+		})
+	}
+}
+
+func TestWithWindow(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	require.NoError(t, WithWindow(time.Minute, 6)(cb))
+	require.Equal(t, 10*time.Second, cb.window.bucketSize)
+	require.Len(t, cb.window.buckets, 6)
+
+	require.Error(t, WithWindow(time.Minute, 0)(cb))
+	require.Error(t, WithWindow(2, 3)(cb))
+}
+
+func TestWithOpenTimeout(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	require.NoError(t, WithOpenTimeout(time.Second)(cb))
+	require.Equal(t, time.Second, cb.openTimeout)
+
+	require.Error(t, WithOpenTimeout(0)(cb))
+}
+
+func TestWithHalfOpenMaxCalls(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	require.NoError(t, WithHalfOpenMaxCalls(3)(cb))
+	require.Equal(t, uint(3), cb.halfOpenMaxCalls)
+
+	require.Error(t, WithHalfOpenMaxCalls(0)(cb))
+}
+
+func TestWithIsFailureFn(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	require.NoError(t, WithIsFailureFn(DefaultIsFailure)(cb))
+	require.NotNil(t, cb.isFailureFn)
+
+	require.Error(t, WithIsFailureFn(nil)(cb))
+}
+
+func TestWithStateChangeFn(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	require.NoError(t, WithStateChangeFn(func(_ string, _, _ State) {})(cb))
+	require.NotNil(t, cb.stateChangeFn)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	cb := &CircuitBreaker{}
+	m := &metrics.Default{}
+	require.NoError(t, WithMetrics(m)(cb))
+	require.Equal(t, m, cb.metric)
+
+	require.Error(t, WithMetrics(nil)(cb))
+}
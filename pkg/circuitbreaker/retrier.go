@@ -0,0 +1,28 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+)
+
+// Task returns a github.com/Vonage/gosrvlib/pkg/retrier task guarded by the circuit breaker.
+// When the breaker is open the task fails with ErrOpen without calling the original task.
+func (cb *CircuitBreaker) Task(task retrier.TaskFn) retrier.TaskFn {
+	return func(ctx context.Context) error {
+		return cb.Execute(ctx, task)
+	}
+}
+
+// RetryIf wraps a github.com/Vonage/gosrvlib/pkg/retrier retry function
+// to stop retrying when the circuit breaker is open.
+func RetryIf(fn retrier.RetryIfFn) retrier.RetryIfFn {
+	return func(err error) bool {
+		if errors.Is(err, ErrOpen) {
+			return false
+		}
+
+		return fn(err)
+	}
+}
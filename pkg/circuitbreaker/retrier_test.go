@@ -0,0 +1,45 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_Task(t *testing.T) {
+	t.Parallel()
+
+	cb, err := New("test", WithConsecutiveFailures(2), WithOpenTimeout(time.Hour))
+	require.NoError(t, err)
+
+	r, err := retrier.New(
+		retrier.WithAttempts(10),
+		retrier.WithDelay(time.Millisecond),
+		retrier.WithRetryIfFn(RetryIf(retrier.DefaultRetryIf)),
+	)
+	require.NoError(t, err)
+
+	var calls int
+
+	err = r.Run(t.Context(), cb.Task(func(_ context.Context) error {
+		calls++
+		return errTest
+	}))
+
+	require.ErrorIs(t, err, ErrOpen)
+	require.Equal(t, 2, calls)
+}
+
+func TestRetryIf(t *testing.T) {
+	t.Parallel()
+
+	fn := RetryIf(retrier.DefaultRetryIf)
+
+	require.False(t, fn(ErrOpen))
+	require.False(t, fn(nil))
+	require.True(t, fn(errors.New("error")))
+}
@@ -0,0 +1,66 @@
+package circuitbreaker
+
+import (
+	"time"
+)
+
+// window counts the call results in a rolling time window divided in buckets.
+type window struct {
+	bucketSize time.Duration
+	buckets    []bucket
+}
+
+type bucket struct {
+	epoch    int64 // sequential number of the bucket time slot
+	total    uint64
+	failures uint64
+}
+
+func newWindow(size time.Duration, buckets uint) *window {
+	return &window{
+		bucketSize: size / time.Duration(buckets),
+		buckets:    make([]bucket, buckets),
+	}
+}
+
+// add records a call result.
+func (w *window) add(now time.Time, failed bool) {
+	epoch := w.epoch(now)
+	b := &w.buckets[epoch%int64(len(w.buckets))]
+
+	if b.epoch != epoch {
+		*b = bucket{epoch: epoch}
+	}
+
+	b.total++
+
+	if failed {
+		b.failures++
+	}
+}
+
+// counts returns the total number of calls and failures in the window.
+func (w *window) counts(now time.Time) (uint64, uint64) {
+	epoch := w.epoch(now)
+	oldest := epoch - int64(len(w.buckets))
+
+	var total, failures uint64
+
+	for _, b := range w.buckets {
+		if b.epoch > oldest && b.epoch <= epoch {
+			total += b.total
+			failures += b.failures
+		}
+	}
+
+	return total, failures
+}
+
+// reset clears all the buckets.
+func (w *window) reset() {
+	clear(w.buckets)
+}
+
+func (w *window) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(w.bucketSize)
+}
@@ -0,0 +1,43 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_window(t *testing.T) {
+	t.Parallel()
+
+	w := newWindow(3*time.Second, 3)
+	now := time.Unix(1000, 0)
+
+	w.add(now, true)
+	w.add(now, false)
+	w.add(now.Add(time.Second), true)
+
+	total, failures := w.counts(now.Add(time.Second))
+	require.Equal(t, uint64(3), total)
+	require.Equal(t, uint64(2), failures)
+
+	// the first bucket is out of the window
+	total, failures = w.counts(now.Add(3 * time.Second))
+	require.Equal(t, uint64(1), total)
+	require.Equal(t, uint64(1), failures)
+
+	// the bucket slot is reused
+	w.add(now.Add(3*time.Second), false)
+
+	total, failures = w.counts(now.Add(3 * time.Second))
+	require.Equal(t, uint64(2), total)
+	require.Equal(t, uint64(1), failures)
+
+	total, _ = w.counts(now.Add(time.Minute))
+	require.Equal(t, uint64(0), total)
+
+	w.reset()
+
+	total, _ = w.counts(now.Add(3 * time.Second))
+	require.Equal(t, uint64(0), total)
+}
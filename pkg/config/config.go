@@ -32,6 +32,15 @@ highest):
 
  2. The program attempts to load the local "config.json" configuration file, and
     as soon as one is found, it overwrites the default values previously set.
+    The file format is detected from the extension, so "config.yaml",
+    "config.yml", "config.toml", "config.hcl", "config.env" (dotenv) and the
+    other formats supported by Viper can be used instead.
+
+    The optional overlay files "config.<environment>.<ext>" and
+    "config.local.<ext>" found in the same directory are then merged in this
+    order, each one overwriting the values of the previous ones. The
+    environment name (e.g. "production") is set with the WithEnvironment option
+    or the "MYPROG_ENVIRONMENT" environment variable.
 
     The configuration file is searched in the following ordered directories
     based on the Linux Filesystem Hierarchy Standard (FHS):
@@ -42,12 +51,17 @@ highest):
 
     - /etc/myprog/
 
- 3. The program attempts to load the environmental variables that define the
-    remote configuration system. If found, it overwrites the corresponding
-    configuration parameters:
+ 3. The program attempts to load the environment variables. Each leaf
+    configuration parameter can be overwritten by the environment variable
+    named after the uppercase program prefix and the parameter key, with the
+    dots replaced by underscores:
 
     - [ENVIRONMENT VARIABLE NAME] → [CONFIGURATION PARAMETER NAME]
 
+    - MYPROG_LOG_LEVEL → log.level
+
+    - MYPROG_SERVERS_PUBLIC_ADDRESS → servers.public.address
+
     - MYPROG_REMOTECONFIGPROVIDER → remoteConfigProvider
 
     - MYPROG_REMOTECONFIGENDPOINT → remoteConfigEndpoint
@@ -58,6 +72,11 @@ highest):
 
     - MYPROG_REMOTECONFIGDATA → remoteConfigData
 
+    Slices can be set as comma-separated values (e.g. "a,b") or JSON arrays,
+    and maps as comma-separated "key=value" pairs or JSON objects. The full
+    list of the environment variables is returned by EnvVars and printed by
+    WriteEnvVars.
+
  4. If the "remoteConfigProvider" parameter is not empty, the program attempts
     to load the configuration data from the specified source. This can be any
     remote source supported by the Viper library (e.g., Consul, etcd, etcd3,
@@ -70,6 +89,52 @@ highest):
 
  6. The configuration parameters are validated via the Validate() function.
 
+The WithDebugDump option writes the list of the final configuration keys, each
+with the source that supplied its value, without the values.
+
+# Secret References:
+
+After loading, any exported string parameter containing one of the following
+references is replaced with the secret value, so credentials are never stored
+in the configuration sources:
+
+  - "secret://aws/<id>#<jsonkey>": the "jsonkey" value of the JSON secret "id"
+    stored in AWS Secrets Manager (or the whole secret if "#<jsonkey>" is
+    omitted), retrieved via the cache set with WithAWSSecretCache
+    (see github.com/Vonage/gosrvlib/pkg/awssecretcache);
+
+  - "file:///run/secrets/db": the content of the local file
+    "/run/secrets/db", without the trailing newlines;
+
+  - "env://NAME": the value of the "NAME" environment variable.
+
+The file and env references are only resolved when the whole value is the
+reference: the file URLs with a host (e.g. "file://localhost/var/data"), a
+query or a fragment, and the values containing a reference within other text,
+are left unchanged. The errors report the configuration key of the invalid
+reference.
+
+The secrets are resolved again on each reload (see Watcher). The resolved
+values are never logged or included in the error messages.
+
+# Documentation:
+
+The JSONSchema and MarkdownReference functions reflect the configuration
+struct (including BaseConfig and LogConfig) into a JSON Schema and a markdown
+reference, using the "mapstructure", "validate" and optional "description"
+struct tags, and the default values set by SetDefaults. This allows generating
+files like config.schema.json instead of maintaining them by hand.
+
+# Live Reload:
+
+The Watcher type holds the current typed configuration and can reload it at
+runtime, on SIGHUP (see bootstrap.WithReloadFunc), when the local
+configuration file changes (WithFileWatch), or periodically to track a remote
+provider (WithPollInterval). Each reload reads again all the configuration
+sources and runs the Validate() function: invalid updates are rejected and the
+current configuration is kept active, while valid changes are delivered to the
+functions registered with Watcher.Subscribe.
+
 # Example:
 
   - An implementation example of this configuration package can be found in
@@ -90,8 +155,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/go-viper/encoding/hcl"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	_ "github.com/spf13/viper/remote" //nolint:revive,nolintlint
@@ -99,9 +167,11 @@ import (
 
 // General constants.
 const (
-	defaultConfigName = "config" // Base name of the file containing the local configuration data.
-	defaultConfigType = "json"   // Type and file extension of the file containing the local configuration data.
-	providerEnvVar    = "envvar" // Provider name for the environment variable configuration source.
+	defaultConfigName = "config"      // Base name of the file containing the local configuration data.
+	defaultConfigType = "json"        // Type of the remote configuration data.
+	localConfigName   = "local"       // Name of the local overlay configuration file (e.g. config.local.json).
+	providerEnvVar    = "envvar"      // Provider name for the environment variable configuration source.
+	envVarEnvironment = "ENVIRONMENT" // Environment variable suffix containing the environment name.
 )
 
 // Remote configuration key names.
@@ -164,10 +234,13 @@ type Viper interface {
 	AutomaticEnv()
 	BindEnv(input ...string) error
 	BindPFlag(key string, flag *pflag.Flag) error
+	ConfigFileUsed() string
 	Get(key string) any
+	MergeInConfig() error
 	ReadConfig(in io.Reader) error
 	ReadInConfig() error
 	ReadRemoteConfig() error
+	SetConfigFile(in string)
 	SetConfigName(in string)
 	SetConfigType(in string)
 	SetDefault(key string, value any)
@@ -178,57 +251,72 @@ type Viper interface {
 // BaseConfig contains the default configuration options to be used in the application config struct.
 type BaseConfig struct {
 	// Log configuration.
-	Log LogConfig `mapstructure:"log" validate:"required"`
+	Log LogConfig `mapstructure:"log" validate:"required" description:"Logger settings"`
 
 	// ShutdownTimeout is the time in seconds to wait for graceful shutdown.
-	ShutdownTimeout int64 `mapstructure:"shutdown_timeout" validate:"omitempty,min=1,max=3600"`
+	ShutdownTimeout int64 `mapstructure:"shutdown_timeout" validate:"omitempty,min=1,max=3600" description:"Time in seconds to wait on exit for a graceful shutdown"`
 }
 
 // LogConfig contains the configuration for the application logger.
 type LogConfig struct {
 	// Level is the standard syslog level: EMERGENCY, ALERT, CRITICAL, ERROR, WARNING, NOTICE, INFO, DEBUG.
-	Level string `mapstructure:"level" validate:"required,oneof=EMERGENCY ALERT CRITICAL ERROR WARNING NOTICE INFO DEBUG"`
+	Level string `mapstructure:"level" validate:"required,oneof=EMERGENCY ALERT CRITICAL ERROR WARNING NOTICE INFO DEBUG" description:"Standard syslog log level"`
 
 	// Format is the log output format: CONSOLE, JSON.
-	Format string `mapstructure:"format" validate:"required,oneof=CONSOLE JSON"`
+	Format string `mapstructure:"format" validate:"required,oneof=CONSOLE JSON" description:"Log output format"`
 
 	// Network is the optional network protocol used to send logs via syslog: udp, tcp.
-	Network string `mapstructure:"network" validate:"omitempty,oneof=udp tcp"`
+	Network string `mapstructure:"network" validate:"omitempty,oneof=udp tcp" description:"Network protocol used to send logs via syslog"`
 
 	// Address is the optional remote syslog network address: (ip:port) or just (:port).
-	Address string `mapstructure:"address" validate:"omitempty,hostname_port"`
+	Address string `mapstructure:"address" validate:"omitempty,hostname_port" description:"Remote syslog network address (ip:port) or just (:port)"`
 }
 
 // remoteSourceConfig contains the default remote source options to be used in the application config struct.
 type remoteSourceConfig struct {
 	// Provider is the optional external configuration source: consul, envvar, etcd, etcd3, firestore, nats.
 	// When envvar is set the data should be set in the Data field.
-	Provider string `mapstructure:"remoteConfigProvider" validate:"omitempty,oneof=consul envvar etcd etcd3 firestore nats"`
+	Provider string `mapstructure:"remoteConfigProvider" validate:"omitempty,oneof=consul envvar etcd etcd3 firestore nats" description:"External configuration source"`
 
 	// Endpoint is the remote configuration URL (ip:port).
-	Endpoint string `mapstructure:"remoteConfigEndpoint" validate:"omitempty,url|hostname_port"`
+	Endpoint string `mapstructure:"remoteConfigEndpoint" validate:"omitempty,url|hostname_port" description:"Remote configuration URL (ip:port)"`
 
 	// Path is the remote configuration path where to search fo the configuration file ("/cli/program").
-	Path string `mapstructure:"remoteConfigPath" validate:"omitempty,file"`
+	Path string `mapstructure:"remoteConfigPath" validate:"omitempty,file" description:"Remote configuration path where to search for the configuration file"`
 
 	// SecretKeyring is the path to the openpgp secret keyring used to decript the remote configuration data (e.g.: "/etc/program/configkey.gpg")
-	SecretKeyring string `mapstructure:"remoteConfigSecretKeyring" validate:"omitempty,file"`
+	SecretKeyring string `mapstructure:"remoteConfigSecretKeyring" validate:"omitempty,file" description:"Path to the openpgp secret keyring used to decrypt the remote configuration data"`
 
 	// Data is the base64 encoded JSON configuration data to be used with the "envvar" provider.
-	Data string `mapstructure:"remoteConfigData" validate:"required_if=Provider envar,omitempty,base64"`
+	Data string `mapstructure:"remoteConfigData" validate:"required_if=Provider envar,omitempty,base64" description:"Base64 encoded JSON configuration data to be used with the envvar provider"`
 }
 
 // Load populates the configuration parameters.
-func Load(cmdName, configDir, envPrefix string, cfg Configuration) error {
-	localViper := viper.New()
-	remoteViper := viper.New()
+func Load(cmdName, configDir, envPrefix string, cfg Configuration, opts ...Option) error {
+	localViper := newViper()
+	remoteViper := newViper()
 
-	return loadConfig(localViper, remoteViper, cmdName, configDir, envPrefix, cfg)
+	return loadConfig(localViper, remoteViper, cmdName, configDir, envPrefix, cfg, opts...)
+}
+
+// newViper returns a new Viper instance supporting also the HCL configuration format,
+// and the slices, maps and structs set as strings by the environment variables.
+func newViper() *viper.Viper {
+	codecs := viper.NewCodecRegistry()
+	_ = codecs.RegisterCodec("hcl", hcl.Codec{})    // never returns an error
+	_ = codecs.RegisterCodec("tfvars", hcl.Codec{}) // never returns an error
+
+	return viper.NewWithOptions(
+		viper.WithCodecRegistry(codecs),
+		viper.WithDecodeHook(envValueDecodeHook()),
+	)
 }
 
 // loadConfig loads the configuration.
-func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix string, cfg Configuration) error {
-	remoteSourceCfg, err := loadLocalConfig(localViper, cmdName, configDir, envPrefix, cfg)
+func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix string, cfg Configuration, opts ...Option) error {
+	lo := newLoadOptions(opts)
+
+	remoteSourceCfg, err := loadLocalConfig(localViper, cmdName, configDir, envPrefix, cfg, lo)
 	if err != nil {
 		return fmt.Errorf("failed loading local configuration: %w", err)
 	}
@@ -238,6 +326,18 @@ func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix str
 		return fmt.Errorf("failed loading remote configuration: %w", err)
 	}
 
+	err = resolveSecrets(lo.ctx, lo.awsCache, cfg)
+	if err != nil {
+		return fmt.Errorf("failed resolving secret references: %w", err)
+	}
+
+	if lo.debugDump != nil {
+		err = dumpKeySources(lo.debugDump, localViper, remoteViper, remoteSourceCfg, envPrefix, lo.files)
+		if err != nil {
+			return fmt.Errorf("failed dumping configuration sources: %w", err)
+		}
+	}
+
 	err = cfg.Validate()
 	if err != nil {
 		return fmt.Errorf("failed validating configuration: %w", err)
@@ -247,47 +347,23 @@ func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix str
 }
 
 // loadLocalConfig returns the local configuration parameters.
-func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configuration) (*remoteSourceConfig, error) {
-	// set default remote configuration values
-	v.SetDefault(keyRemoteConfigProvider, defaultRemoteConfigProvider)
-	v.SetDefault(keyRemoteConfigEndpoint, defaultRemoteConfigEndpoint)
-	v.SetDefault(keyRemoteConfigPath, defaultRemoteConfigPath)
-	v.SetDefault(keyRemoteConfigSecretKeyring, defaultRemoteConfigSecretKeyring)
-
-	// set default logging configuration values
-	v.SetDefault(keyLogFormat, defaultLogFormat)
-	v.SetDefault(keyLogLevel, defaultLogLevel)
-	v.SetDefault(keyLogAddress, defaultLogAddress)
-	v.SetDefault(keyLogNetwork, defaultLogNetwork)
+func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configuration, lo *loadOptions) (*remoteSourceConfig, error) {
+	// set the default values of the common parameters
+	setDefaultValues(v)
 
-	// set default config name and type
+	// set default config name, the type is detected from the file extension
 	v.SetConfigName(defaultConfigName)
-	v.SetConfigType(defaultConfigType)
 
 	// add default search paths
 	configureSearchPath(v, cmdName, configDir)
 
-	// set application defaults
-	v.SetDefault(keyShutdownTimeout, defaultShutdownTimeout)
-
 	// set defaults from application configuration
 	cfg.SetDefaults(v)
 
-	// support environment variables for the remote configuration
+	// support environment variables for all the configuration parameters
 	v.AutomaticEnv()
 	v.SetEnvPrefix(strings.ReplaceAll(envPrefix, "-", "_")) // will be uppercased automatically
-
-	envVar := []string{
-		keyRemoteConfigProvider,
-		keyRemoteConfigEndpoint,
-		keyRemoteConfigPath,
-		keyRemoteConfigSecretKeyring,
-		keyRemoteConfigData,
-	}
-
-	for _, ev := range envVar {
-		_ = v.BindEnv(ev) // we ignore the error because we are always passing an argument value
-	}
+	bindEnvVars(v, envPrefix, cfg)
 
 	// Find and read the local configuration file (if any)
 	err := v.ReadInConfig()
@@ -295,6 +371,11 @@ func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configur
 		return nil, fmt.Errorf("failed reading in config: %w", err)
 	}
 
+	err = mergeOverlayConfig(v, envPrefix, lo)
+	if err != nil {
+		return nil, err
+	}
+
 	var rsCfg remoteSourceConfig
 
 	err = v.Unmarshal(&rsCfg)
@@ -305,6 +386,24 @@ func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configur
 	return &rsCfg, nil
 }
 
+// setDefaultValues sets the default values of the parameters defined by this package.
+func setDefaultValues(v Viper) {
+	// set default remote configuration values
+	v.SetDefault(keyRemoteConfigProvider, defaultRemoteConfigProvider)
+	v.SetDefault(keyRemoteConfigEndpoint, defaultRemoteConfigEndpoint)
+	v.SetDefault(keyRemoteConfigPath, defaultRemoteConfigPath)
+	v.SetDefault(keyRemoteConfigSecretKeyring, defaultRemoteConfigSecretKeyring)
+
+	// set default logging configuration values
+	v.SetDefault(keyLogFormat, defaultLogFormat)
+	v.SetDefault(keyLogLevel, defaultLogLevel)
+	v.SetDefault(keyLogAddress, defaultLogAddress)
+	v.SetDefault(keyLogNetwork, defaultLogNetwork)
+
+	// set application defaults
+	v.SetDefault(keyShutdownTimeout, defaultShutdownTimeout)
+}
+
 // loadRemoteConfig returns the remote configuration parameters.
 func loadRemoteConfig(lv Viper, rv Viper, rs *remoteSourceConfig, envPrefix string, cfg Configuration) error {
 	for _, k := range lv.AllKeys() {
@@ -376,6 +475,73 @@ func loadFromRemoteSource(v Viper, rc *remoteSourceConfig, envPrefix string) err
 	return v.ReadRemoteConfig() //nolint:wrapcheck
 }
 
+// mergeOverlayConfig merges in order the optional "config.<environment>.<ext>"
+// and "config.local.<ext>" files found in the same directory of the main configuration file.
+func mergeOverlayConfig(v Viper, envPrefix string, lo *loadOptions) error {
+	baseFile := v.ConfigFileUsed()
+	if baseFile == "" {
+		return nil
+	}
+
+	lo.files = []string{baseFile}
+
+	env := lo.environment
+	if env == "" {
+		env = os.Getenv(envVarName(envPrefix, envVarEnvironment))
+	}
+
+	var names []string
+
+	if env != "" && env != localConfigName && !strings.ContainsAny(env, `/\`) {
+		names = append(names, env)
+	}
+
+	names = append(names, localConfigName)
+
+	dir := filepath.Dir(baseFile)
+
+	for _, name := range names {
+		file := findConfigFile(dir, defaultConfigName+"."+name)
+		if file == "" {
+			continue
+		}
+
+		v.SetConfigFile(file)
+
+		err := v.MergeInConfig()
+		if err != nil {
+			return fmt.Errorf("failed merging config file %s: %w", file, err)
+		}
+
+		lo.files = append(lo.files, file)
+	}
+
+	// restore the main configuration file
+	v.SetConfigFile(baseFile)
+
+	return nil
+}
+
+// findConfigFile returns the path of the first existing file with the given base name
+// and one of the supported extensions, or an empty string.
+func findConfigFile(dir, name string) string {
+	for _, ext := range viper.SupportedExts {
+		file := filepath.Join(dir, name+"."+ext)
+
+		info, err := os.Stat(file)
+		if err == nil && !info.IsDir() {
+			return file
+		}
+	}
+
+	return ""
+}
+
+// envVarName returns the name of the environment variable for the given key.
+func envVarName(envPrefix, key string) string {
+	return strings.ToUpper(strings.ReplaceAll(envPrefix, "-", "_") + "_" + strings.ReplaceAll(key, ".", "_"))
+}
+
 // configureSearchPath sets the directory paths to search in order for a local configuration file.
 func configureSearchPath(v Viper, cmdName, configDir string) {
 	var configSearchPath []string
@@ -105,7 +105,7 @@ func mockViper(ctrl *gomock.Controller) *MockViper {
 	mock := NewMockViper(ctrl)
 
 	mock.EXPECT().SetConfigName(defaultConfigName)
-	mock.EXPECT().SetConfigType(defaultConfigType)
+	mock.EXPECT().ConfigFileUsed().Return("").AnyTimes()
 
 	mock.EXPECT().SetDefault(keyRemoteConfigProvider, defaultRemoteConfigProvider)
 	mock.EXPECT().SetDefault(keyRemoteConfigEndpoint, defaultRemoteConfigEndpoint)
@@ -234,7 +234,7 @@ func Test_loadLocalConfig(t *testing.T) {
 
 			var testCfg testConfig
 
-			got, err := loadLocalConfig(v, "test_name", configDir, "test", &testCfg)
+			got, err := loadLocalConfig(v, "test_name", configDir, "test", &testCfg, &loadOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("loadLocalConfig() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1111,3 +1111,136 @@ func TestLoad(t *testing.T) {
 	err = Load("cmd", tmpConfigDir, "test", targetConfig)
 	require.NoError(t, err)
 }
+
+func TestLoad_formats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name:    "json",
+			file:    "config.json",
+			content: `{"log": {"level": "INFO"}, "string": "value", "int": 3}`,
+		},
+		{
+			name:    "yaml",
+			file:    "config.yaml",
+			content: "log:\n  level: INFO\nstring: value\nint: 3\n",
+		},
+		{
+			name:    "yml",
+			file:    "config.yml",
+			content: "log:\n  level: INFO\nstring: value\nint: 3\n",
+		},
+		{
+			name:    "toml",
+			file:    "config.toml",
+			content: "string = \"value\"\nint = 3\n[log]\nlevel = \"INFO\"\n",
+		},
+		{
+			name:    "hcl",
+			file:    "config.hcl",
+			content: "string = \"value\"\nint = 3\n\"log.level\" = \"INFO\"\n",
+		},
+		{
+			name:    "dotenv",
+			file:    "config.env",
+			content: "LOG.LEVEL=INFO\nSTRING=value\nINT=3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0o600))
+
+			cfg := &testConfig{}
+
+			err := Load("cmd", dir, "test", cfg)
+			require.NoError(t, err)
+			require.Equal(t, "INFO", cfg.Log.Level)
+			require.Equal(t, "value", cfg.String)
+			require.Equal(t, 3, cfg.Int)
+		})
+	}
+}
+
+func TestLoad_layers(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"config.json":            `{"log": {"level": "INFO"}, "string": "base", "int": 1, "int64": 1, "data": {"str": "base", "int": 1}}`,
+		"config.production.yaml": "string: production\nint: 2\ndata:\n  int: 2\n",
+		"config.staging.json":    `{"string": "staging"}`,
+		"config.local.toml":      "int = 3\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	cfg := &testConfig{}
+
+	err := Load("cmd", dir, "test", cfg, WithEnvironment("production"))
+	require.NoError(t, err)
+	require.Equal(t, "INFO", cfg.Log.Level)
+	require.Equal(t, "production", cfg.String)
+	require.Equal(t, 3, cfg.Int)
+	require.Equal(t, int64(1), cfg.Int64)
+	require.Equal(t, "base", cfg.Data.Str)
+	require.Equal(t, 2, cfg.Data.Int)
+
+	cfg = &testConfig{}
+
+	err = Load("cmd", dir, "test", cfg, WithEnvironment("../production"))
+	require.NoError(t, err)
+	require.Equal(t, "base", cfg.String)
+	require.Equal(t, 3, cfg.Int)
+}
+
+//nolint:paralleltest
+func TestLoad_layers_envVar(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"string": "base"}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.staging.json"), []byte(`{"string": "staging"}`), 0o600))
+
+	t.Setenv("LAYERTEST_ENVIRONMENT", "staging")
+
+	cfg := &testConfig{}
+
+	err := Load("cmd", dir, "layertest", cfg)
+	require.NoError(t, err)
+	require.Equal(t, "staging", cfg.String)
+}
+
+func TestLoad_layers_error(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"string": "base"}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.local.json"), []byte(`{`), 0o600))
+
+	err := Load("cmd", dir, "test", &testConfig{})
+	require.Error(t, err)
+}
+
+func Test_findConfigFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "config.local.json"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.local.yaml"), []byte(``), 0o600))
+
+	require.Equal(t, filepath.Join(dir, "config.local.yaml"), findConfigFile(dir, "config.local"))
+	require.Empty(t, findConfigFile(dir, "config.missing"))
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// EnvVar describes the environment variable that overrides a configuration parameter.
+type EnvVar struct {
+	// Name is the environment variable name (e.g. "MYPROG_SERVERS_PUBLIC_ADDRESS").
+	Name string
+
+	// Key is the configuration parameter key (e.g. "servers.public.address").
+	Key string
+
+	// Type is the human-readable type of the configuration parameter.
+	Type string
+}
+
+// EnvVars returns the environment variables that can override the leaf
+// parameters of the configuration type, sorted by name.
+//
+// The variable name is the uppercase envPrefix followed by the parameter key
+// with the dots replaced by underscores. Slices can be set as comma-separated
+// values or JSON arrays, and maps as "key=value" comma-separated pairs or JSON
+// objects.
+func EnvVars(envPrefix string, cfg Configuration) []EnvVar {
+	g := &schemaGenerator{defaults: newViper()}
+
+	root := g.object(reflect.TypeOf(cfg), "")
+	g.addProperties(root, reflect.TypeOf(remoteSourceConfig{}), "")
+
+	var vars []EnvVar
+
+	collectEnvVars(&vars, envPrefix, root, "")
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+
+	return vars
+}
+
+// WriteEnvVars writes the table of the environment variables that can override
+// the configuration parameters (see EnvVars).
+func WriteEnvVars(w io.Writer, envPrefix string, cfg Configuration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "ENVIRONMENT VARIABLE\tCONFIGURATION KEY\tTYPE")
+
+	for _, ev := range EnvVars(envPrefix, cfg) {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", ev.Name, ev.Key, ev.Type)
+	}
+
+	return tw.Flush() //nolint:wrapcheck
+}
+
+// bindEnvVars binds all the configuration leaf keys to their environment variables.
+func bindEnvVars(v Viper, envPrefix string, cfg Configuration) {
+	for _, ev := range EnvVars(envPrefix, cfg) {
+		_ = v.BindEnv(ev.Key, ev.Name) // we ignore the error because we are always passing an argument value
+	}
+}
+
+// collectEnvVars appends the environment variables of the schema leaves.
+func collectEnvVars(vars *[]EnvVar, envPrefix string, s *Schema, path string) {
+	for k, p := range s.Properties {
+		key := joinPath(path, k)
+
+		if p.Properties != nil {
+			collectEnvVars(vars, envPrefix, p, key)
+			continue
+		}
+
+		*vars = append(*vars, EnvVar{
+			Name: envVarName(envPrefix, key),
+			Key:  key,
+			Type: typeName(p),
+		})
+	}
+}
+
+// envValueDecodeHook returns the decode hook used to unmarshal the configuration.
+// In addition to the Viper default hooks, it decodes the string values
+// (e.g. from environment variables) into slices, maps and structs.
+func envValueDecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		stringToCompositeHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
+// stringToCompositeHookFunc decodes JSON strings into slices, maps and structs,
+// and "key=value" comma-separated pairs into maps.
+func stringToCompositeHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+
+		//nolint:exhaustive
+		switch to.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		default:
+			return data, nil
+		}
+
+		s := strings.TrimSpace(data.(string)) //nolint:forcetypeassert
+
+		if strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") {
+			var v any
+
+			err := json.Unmarshal([]byte(s), &v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON value: %w", err)
+			}
+
+			return v, nil
+		}
+
+		if to.Kind() != reflect.Map {
+			return data, nil
+		}
+
+		m := make(map[string]any)
+
+		for _, kv := range strings.Split(s, ",") {
+			if kv == "" {
+				continue
+			}
+
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, errors.New("invalid map value: expected comma-separated key=value pairs")
+			}
+
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+
+		return m, nil
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVars_untagged(t *testing.T) {
+	t.Parallel()
+
+	vars := EnvVars("my-prog", &testSchemaConfig{})
+
+	idx := make(map[string]EnvVar, len(vars))
+	for _, ev := range vars {
+		idx[ev.Key] = ev
+	}
+
+	// the untagged fields use the lowercase field name, as viper does
+	require.Equal(t, EnvVar{Name: "MY_PROG_NOTAG", Key: "notag", Type: "string"}, idx["notag"])
+	require.NotContains(t, idx, "NoTag")
+}
+
+func TestEnvVars(t *testing.T) {
+	t.Parallel()
+
+	vars := EnvVars("my-prog", &testConfig{})
+
+	idx := make(map[string]EnvVar, len(vars))
+	for _, ev := range vars {
+		idx[ev.Key] = ev
+	}
+
+	require.Equal(t, EnvVar{Name: "MY_PROG_DATA_STR", Key: "data.str", Type: "string"}, idx["data.str"])
+	require.Equal(t, EnvVar{Name: "MY_PROG_DATA_ARR", Key: "data.arr", Type: "array of integer"}, idx["data.arr"])
+	require.Equal(t, EnvVar{Name: "MY_PROG_LOG_LEVEL", Key: "log.level", Type: "string"}, idx["log.level"])
+	require.Equal(t, EnvVar{Name: "MY_PROG_SHUTDOWN_TIMEOUT", Key: "shutdown_timeout", Type: "integer"}, idx["shutdown_timeout"])
+	require.Equal(t, EnvVar{Name: "MY_PROG_MAPDATA", Key: "mapdata", Type: "map of object"}, idx["mapdata"])
+	require.Equal(t, EnvVar{Name: "MY_PROG_REMOTECONFIGPROVIDER", Key: "remoteConfigProvider", Type: "string"}, idx["remoteConfigProvider"])
+	require.NotContains(t, idx, "data")
+	require.NotContains(t, idx, "validateErr")
+
+	for i := 1; i < len(vars); i++ {
+		require.Less(t, vars[i-1].Name, vars[i].Name)
+	}
+}
+
+func TestWriteEnvVars(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	err := WriteEnvVars(buf, "myprog", &testConfig{})
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.Regexp(t, `^ENVIRONMENT VARIABLE\s+CONFIGURATION KEY\s+TYPE\n`, out)
+	require.Regexp(t, `\nMYPROG_DATA_STR\s+data\.str\s+string\n`, out)
+
+	err = WriteEnvVars(errWriter{}, "myprog", &testConfig{})
+	require.Error(t, err)
+}
+
+//nolint:paralleltest
+func TestLoad_envVars(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"data": {"str": "file", "int": 1}}`), 0o600))
+
+	t.Setenv("ENVTEST_DATA_STR", "env")
+	t.Setenv("ENVTEST_DATA_ARR", "[1, 2, 3]")
+	t.Setenv("ENVTEST_LOG_LEVEL", "INFO")
+	t.Setenv("ENVTEST_SHUTDOWN_TIMEOUT", "5")
+	t.Setenv("ENVTEST_SLICE", "a,b")
+	t.Setenv("ENVTEST_MAPSTRING", "a=1, b=2")
+	t.Setenv("ENVTEST_NESTEDMAP", `{"a": {"b": "c"}}`)
+	t.Setenv("ENVTEST_MAPDATA", `{"x": {"str": "s", "arr": [4]}}`)
+
+	buf := &bytes.Buffer{}
+	cfg := &testConfig{}
+
+	err := Load("cmd", dir, "envtest", cfg, WithDebugDump(buf))
+	require.NoError(t, err)
+
+	require.Equal(t, testData{Str: "env", Int: 1, Arr: []int{1, 2, 3}}, cfg.Data)
+	require.Equal(t, "INFO", cfg.Log.Level)
+	require.Equal(t, int64(5), cfg.ShutdownTimeout)
+	require.Equal(t, []string{"a", "b"}, cfg.Slice)
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, cfg.MapString)
+	require.Equal(t, map[string]map[string]string{"a": {"b": "c"}}, cfg.NestedMap)
+	require.Equal(t, map[string]testData{"x": {Str: "s", Arr: []int{4}}}, cfg.MapData)
+
+	require.Contains(t, buf.String(), "data.str: env:ENVTEST_DATA_STR\n")
+	require.Contains(t, buf.String(), "log.level: env:ENVTEST_LOG_LEVEL\n")
+
+	t.Setenv("ENVTEST_MAPSTRING", "invalid")
+
+	err = Load("cmd", dir, "envtest", &testConfig{})
+	require.Error(t, err)
+
+	t.Setenv("ENVTEST_MAPSTRING", "")
+	t.Setenv("ENVTEST_SLICE", "[invalid")
+
+	err = Load("cmd", dir, "envtest", &testConfig{})
+	require.Error(t, err)
+}
+
+func Test_stringToCompositeHookFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		to      any
+		data    any
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "not a string",
+			to:   []string{},
+			data: 1,
+			want: 1,
+		},
+		{
+			name: "not a composite type",
+			to:   "",
+			data: "[1]",
+			want: "[1]",
+		},
+		{
+			name: "JSON array",
+			to:   []int{},
+			data: " [1, 2] ",
+			want: []any{float64(1), float64(2)},
+		},
+		{
+			name: "JSON object",
+			to:   testData{},
+			data: `{"str": "a"}`,
+			want: map[string]any{"str": "a"},
+		},
+		{
+			name:    "invalid JSON",
+			to:      map[string]string{},
+			data:    `{"str"`,
+			wantErr: true,
+		},
+		{
+			name: "comma-separated slice",
+			to:   []string{},
+			data: "a,b",
+			want: "a,b",
+		},
+		{
+			name: "key=value pairs",
+			to:   map[string]string{},
+			data: "a=1,,b = 2",
+			want: map[string]any{"a": "1", "b": "2"},
+		},
+		{
+			name:    "invalid key=value pairs",
+			to:      map[string]string{},
+			data:    "a=1,b",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fn := stringToCompositeHookFunc()
+
+			got, err := fn(reflect.TypeOf(tt.data), reflect.TypeOf(tt.to), tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
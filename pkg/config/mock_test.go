@@ -140,6 +140,20 @@ func (mr *MockViperMockRecorder) BindPFlag(key, flag any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindPFlag", reflect.TypeOf((*MockViper)(nil).BindPFlag), key, flag)
 }
 
+// ConfigFileUsed mocks base method.
+func (m *MockViper) ConfigFileUsed() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigFileUsed")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ConfigFileUsed indicates an expected call of ConfigFileUsed.
+func (mr *MockViperMockRecorder) ConfigFileUsed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigFileUsed", reflect.TypeOf((*MockViper)(nil).ConfigFileUsed))
+}
+
 // Get mocks base method.
 func (m *MockViper) Get(key string) any {
 	m.ctrl.T.Helper()
@@ -154,6 +168,20 @@ func (mr *MockViperMockRecorder) Get(key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockViper)(nil).Get), key)
 }
 
+// MergeInConfig mocks base method.
+func (m *MockViper) MergeInConfig() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeInConfig")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeInConfig indicates an expected call of MergeInConfig.
+func (mr *MockViperMockRecorder) MergeInConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeInConfig", reflect.TypeOf((*MockViper)(nil).MergeInConfig))
+}
+
 // ReadConfig mocks base method.
 func (m *MockViper) ReadConfig(in io.Reader) error {
 	m.ctrl.T.Helper()
@@ -196,6 +224,18 @@ func (mr *MockViperMockRecorder) ReadRemoteConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRemoteConfig", reflect.TypeOf((*MockViper)(nil).ReadRemoteConfig))
 }
 
+// SetConfigFile mocks base method.
+func (m *MockViper) SetConfigFile(in string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConfigFile", in)
+}
+
+// SetConfigFile indicates an expected call of SetConfigFile.
+func (mr *MockViperMockRecorder) SetConfigFile(in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConfigFile", reflect.TypeOf((*MockViper)(nil).SetConfigFile), in)
+}
+
 // SetConfigName mocks base method.
 func (m *MockViper) SetConfigName(in string) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"io"
+)
+
+// Option is a type alias for a function that configures the configuration loading.
+type Option func(*loadOptions)
+
+type loadOptions struct {
+	ctx         context.Context //nolint:containedctx
+	environment string
+	debugDump   io.Writer
+	awsCache    AWSSecretCache
+	files       []string
+}
+
+// WithEnvironment sets the environment name (e.g. "production") used to load the
+// "config.<environment>.<ext>" overlay file.
+// If not set, the value of the <ENVPREFIX>_ENVIRONMENT environment variable is used.
+func WithEnvironment(name string) Option {
+	return func(o *loadOptions) {
+		o.environment = name
+	}
+}
+
+// WithDebugDump writes to w the list of the final configuration keys,
+// each with the source that supplied its value (default, file, env, remote).
+// The configuration values are never written.
+func WithDebugDump(w io.Writer) Option {
+	return func(o *loadOptions) {
+		o.debugDump = w
+	}
+}
+
+// WithContext sets the context used to resolve the secret references (default context.Background()).
+func WithContext(ctx context.Context) Option {
+	return func(o *loadOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithAWSSecretCache sets the cache used to resolve the "secret://aws/<id>#<jsonkey>" references
+// (e.g. github.com/Vonage/gosrvlib/pkg/awssecretcache.Cache).
+// The AWS secrets are fetched again on each load or reload.
+func WithAWSSecretCache(c AWSSecretCache) Option {
+	return func(o *loadOptions) {
+		o.awsCache = c
+	}
+}
+
+func newLoadOptions(opts []Option) *loadOptions {
+	o := &loadOptions{
+		ctx: context.Background(),
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(o)
+	}
+
+	return o
+}
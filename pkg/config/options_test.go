@@ -0,0 +1,48 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnvironment(t *testing.T) {
+	t.Parallel()
+
+	o := newLoadOptions([]Option{WithEnvironment("production")})
+	require.Equal(t, "production", o.environment)
+}
+
+func TestWithDebugDump(t *testing.T) {
+	t.Parallel()
+
+	w := &bytes.Buffer{}
+
+	o := newLoadOptions([]Option{WithDebugDump(w)})
+	require.Equal(t, w, o.debugDump)
+}
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "value")
+
+	o := newLoadOptions([]Option{WithContext(ctx)})
+	require.Equal(t, ctx, o.ctx)
+
+	o = newLoadOptions(nil)
+	require.Equal(t, context.Background(), o.ctx)
+}
+
+func TestWithAWSSecretCache(t *testing.T) {
+	t.Parallel()
+
+	c := &testAWSSecretCache{}
+
+	o := newLoadOptions([]Option{WithAWSSecretCache(c)})
+	require.Equal(t, c, o.awsCache)
+}
+
+type testCtxKey struct{}
@@ -0,0 +1,643 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// JSONSchemaDraft is the JSON Schema version of the generated schemas.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Struct tags used to generate the schema.
+const (
+	tagMapstructure = "mapstructure"
+	tagValidate     = "validate"
+	tagDescription  = "description"
+)
+
+// regexOneOfValues splits the values of the "oneof" validation tag, as in go-playground/validator.
+var regexOneOfValues = regexp.MustCompile(`'[^']*'|\S+`)
+
+// validateFormats maps the go-playground/validator tags to the JSON Schema formats.
+var validateFormats = map[string]string{
+	"datetime_rfc3339": "date-time",
+	"email":            "email",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"http_url":         "uri",
+	"ipv4":             "ipv4",
+	"ipv6":             "ipv6",
+	"uri":              "uri",
+	"url":              "uri",
+	"uuid":             "uuid",
+}
+
+// validatePatterns maps the go-playground/validator tags to the JSON Schema patterns.
+var validatePatterns = map[string]string{
+	"alpha":         `^[a-zA-Z]+$`,
+	"alphanum":      `^[a-zA-Z0-9]+$`,
+	"base64":        `^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{4})$`,
+	"hexadecimal":   `^(0[xX])?[0-9a-fA-F]+$`,
+	"hostname_port": `^[^:]*:[0-9]{1,5}$`,
+	"lowercase":     `^[^A-Z]*$`,
+	"numeric":       `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"uppercase":     `^[^a-z]*$`,
+}
+
+// Schema is a JSON Schema (draft-07) node.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// SchemaOption is a type alias for a function that configures the schema and reference generators.
+type SchemaOption func(*schemaConfig)
+
+type schemaConfig struct {
+	title       string
+	description string
+	flagSet     *pflag.FlagSet
+}
+
+// WithSchemaTitle sets the title of the generated schema and reference.
+func WithSchemaTitle(title string) SchemaOption {
+	return func(c *schemaConfig) {
+		c.title = title
+	}
+}
+
+// WithSchemaDescription sets the description of the generated schema and reference.
+func WithSchemaDescription(description string) SchemaOption {
+	return func(c *schemaConfig) {
+		c.description = description
+	}
+}
+
+// WithSchemaFlagSet adds the command-line flags section to the generated markdown reference.
+func WithSchemaFlagSet(fs *pflag.FlagSet) SchemaOption {
+	return func(c *schemaConfig) {
+		c.flagSet = fs
+	}
+}
+
+// GenerateSchema reflects the configuration type into a JSON Schema.
+//
+// The properties are named after the "mapstructure" tags, and described by
+// the optional "description" tags. The default values are the ones set by the
+// library and by the Configuration.SetDefaults method. The common
+// go-playground "validate" tags are mapped to the schema constraints (e.g.
+// required, min, max, len, oneof, url, email, hostname_port). With
+// "omitempty" the zero value is also allowed.
+func GenerateSchema(cfg Configuration, opts ...SchemaOption) *Schema {
+	sc := &schemaConfig{}
+
+	for _, applyOpt := range opts {
+		applyOpt(sc)
+	}
+
+	v := newViper()
+	setDefaultValues(v)
+	cfg.SetDefaults(v)
+
+	g := &schemaGenerator{defaults: v}
+
+	root := g.object(reflect.TypeOf(cfg), "")
+	g.addProperties(root, reflect.TypeOf(remoteSourceConfig{}), "")
+
+	root.Schema = JSONSchemaDraft
+	root.Title = sc.title
+	root.Description = sc.description
+
+	return root
+}
+
+// JSONSchema returns the indented JSON Schema of the configuration type (see GenerateSchema).
+func JSONSchema(cfg Configuration, opts ...SchemaOption) ([]byte, error) {
+	return json.MarshalIndent(GenerateSchema(cfg, opts...), "", "  ") //nolint:wrapcheck
+}
+
+// MarkdownReference returns the markdown documentation of all the configuration parameters,
+// with their type, default value, constraints and description (see GenerateSchema).
+func MarkdownReference(cfg Configuration, opts ...SchemaOption) []byte {
+	sc := &schemaConfig{}
+
+	for _, applyOpt := range opts {
+		applyOpt(sc)
+	}
+
+	s := GenerateSchema(cfg, opts...)
+	b := &bytes.Buffer{}
+
+	if sc.title != "" {
+		fmt.Fprintf(b, "# %s\n\n", sc.title)
+	}
+
+	if sc.description != "" {
+		fmt.Fprintf(b, "%s\n\n", sc.description)
+	}
+
+	b.WriteString("## Configuration parameters\n\n")
+	b.WriteString("| Key | Type | Default | Required | Constraints | Description |\n")
+	b.WriteString("|-----|------|---------|----------|-------------|-------------|\n")
+
+	writeMarkdownRows(b, s, "")
+
+	if sc.flagSet != nil {
+		b.WriteString("\n## Command-line flags\n\n")
+		b.WriteString("| Flag | Shorthand | Default | Description |\n")
+		b.WriteString("|------|-----------|---------|-------------|\n")
+
+		sc.flagSet.VisitAll(func(f *pflag.Flag) {
+			fmt.Fprintf(b, "| `--%s` | %s | %s | %s |\n", f.Name, mdCode(f.Shorthand, ""), mdCode(f.DefValue, ""), mdEscape(f.Usage))
+		})
+	}
+
+	return b.Bytes()
+}
+
+type schemaGenerator struct {
+	defaults Viper
+}
+
+// object returns the schema of a struct type.
+func (g *schemaGenerator) object(t reflect.Type, path string) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	g.addProperties(s, t, path)
+
+	return s
+}
+
+// addProperties adds the fields of the struct type to the object schema.
+func (g *schemaGenerator) addProperties(s *Schema, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, squash := fieldName(f)
+		if name == "-" || (!f.IsExported() && !squash) {
+			continue
+		}
+
+		if squash {
+			g.addProperties(s, f.Type, path)
+			continue
+		}
+
+		key := joinPath(path, name)
+		tags := strings.Split(f.Tag.Get(tagValidate), ",")
+
+		fs := g.field(f.Type, key, tags)
+		fs.Description = f.Tag.Get(tagDescription)
+		s.Properties[name] = fs
+
+		if hasTag(tags, "required") {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	sort.Strings(s.Required)
+}
+
+// field returns the schema of a field type with the validation tags.
+func (g *schemaGenerator) field(t reflect.Type, key string, tags []string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	itemTags, hasDive := diveTags(tags)
+
+	var s *Schema
+
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.object(t, key)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			s = &Schema{Type: "string"}
+			break
+		}
+
+		s = &Schema{Type: "array", Items: g.field(t.Elem(), key, itemTags)}
+	case reflect.Map:
+		s = &Schema{Type: "object", AdditionalProperties: g.field(t.Elem(), key, itemTags)}
+	default:
+		s = &Schema{Type: jsonType(t)}
+	}
+
+	if hasDive {
+		tags = tags[:len(tags)-len(itemTags)-1]
+	}
+
+	applyConstraints(s, t, tags)
+
+	if s.Type != "object" && s.Type != "array" {
+		if def := g.defaults.Get(key); def != nil {
+			s.Default = def
+		}
+	}
+
+	return s
+}
+
+// applyConstraints maps the validation tags to the schema constraints.
+// With "omitempty" the constraints are wrapped to also allow the zero value.
+func applyConstraints(s *Schema, t reflect.Type, tags []string) {
+	c := &Schema{}
+
+	for _, tag := range tags {
+		name, param, _ := strings.Cut(strings.TrimSpace(tag), "=")
+		applyConstraint(c, t, name, param)
+	}
+
+	if reflect.DeepEqual(c, &Schema{}) {
+		return
+	}
+
+	if !hasTag(tags, "omitempty") {
+		mergeConstraints(s, c)
+		return
+	}
+
+	if reflect.DeepEqual(c, &Schema{Enum: c.Enum}) {
+		s.Enum = append([]any{zeroValue(t)}, c.Enum...)
+		return
+	}
+
+	s.AnyOf = []*Schema{{Const: zeroValue(t)}, c}
+}
+
+// applyConstraint maps a single validation tag to the schema constraint.
+func applyConstraint(c *Schema, t reflect.Type, name, param string) {
+	if f, ok := validateFormats[name]; ok {
+		c.Format = f
+		return
+	}
+
+	if p, ok := validatePatterns[name]; ok {
+		c.Pattern = p
+		return
+	}
+
+	switch name {
+	case "min", "gte":
+		setMin(c, t, param, false)
+	case "max", "lte":
+		setMax(c, t, param, false)
+	case "gt":
+		setMin(c, t, param, true)
+	case "lt":
+		setMax(c, t, param, true)
+	case "len":
+		setMin(c, t, param, false)
+		setMax(c, t, param, false)
+	case "oneof":
+		for _, v := range regexOneOfValues.FindAllString(param, -1) {
+			c.Enum = append(c.Enum, enumValue(t, strings.Trim(v, "'")))
+		}
+	}
+}
+
+func setMin(c *Schema, t reflect.Type, param string, exclusive bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	count := int(n)
+	if exclusive {
+		count++ // the lengths are integers: "gt=n" means "at least n+1"
+	}
+
+	switch jsonType(t) {
+	case "string":
+		c.MinLength = intPtr(count)
+	case "array":
+		c.MinItems = intPtr(count)
+	case "object":
+		c.MinProperties = intPtr(count)
+	default:
+		if exclusive {
+			c.ExclusiveMinimum = &n
+		} else {
+			c.Minimum = &n
+		}
+	}
+}
+
+func setMax(c *Schema, t reflect.Type, param string, exclusive bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	count := int(n)
+	if exclusive {
+		count-- // the lengths are integers: "lt=n" means "at most n-1"
+	}
+
+	switch jsonType(t) {
+	case "string":
+		c.MaxLength = intPtr(count)
+	case "array":
+		c.MaxItems = intPtr(count)
+	case "object":
+		c.MaxProperties = intPtr(count)
+	default:
+		if exclusive {
+			c.ExclusiveMaximum = &n
+		} else {
+			c.Maximum = &n
+		}
+	}
+}
+
+// mergeConstraints copies the non-empty constraints of c into s.
+func mergeConstraints(s, c *Schema) {
+	sv := reflect.ValueOf(s).Elem()
+	cv := reflect.ValueOf(c).Elem()
+
+	for i := range cv.NumField() {
+		if !cv.Field(i).IsZero() {
+			sv.Field(i).Set(cv.Field(i))
+		}
+	}
+}
+
+// jsonType returns the JSON Schema type of the Go type.
+//
+//nolint:exhaustive
+func jsonType(t reflect.Type) any {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return []string{"string", "integer"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+
+	return nil
+}
+
+// enumValue converts the "oneof" value to the field type.
+func enumValue(t reflect.Type, v string) any {
+	switch jsonType(t) {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+
+	return v
+}
+
+// zeroValue returns the JSON zero value of the Go type.
+func zeroValue(t reflect.Type) any {
+	switch jsonType(t) {
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+
+	return nil
+}
+
+// fieldName returns the configuration key name of the field, as set by the "mapstructure" tag,
+// and whether it is squashed into the parent struct.
+// The untagged fields use the lowercase field name, as viper does.
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get(tagMapstructure)
+	name, opts, _ := strings.Cut(tag, ",")
+
+	if slices.Contains(strings.Split(opts, ","), "squash") {
+		return "", true
+	}
+
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+
+	return name, false
+}
+
+// diveTags returns the validation tags to apply to the items of slices and maps.
+func diveTags(tags []string) ([]string, bool) {
+	for i, tag := range tags {
+		if strings.TrimSpace(tag) == "dive" {
+			return tags[i+1:], true
+		}
+	}
+
+	return nil, false
+}
+
+func hasTag(tags []string, name string) bool {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// writeMarkdownRows writes the reference table rows of the schema leaves.
+func writeMarkdownRows(b *bytes.Buffer, s *Schema, path string) {
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := s.Properties[k]
+		key := joinPath(path, k)
+
+		if p.Properties != nil {
+			writeMarkdownRows(b, p, key)
+			continue
+		}
+
+		required := ""
+		if contains(s.Required, k) {
+			required = "yes"
+		}
+
+		def := ""
+		if p.Default != nil {
+			d, _ := json.Marshal(p.Default)
+			def = string(d)
+		}
+
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			key, typeName(p), mdCode(def, ""), required, mdEscape(constraintsText(p)), mdEscape(p.Description))
+	}
+}
+
+// constraintsText returns the human-readable constraints of a schema node.
+func constraintsText(s *Schema) string {
+	var out []string
+
+	if len(s.AnyOf) == 2 {
+		c := constraintsText(s.AnyOf[1])
+		if c != "" {
+			out = append(out, "empty or "+c)
+		}
+	}
+
+	if s.Enum != nil {
+		vals := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			d, _ := json.Marshal(v)
+			vals = append(vals, string(d))
+		}
+
+		out = append(out, "one of: "+strings.Join(vals, ", "))
+	}
+
+	add := func(label string, v any) {
+		out = append(out, fmt.Sprintf("%s: %v", label, v))
+	}
+
+	for _, c := range []struct {
+		label string
+		value any
+	}{
+		{"format", s.Format},
+		{"pattern", s.Pattern},
+		{"min", s.Minimum},
+		{"max", s.Maximum},
+		{"greater than", s.ExclusiveMinimum},
+		{"less than", s.ExclusiveMaximum},
+		{"min length", s.MinLength},
+		{"max length", s.MaxLength},
+		{"min items", s.MinItems},
+		{"max items", s.MaxItems},
+		{"min properties", s.MinProperties},
+		{"max properties", s.MaxProperties},
+	} {
+		v := reflect.ValueOf(c.value)
+		if v.IsZero() {
+			continue
+		}
+
+		if v.Kind() == reflect.Pointer {
+			add(c.label, v.Elem().Interface())
+			continue
+		}
+
+		add(c.label, c.value)
+	}
+
+	return strings.Join(out, "; ")
+}
+
+// typeName returns the human-readable type of a schema node.
+func typeName(s *Schema) string {
+	switch t := s.Type.(type) {
+	case string:
+		if t == "array" && s.Items != nil {
+			return "array of " + typeName(s.Items)
+		}
+
+		if ap, ok := s.AdditionalProperties.(*Schema); ok && t == "object" {
+			return "map of " + typeName(ap)
+		}
+
+		return t
+	case []string:
+		return strings.Join(t, " or ")
+	}
+
+	return "any"
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mdCode(s, empty string) string {
+	if s == "" {
+		return empty
+	}
+
+	return "`" + s + "`"
+}
+
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
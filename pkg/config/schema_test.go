@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+type testSchemaItem struct {
+	Name string `mapstructure:"name" validate:"required,alphanum"`
+}
+
+type testSchemaConfig struct {
+	BaseConfig `mapstructure:",squash" validate:"required"`
+
+	Enabled  bool                      `mapstructure:"enabled" description:"Enable the service"`
+	Address  string                    `mapstructure:"address" validate:"required,hostname_port" description:"Server | address"`
+	URL      string                    `mapstructure:"url" validate:"omitempty,url"`
+	Retries  int                       `mapstructure:"retries" validate:"min=1,max=10"`
+	Ratio    float64                   `mapstructure:"ratio" validate:"gt=0,lt=1"`
+	Mode     string                    `mapstructure:"mode" validate:"required,oneof=fast 'very slow'"`
+	Level    int                       `mapstructure:"level" validate:"oneof=1 2 3"`
+	Weight   float32                   `mapstructure:"weight" validate:"oneof=0.5 1.5"`
+	Code     string                    `mapstructure:"code" validate:"len=3"`
+	Prefix   string                    `mapstructure:"prefix" validate:"gt=2,lt=10"`
+	Timeout  time.Duration             `mapstructure:"timeout"`
+	Tags     []string                  `mapstructure:"tags" validate:"min=1,dive,lowercase"`
+	Items    []testSchemaItem          `mapstructure:"items"`
+	Hosts    []string                  `mapstructure:"hosts" validate:"gt=0,lt=4"`
+	Labels   map[string]string         `mapstructure:"labels" validate:"max=5"`
+	Limits   map[string]int            `mapstructure:"limits" validate:"dive,gte=0"`
+	Headers  map[string]string         `mapstructure:"headers" validate:"gt=1,lt=3"`
+	Data     []byte                    `mapstructure:"data"`
+	Ptr      *testSchemaItem           `mapstructure:"ptr"`
+	Any      any                       `mapstructure:"any"`
+	Nested   map[string]testSchemaItem `mapstructure:"nested"`
+	Skip     string                    `mapstructure:"-"`
+	NoTag    string
+	Invalid  int `mapstructure:"invalid" validate:"min=x,max=y"`
+	unexport string
+}
+
+func (c *testSchemaConfig) SetDefaults(v Viper) {
+	v.SetDefault("enabled", true)
+	v.SetDefault("address", ":8080")
+	v.SetDefault("retries", 3)
+}
+
+func (c *testSchemaConfig) Validate() error {
+	return nil
+}
+
+func TestGenerateSchema(t *testing.T) {
+	t.Parallel()
+
+	s := GenerateSchema(&testSchemaConfig{}, WithSchemaTitle("Test"), WithSchemaDescription("Test configuration"))
+
+	require.Equal(t, JSONSchemaDraft, s.Schema)
+	require.Equal(t, "Test", s.Title)
+	require.Equal(t, "Test configuration", s.Description)
+	require.Equal(t, "object", s.Type)
+	require.Equal(t, false, s.AdditionalProperties)
+	require.Equal(t, []string{"address", "log", "mode"}, s.Required)
+
+	p := s.Properties
+
+	require.NotContains(t, p, "Skip")
+	require.NotContains(t, p, "unexport")
+	require.Contains(t, p, "notag", "the untagged fields use the lowercase field name")
+	require.NotContains(t, p, "NoTag")
+	require.Contains(t, p, "remoteConfigProvider")
+	require.Contains(t, p, "shutdown_timeout")
+
+	require.Equal(t, "boolean", p["enabled"].Type)
+	require.Equal(t, true, p["enabled"].Default)
+	require.Equal(t, "Enable the service", p["enabled"].Description)
+
+	require.Equal(t, validatePatterns["hostname_port"], p["address"].Pattern)
+	require.Equal(t, ":8080", p["address"].Default)
+
+	require.Len(t, p["url"].AnyOf, 2)
+	require.Equal(t, "", p["url"].AnyOf[0].Const)
+	require.Equal(t, "uri", p["url"].AnyOf[1].Format)
+
+	require.InDelta(t, 1, *p["retries"].Minimum, 0)
+	require.InDelta(t, 10, *p["retries"].Maximum, 0)
+	require.Equal(t, 3, p["retries"].Default)
+
+	require.Equal(t, "number", p["ratio"].Type)
+	require.InDelta(t, 0, *p["ratio"].ExclusiveMinimum, 0)
+	require.InDelta(t, 1, *p["ratio"].ExclusiveMaximum, 0)
+
+	require.Equal(t, []any{"fast", "very slow"}, p["mode"].Enum)
+	require.Equal(t, []any{int64(1), int64(2), int64(3)}, p["level"].Enum)
+	require.Equal(t, []any{0.5, 1.5}, p["weight"].Enum)
+
+	require.Equal(t, 3, *p["code"].MinLength)
+	require.Equal(t, 3, *p["code"].MaxLength)
+
+	// the exclusive bounds of the integer lengths are converted to the inclusive ones
+	require.Equal(t, 3, *p["prefix"].MinLength)
+	require.Equal(t, 9, *p["prefix"].MaxLength)
+	require.Equal(t, 1, *p["hosts"].MinItems)
+	require.Equal(t, 3, *p["hosts"].MaxItems)
+	require.Equal(t, 2, *p["headers"].MinProperties)
+	require.Equal(t, 2, *p["headers"].MaxProperties)
+
+	require.Equal(t, []string{"string", "integer"}, p["timeout"].Type)
+
+	require.Equal(t, "array", p["tags"].Type)
+	require.Equal(t, 1, *p["tags"].MinItems)
+	require.Equal(t, validatePatterns["lowercase"], p["tags"].Items.Pattern)
+
+	require.Equal(t, "object", p["items"].Items.Type)
+	require.Equal(t, []string{"name"}, p["items"].Items.Required)
+
+	require.Equal(t, 5, *p["labels"].MaxProperties)
+	require.Equal(t, "string", p["labels"].AdditionalProperties.(*Schema).Type)   //nolint:forcetypeassert
+	require.InDelta(t, 0, *p["limits"].AdditionalProperties.(*Schema).Minimum, 0) //nolint:forcetypeassert
+
+	require.Equal(t, "string", p["data"].Type)
+	require.Equal(t, "object", p["ptr"].Type)
+	require.Nil(t, p["any"].Type)
+	require.Nil(t, p["invalid"].Minimum)
+
+	log := p["log"]
+	require.Equal(t, []string{"format", "level"}, log.Required)
+	require.Equal(t, "DEBUG", log.Properties["level"].Default)
+	require.Equal(t, []any{"", "udp", "tcp"}, log.Properties["network"].Enum)
+	require.Len(t, log.Properties["address"].AnyOf, 2)
+
+	require.Len(t, p["shutdown_timeout"].AnyOf, 2)
+	require.Equal(t, 0, p["shutdown_timeout"].AnyOf[0].Const)
+	require.InDelta(t, 3600, *p["shutdown_timeout"].AnyOf[1].Maximum, 0)
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := JSONSchema(&testSchemaConfig{})
+	require.NoError(t, err)
+
+	var s map[string]any
+
+	require.NoError(t, json.Unmarshal(data, &s))
+	require.Equal(t, JSONSchemaDraft, s["$schema"])
+
+	props := s["properties"].(map[string]any)                                      //nolint:forcetypeassert
+	url := props["url"].(map[string]any)                                           //nolint:forcetypeassert
+	require.Equal(t, "", url["anyOf"].([]any)[0].(map[string]any)["const"])        //nolint:forcetypeassert
+	require.Equal(t, false, props["log"].(map[string]any)["additionalProperties"]) //nolint:forcetypeassert
+}
+
+func TestMarkdownReference(t *testing.T) {
+	t.Parallel()
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.StringP("configDir", "c", "", "Configuration directory")
+	fs.String("logLevel", "INFO", "Log level")
+
+	md := string(MarkdownReference(&testSchemaConfig{},
+		WithSchemaTitle("Test"),
+		WithSchemaDescription("Test configuration"),
+		WithSchemaFlagSet(fs),
+	))
+
+	require.Contains(t, md, "# Test\n\nTest configuration\n\n## Configuration parameters\n")
+	require.Contains(t, md, "| `address` | string | `\":8080\"` | yes | pattern: ^[^:]*:[0-9]{1,5}$ | Server \\| address |\n")
+	require.Contains(t, md, "| `log.level` | string | `\"DEBUG\"` | yes | one of: \"EMERGENCY\", \"ALERT\", \"CRITICAL\", \"ERROR\", \"WARNING\", \"NOTICE\", \"INFO\", \"DEBUG\" | Standard syslog log level |\n")
+	require.Contains(t, md, "| `shutdown_timeout` | integer | `30` |  | empty or min: 1; max: 3600 |")
+	require.Contains(t, md, "| `tags` | array of string |  |  | min items: 1 |  |\n")
+	require.Contains(t, md, "| `labels` | map of string |  |  | max properties: 5 |  |\n")
+	require.Contains(t, md, "| `notag` | string |")
+	require.Contains(t, md, "| `timeout` | string or integer |")
+	require.Contains(t, md, "| `any` | any |")
+	require.Contains(t, md, "| `ptr.name` | string |  | yes | pattern: ^[a-zA-Z0-9]+$ |  |\n")
+	require.Contains(t, md, "## Command-line flags\n")
+	require.Contains(t, md, "| `--configDir` | `c` |  | Configuration directory |\n")
+	require.Contains(t, md, "| `--logLevel` |  | `INFO` | Log level |\n")
+
+	md = string(MarkdownReference(&testSchemaConfig{}))
+	require.NotContains(t, md, "Command-line flags")
+	require.True(t, len(md) > 0 && md[0:2] == "##")
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Secret reference prefixes.
+const (
+	secretRefAWS  = "secret://aws/" // AWS Secrets Manager: secret://aws/<id>#<jsonkey>
+	secretRefFile = "file://"       // local file: file:///run/secrets/db
+	secretRefEnv  = "env://"        // environment variable: env://NAME
+	secretRef     = "secret://"     // generic secret reference prefix
+)
+
+// regexEnvName matches the valid environment variable names.
+var regexEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AWSSecretCache is the interface to retrieve the AWS Secrets Manager secrets.
+// It is implemented by github.com/Vonage/gosrvlib/pkg/awssecretcache.Cache.
+type AWSSecretCache interface {
+	GetSecretString(ctx context.Context, key string) (string, error)
+	Remove(key string)
+}
+
+// secretResolver resolves the secret references in the configuration string fields.
+type secretResolver struct {
+	ctx       context.Context //nolint:containedctx
+	awsCache  AWSSecretCache
+	refreshed map[string]bool
+}
+
+// resolveSecrets replaces the secret references in all the exported string fields of cfg with the secret values.
+// The AWS secrets are refreshed on each load, so a reload always gets the current values.
+func resolveSecrets(ctx context.Context, awsCache AWSSecretCache, cfg any) error {
+	r := &secretResolver{
+		ctx:       ctx,
+		awsCache:  awsCache,
+		refreshed: make(map[string]bool),
+	}
+
+	return r.resolveValue(reflect.ValueOf(cfg), "")
+}
+
+// resolveValue recursively resolves the secret references in the value.
+//
+//nolint:exhaustive
+func (r *secretResolver) resolveValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return r.resolveValue(v.Elem(), path)
+	case reflect.Struct:
+		return r.resolveStruct(v, path)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			err := r.resolveValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return r.resolveMap(v, path)
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+
+		s, err := r.resolve(v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		v.SetString(s)
+	}
+
+	return nil
+}
+
+func (r *secretResolver) resolveStruct(v reflect.Value, path string) error {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		err := r.resolveValue(v.Field(i), fieldPath(path, f))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *secretResolver) resolveMap(v reflect.Value, path string) error {
+	if v.IsNil() {
+		return nil
+	}
+
+	iter := v.MapRange()
+
+	for iter.Next() {
+		// map values are not addressable, so they are resolved on a copy
+		val := reflect.New(iter.Value().Type()).Elem()
+		val.Set(iter.Value())
+
+		err := r.resolveValue(val, fmt.Sprintf("%s[%v]", path, iter.Key()))
+		if err != nil {
+			return err
+		}
+
+		v.SetMapIndex(iter.Key(), val)
+	}
+
+	return nil
+}
+
+// resolve returns the value of the secret reference, or the input string if it is not a reference.
+func (r *secretResolver) resolve(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, secretRefAWS):
+		return r.resolveAWS(strings.TrimPrefix(s, secretRefAWS))
+	case strings.HasPrefix(s, secretRef):
+		return "", errors.New("unsupported secret reference provider")
+	case isFileRef(s):
+		return resolveFile(strings.TrimPrefix(s, secretRefFile))
+	case isEnvRef(s):
+		return resolveEnv(strings.TrimPrefix(s, secretRefEnv))
+	}
+
+	return s, nil
+}
+
+// resolveAWS returns the AWS secret value for the reference "<id>#<jsonkey>".
+func (r *secretResolver) resolveAWS(ref string) (string, error) {
+	if r.awsCache == nil {
+		return "", errors.New("AWS secret reference found but the AWS secret cache is not configured (see WithAWSSecretCache)")
+	}
+
+	id, key, hasKey := strings.Cut(ref, "#")
+	if id == "" {
+		return "", errors.New("empty AWS secret ID")
+	}
+
+	if !r.refreshed[id] {
+		r.awsCache.Remove(id)
+		r.refreshed[id] = true
+	}
+
+	secret, err := r.awsCache.GetSecretString(r.ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving AWS secret %s: %w", id, err)
+	}
+
+	if !hasKey {
+		return secret, nil
+	}
+
+	var data map[string]json.RawMessage
+
+	err = json.Unmarshal([]byte(secret), &data)
+	if err != nil {
+		return "", fmt.Errorf("the AWS secret %s is not a JSON object", id)
+	}
+
+	raw, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("the AWS secret %s does not contain the key %s", id, key)
+	}
+
+	var value string
+
+	if json.Unmarshal(raw, &value) == nil {
+		return value, nil
+	}
+
+	// non-string JSON values (e.g. numbers) are returned as they are
+	return string(raw), nil
+}
+
+// isFileRef returns true if the whole value is a local file reference "file:///<path>".
+// The file URLs with a host (e.g. "file://localhost/var/data"), a query, a fragment or spaces are not references.
+func isFileRef(s string) bool {
+	path, ok := strings.CutPrefix(s, secretRefFile)
+
+	return ok &&
+		len(path) > 1 &&
+		strings.HasPrefix(path, "/") &&
+		!strings.ContainsAny(path, "?#") &&
+		!strings.ContainsFunc(path, unicode.IsSpace)
+}
+
+// isEnvRef returns true if the whole value is an environment variable reference "env://NAME".
+func isEnvRef(s string) bool {
+	name, ok := strings.CutPrefix(s, secretRefEnv)
+
+	return ok && regexEnvName.MatchString(name)
+}
+
+// resolveFile returns the content of the file, without the trailing newlines.
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return "", fmt.Errorf("failed reading secret file %s: %w", path, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveEnv returns the value of the environment variable.
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("the secret environment variable %s is not set", name)
+	}
+
+	return value, nil
+}
+
+// fieldPath returns the configuration key path of the struct field (see fieldName).
+// The squashed embedded structs do not add a path element.
+func fieldPath(path string, f reflect.StructField) string {
+	name, squash := fieldName(f)
+	if squash {
+		return path
+	}
+
+	return joinPath(path, name)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
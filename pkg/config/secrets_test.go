@@ -0,0 +1,231 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/awssecretcache"
+	"github.com/stretchr/testify/require"
+)
+
+var _ AWSSecretCache = (*awssecretcache.Cache)(nil)
+
+type testAWSSecretCache struct {
+	secrets map[string]string
+	removed []string
+	calls   int
+}
+
+func (c *testAWSSecretCache) GetSecretString(_ context.Context, key string) (string, error) {
+	c.calls++
+
+	v, ok := c.secrets[key]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+
+	return v, nil
+}
+
+func (c *testAWSSecretCache) Remove(key string) {
+	c.removed = append(c.removed, key)
+}
+
+type testSecretNested struct {
+	Password string
+	Ptr      *string
+}
+
+type testSecretEmbedded struct {
+	Token string `mapstructure:"api_token"`
+}
+
+type testSecretConfig struct {
+	testSecretEmbedded `mapstructure:",squash"`
+
+	Plain   string
+	URL     string `mapstructure:"data_url"`
+	Whole   string
+	User    string
+	Port    string
+	File    string
+	Env     string
+	Nested  testSecretNested
+	PNested *testSecretNested
+	Slice   []string
+	Map     map[string]string
+	MapData map[string]testSecretNested
+	Array   [1]string
+	Any     any
+	NilPtr  *testSecretNested
+	NilMap  map[string]string
+	secret  string
+}
+
+//nolint:paralleltest
+func Test_resolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "db")
+	require.NoError(t, os.WriteFile(file, []byte("file-secret\n"), 0o600))
+
+	t.Setenv("GOSRVLIB_TEST_SECRET", "env-secret")
+
+	cache := &testAWSSecretCache{
+		secrets: map[string]string{
+			"prod/db": `{"user": "admin", "port": 5432}`,
+			"token":   "whole-secret",
+		},
+	}
+
+	ptr := "env://GOSRVLIB_TEST_SECRET"
+
+	cfg := &testSecretConfig{
+		Plain:   "plain",
+		URL:     "file://localhost/var/data",
+		Whole:   "secret://aws/token",
+		User:    "secret://aws/prod/db#user",
+		Port:    "secret://aws/prod/db#port",
+		File:    "file://" + file,
+		Env:     "env://GOSRVLIB_TEST_SECRET",
+		Nested:  testSecretNested{Password: "env://GOSRVLIB_TEST_SECRET", Ptr: &ptr},
+		PNested: &testSecretNested{Password: "secret://aws/token"},
+		Slice:   []string{"a", "env://GOSRVLIB_TEST_SECRET"},
+		Map:     map[string]string{"k": "env://GOSRVLIB_TEST_SECRET"},
+		MapData: map[string]testSecretNested{"k": {Password: "file://" + file}},
+		Array:   [1]string{"env://GOSRVLIB_TEST_SECRET"},
+		Any:     "env://GOSRVLIB_TEST_SECRET",
+		secret:  "env://GOSRVLIB_TEST_SECRET",
+	}
+
+	err := resolveSecrets(context.Background(), cache, cfg)
+	require.NoError(t, err)
+
+	require.Equal(t, "plain", cfg.Plain)
+	require.Equal(t, "file://localhost/var/data", cfg.URL)
+	require.Equal(t, "whole-secret", cfg.Whole)
+	require.Equal(t, "admin", cfg.User)
+	require.Equal(t, "5432", cfg.Port)
+	require.Equal(t, "file-secret", cfg.File)
+	require.Equal(t, "env-secret", cfg.Env)
+	require.Equal(t, "env-secret", cfg.Nested.Password)
+	require.Equal(t, "env-secret", *cfg.Nested.Ptr)
+	require.Equal(t, "whole-secret", cfg.PNested.Password)
+	require.Equal(t, []string{"a", "env-secret"}, cfg.Slice)
+	require.Equal(t, map[string]string{"k": "env-secret"}, cfg.Map)
+	require.Equal(t, "file-secret", cfg.MapData["k"].Password)
+	require.Equal(t, [1]string{"env-secret"}, cfg.Array)
+	require.Equal(t, "env://GOSRVLIB_TEST_SECRET", cfg.Any)
+	require.Equal(t, "env://GOSRVLIB_TEST_SECRET", cfg.secret)
+
+	// each secret is refreshed only once per load
+	require.ElementsMatch(t, []string{"token", "prod/db"}, cache.removed)
+	require.Equal(t, 4, cache.calls)
+}
+
+func Test_resolveSecrets_errors(t *testing.T) {
+	t.Parallel()
+
+	cache := &testAWSSecretCache{
+		secrets: map[string]string{
+			"text": "not-json",
+			"json": `{"user": "admin"}`,
+		},
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		cache AWSSecretCache
+	}{
+		{name: "unsupported provider", value: "secret://gcp/id", cache: cache},
+		{name: "missing cache", value: "secret://aws/json#user"},
+		{name: "empty id", value: "secret://aws/#user", cache: cache},
+		{name: "missing secret", value: "secret://aws/missing", cache: cache},
+		{name: "not json", value: "secret://aws/text#user", cache: cache},
+		{name: "missing json key", value: "secret://aws/json#password", cache: cache},
+		{name: "old file syntax", value: "secret://file/run/secrets/db", cache: cache},
+		{name: "missing file", value: "file:///missing/gosrvlib/secret", cache: cache},
+		{name: "missing env", value: "env://GOSRVLIB_TEST_MISSING_SECRET", cache: cache},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &testSecretConfig{Nested: testSecretNested{Password: tt.value}}
+
+			err := resolveSecrets(context.Background(), tt.cache, cfg)
+			require.Error(t, err)
+			require.ErrorContains(t, err, "nested.password:")
+
+			cfg = &testSecretConfig{URL: tt.value}
+			require.ErrorContains(t, resolveSecrets(context.Background(), tt.cache, cfg), "data_url:")
+
+			cfg = &testSecretConfig{testSecretEmbedded: testSecretEmbedded{Token: tt.value}}
+			require.ErrorContains(t, resolveSecrets(context.Background(), tt.cache, cfg), "api_token:")
+
+			cfg = &testSecretConfig{Slice: []string{tt.value}}
+			require.ErrorContains(t, resolveSecrets(context.Background(), tt.cache, cfg), "slice[0]:")
+
+			cfg = &testSecretConfig{MapData: map[string]testSecretNested{"k": {Password: tt.value}}}
+			require.ErrorContains(t, resolveSecrets(context.Background(), tt.cache, cfg), "mapdata[k].password:")
+		})
+	}
+}
+
+func Test_secretResolver_resolve_notReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"",
+		"plain",
+		"file://localhost/var/data",
+		"file:///var/data?mode=ro",
+		"file:///var/data#section",
+		"file:///var/my data",
+		"sqlite:file:///var/data.db",
+		" file:///var/data",
+		"env://",
+		"env://NOT A NAME",
+		"env://NAME/path",
+		"https://example.com/env://NAME",
+	}
+
+	r := &secretResolver{ctx: context.Background(), refreshed: make(map[string]bool)}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := r.resolve(value)
+			require.NoError(t, err)
+			require.Equal(t, value, got)
+		})
+	}
+}
+
+func TestLoad_secrets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-secret"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"string": "secret://aws/app#key", "data": {"str": "file://`+secretFile+`"}}`), 0o600))
+
+	cache := &testAWSSecretCache{secrets: map[string]string{"app": `{"key": "aws-secret"}`}}
+
+	cfg := &testConfig{}
+
+	err := Load("cmd", dir, "test", cfg, WithContext(context.Background()), WithAWSSecretCache(cache))
+	require.NoError(t, err)
+	require.Equal(t, "aws-secret", cfg.String)
+	require.Equal(t, "file-secret", cfg.Data.Str)
+
+	err = Load("cmd", dir, "test", &testConfig{})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "aws-secret")
+}
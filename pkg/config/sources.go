@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// Configuration key sources.
+const (
+	sourceDefault = "default"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceRemote  = "remote"
+)
+
+// dumpKeySources writes the list of the final configuration keys,
+// each with the source that supplied its value.
+func dumpKeySources(w io.Writer, lv, rv Viper, rs *remoteSourceConfig, envPrefix string, files []string) error {
+	sources := keySources(lv, rv, rs, envPrefix, files)
+
+	keys := make([]string, 0, len(sources))
+	for k := range sources {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		_, err := fmt.Fprintf(w, "%s: %s\n", k, sources[k])
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// keySources returns the source of each final configuration key,
+// in increasing order of priority: default, files, env, remote.
+func keySources(lv, rv Viper, rs *remoteSourceConfig, envPrefix string, files []string) map[string]string {
+	keys := rv.AllKeys()
+	sources := make(map[string]string, len(keys))
+
+	for _, k := range keys {
+		sources[k] = sourceDefault
+	}
+
+	for _, file := range files {
+		for _, k := range fileKeys(file) {
+			sources[k] = sourceFile + ":" + file
+		}
+	}
+
+	for k := range sources {
+		name := envVarName(envPrefix, k)
+		if _, ok := os.LookupEnv(name); ok {
+			sources[k] = sourceEnv + ":" + name
+		}
+	}
+
+	if rs.Provider != "" {
+		for _, k := range keys {
+			if !reflect.DeepEqual(rv.Get(k), lv.Get(k)) {
+				sources[k] = sourceRemote + ":" + rs.Provider
+			}
+		}
+	}
+
+	return sources
+}
+
+// fileKeys returns the keys defined in a configuration file.
+func fileKeys(file string) []string {
+	v := newViper()
+	v.SetConfigFile(file)
+
+	if v.ReadInConfig() != nil {
+		return nil
+	}
+
+	return v.AllKeys()
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+type errWriter struct{}
+
+func (errWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+//nolint:paralleltest
+func Test_keySources(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("log:\n  level: INFO\nstring: file\nint: 1\n"), 0o600))
+
+	overlay := filepath.Join(dir, "config.local.toml")
+	require.NoError(t, os.WriteFile(overlay, []byte("int = 2\n"), 0o600))
+
+	remoteData := base64.StdEncoding.EncodeToString([]byte(`{"string": "remote"}`))
+
+	t.Setenv("KEYSRCTEST_REMOTECONFIGPROVIDER", "envvar")
+	t.Setenv("KEYSRCTEST_REMOTECONFIGDATA", remoteData)
+
+	buf := &bytes.Buffer{}
+
+	err := Load("cmd", dir, "keysrctest", &testConfig{}, WithDebugDump(buf))
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "log.level: file:"+file+"\n")
+	require.Contains(t, out, "log.format: default\n")
+	require.Contains(t, out, "int: file:"+overlay+"\n")
+	require.Contains(t, out, "remoteconfigprovider: env:KEYSRCTEST_REMOTECONFIGPROVIDER\n")
+	require.Contains(t, out, "string: remote:envvar\n")
+	require.NotContains(t, out, remoteData)
+}
+
+func Test_dumpKeySources_error(t *testing.T) {
+	t.Parallel()
+
+	v := viper.New()
+	v.SetDefault("key", "value")
+
+	err := dumpKeySources(errWriter{}, v, v, &remoteSourceConfig{}, "test", nil)
+	require.Error(t, err)
+
+	buf := &bytes.Buffer{}
+
+	err = dumpKeySources(buf, v, v, &remoteSourceConfig{}, "test", []string{"/missing/config.json"})
+	require.NoError(t, err)
+	require.Equal(t, "key: default\n", buf.String())
+}
+
+func TestLoad_debugDump_error(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{}`), 0o600))
+
+	err := Load("cmd", dir, "test", &testConfig{}, WithDebugDump(errWriter{}))
+	require.Error(t, err)
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// SubscriberFunc is the type of function called with the new configuration after a successful reload.
+type SubscriberFunc[T Configuration] func(ctx context.Context, cfg T)
+
+// WatcherOption is a type alias for a function that configures the configuration Watcher.
+type WatcherOption func(*watcherConfig)
+
+type watcherConfig struct {
+	pollInterval time.Duration
+	watchFile    bool
+	loadOpts     []Option
+}
+
+// WithLoadOptions sets the options used to load the configuration (see Load).
+func WithLoadOptions(opts ...Option) WatcherOption {
+	return func(c *watcherConfig) {
+		c.loadOpts = append(c.loadOpts, opts...)
+	}
+}
+
+// WithPollInterval periodically reloads the configuration (e.g. to track a remote configuration provider).
+// The subscribers are only notified when the configuration changes.
+func WithPollInterval(interval time.Duration) WatcherOption {
+	return func(c *watcherConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// WithFileWatch reloads the configuration when the local configuration files change (fsnotify),
+// including the overlay files in the same directory.
+func WithFileWatch() WatcherOption {
+	return func(c *watcherConfig) {
+		c.watchFile = true
+	}
+}
+
+// Watcher holds the current typed configuration and reloads it on demand,
+// on local file changes or periodically.
+//
+// Each reload reads again all the configuration sources (local file,
+// environment variables and remote provider) and runs the Validate() method.
+// An invalid configuration is rejected and the current one is kept active.
+//
+// Watcher implements the bootstrap.Component interface, and the Reload method
+// can be used with bootstrap.WithReloadFunc to reload on SIGHUP.
+type Watcher[T Configuration] struct {
+	cmdName    string
+	configDir  string
+	envPrefix  string
+	newConfig  func() T
+	cfg        watcherConfig
+	reloadMu   sync.Mutex
+	mu         sync.RWMutex
+	current    T
+	configFile string
+	subs       []SubscriberFunc[T]
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewWatcher loads and validates the initial configuration and returns a new Watcher.
+// The newConfig function must return a new configuration instance for each reload.
+func NewWatcher[T Configuration](cmdName, configDir, envPrefix string, newConfig func() T, opts ...WatcherOption) (*Watcher[T], error) {
+	if newConfig == nil {
+		return nil, errors.New("the newConfig function is required")
+	}
+
+	w := &Watcher[T]{
+		cmdName:   cmdName,
+		configDir: configDir,
+		envPrefix: envPrefix,
+		newConfig: newConfig,
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(&w.cfg)
+	}
+
+	cfg, file, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+
+	w.current = cfg
+	w.configFile = file
+
+	return w, nil
+}
+
+// Config returns the current configuration.
+func (w *Watcher[T]) Config() T {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.current
+}
+
+// Subscribe registers a function to be called with the new configuration after each successful reload.
+func (w *Watcher[T]) Subscribe(fn SubscriberFunc[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subs = append(w.subs, fn)
+}
+
+// Reload reads and validates the configuration again.
+// If the new configuration is valid and different from the current one,
+// it becomes the current configuration and is delivered to the subscribers.
+// On error the current configuration is kept.
+func (w *Watcher[T]) Reload(ctx context.Context) error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	l := logging.FromContext(ctx)
+
+	cfg, file, err := w.load()
+	if err != nil {
+		l.Error("configuration reload rejected", zap.Error(err))
+		return err
+	}
+
+	w.mu.Lock()
+
+	if reflect.DeepEqual(cfg, w.current) {
+		w.mu.Unlock()
+		l.Debug("configuration unchanged")
+
+		return nil
+	}
+
+	w.current = cfg
+	w.configFile = file
+	subs := append([]SubscriberFunc[T]{}, w.subs...)
+
+	w.mu.Unlock()
+
+	l.Info("configuration reloaded", zap.String("config_file", file))
+
+	for _, fn := range subs {
+		fn(ctx, cfg)
+	}
+
+	return nil
+}
+
+// Start starts watching the configuration sources in background, as configured by the options.
+// The file watch is skipped if no local configuration file has been loaded.
+// It returns an error if the watcher is already started.
+func (w *Watcher[T]) Start(ctx context.Context) error {
+	if w.done != nil {
+		return errors.New("the configuration watcher is already started")
+	}
+
+	var fw *fsnotify.Watcher
+
+	file := w.configFileUsed()
+
+	if w.cfg.watchFile && file == "" {
+		logging.FromContext(ctx).Warn("no local configuration file to watch")
+	}
+
+	if w.cfg.watchFile && file != "" {
+		var err error
+
+		fw, err = fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed creating the configuration file watcher: %w", err)
+		}
+
+		// watch the directory to also detect atomic replacements (e.g. Kubernetes ConfigMaps)
+		err = fw.Add(filepath.Dir(file))
+		if err != nil {
+			_ = fw.Close()
+			return fmt.Errorf("failed watching the configuration file: %w", err)
+		}
+	}
+
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	go w.watch(context.WithoutCancel(ctx), fw, w.stop, w.done)
+
+	return nil
+}
+
+// Stop stops watching the configuration sources.
+// If the context is done before the watcher is stopped, Stop can be called again to wait for it.
+func (w *Watcher[T]) Stop(ctx context.Context) error {
+	if w.done == nil {
+		return nil
+	}
+
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	}
+
+	w.done = nil
+
+	return nil
+}
+
+// HealthCheck always returns nil as an invalid configuration update never replaces the current one.
+func (w *Watcher[T]) HealthCheck(_ context.Context) error {
+	return nil
+}
+
+// watch reloads the configuration on the file events and poll ticks until stopped.
+func (w *Watcher[T]) watch(ctx context.Context, fw *fsnotify.Watcher, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	var (
+		tick   <-chan time.Time
+		events chan fsnotify.Event
+		errs   chan error
+	)
+
+	if w.cfg.pollInterval > 0 {
+		ticker := time.NewTicker(w.cfg.pollInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	if fw != nil {
+		defer func() { _ = fw.Close() }()
+
+		events = fw.Events
+		errs = fw.Errors
+	}
+
+	file := w.configFileUsed()
+	name := configFileName(file)
+	realFile, _ := filepath.EvalSymlinks(file)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-tick:
+			_ = w.Reload(ctx)
+		case ev := <-events:
+			curRealFile, _ := filepath.EvalSymlinks(file)
+			changed := isConfigFileEvent(ev, name)
+
+			if changed || curRealFile != realFile {
+				realFile = curRealFile
+
+				_ = w.Reload(ctx)
+			}
+		case err := <-errs:
+			logging.FromContext(ctx).Error("configuration file watcher error", zap.Error(err))
+		}
+	}
+}
+
+// isConfigFileEvent returns true if the event changed a main or overlay configuration file
+// with the specified base name (e.g. "config" for config.json and config.local.json).
+func isConfigFileEvent(ev fsnotify.Event, name string) bool {
+	return strings.HasPrefix(filepath.Base(ev.Name), name+".") &&
+		ev.Has(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename)
+}
+
+// configFileName returns the base name of the configuration file without the extension.
+func configFileName(file string) string {
+	base := filepath.Base(file)
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// configFileUsed returns the path of the local configuration file, or an empty string if none has been loaded.
+func (w *Watcher[T]) configFileUsed() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.configFile == "" {
+		return ""
+	}
+
+	return filepath.Clean(w.configFile)
+}
+
+// load reads a new configuration instance and returns it with the path of the local configuration file.
+func (w *Watcher[T]) load() (T, string, error) {
+	cfg := w.newConfig()
+	localViper := newViper()
+
+	err := loadConfig(localViper, newViper(), w.cmdName, w.configDir, w.envPrefix, cfg, w.cfg.loadOpts...)
+	if err != nil {
+		var zero T
+		return zero, "", err
+	}
+
+	return cfg, localViper.ConfigFileUsed(), nil
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/require"
+)
+
+type testWatchConfig struct {
+	BaseConfig `mapstructure:",squash"`
+
+	Value string `mapstructure:"value"`
+}
+
+func (c *testWatchConfig) SetDefaults(v Viper) {
+	v.SetDefault("value", "default")
+}
+
+func (c *testWatchConfig) Validate() error {
+	if c.Value == "invalid" {
+		return os.ErrInvalid
+	}
+
+	return nil
+}
+
+func newTestWatchConfig() *testWatchConfig {
+	return &testWatchConfig{}
+}
+
+func writeTestWatchConfig(t *testing.T, dir, value string) {
+	t.Helper()
+
+	data := []byte(`{"log":{"format":"JSON","level":"DEBUG"},"value":"` + value + `"}`)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600))
+}
+
+func TestNewWatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	w, err := NewWatcher[*testWatchConfig]("cmd", dir, "test", nil)
+	require.Error(t, err)
+	require.Nil(t, w)
+
+	w, err = NewWatcher("cmd", dir, "test", newTestWatchConfig)
+	require.Error(t, err)
+	require.Nil(t, w)
+
+	writeTestWatchConfig(t, dir, "invalid")
+
+	w, err = NewWatcher("cmd", dir, "test", newTestWatchConfig)
+	require.Error(t, err)
+	require.Nil(t, w)
+
+	writeTestWatchConfig(t, dir, "first")
+
+	w, err = NewWatcher("cmd", dir, "test", newTestWatchConfig, WithPollInterval(time.Second), WithFileWatch())
+	require.NoError(t, err)
+	require.NotNil(t, w)
+	require.Equal(t, "first", w.Config().Value)
+	require.Equal(t, time.Second, w.cfg.pollInterval)
+	require.True(t, w.cfg.watchFile)
+	require.Equal(t, filepath.Join(dir, "config.json"), w.configFileUsed())
+	require.NoError(t, w.HealthCheck(context.Background()))
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatchConfig(t, dir, "first")
+
+	w, err := NewWatcher("cmd", dir, "test", newTestWatchConfig)
+	require.NoError(t, err)
+
+	var got []string
+
+	w.Subscribe(func(_ context.Context, cfg *testWatchConfig) {
+		got = append(got, cfg.Value)
+	})
+
+	ctx := context.Background()
+
+	// unchanged
+	require.NoError(t, w.Reload(ctx))
+	require.Empty(t, got)
+
+	writeTestWatchConfig(t, dir, "second")
+	require.NoError(t, w.Reload(ctx))
+	require.Equal(t, "second", w.Config().Value)
+	require.Equal(t, []string{"second"}, got)
+
+	// invalid update is rejected
+	writeTestWatchConfig(t, dir, "invalid")
+	require.Error(t, w.Reload(ctx))
+	require.Equal(t, "second", w.Config().Value)
+	require.Equal(t, []string{"second"}, got)
+
+	// missing file is rejected
+	require.NoError(t, os.Remove(filepath.Join(dir, "config.json")))
+	require.Error(t, w.Reload(ctx))
+	require.Equal(t, "second", w.Config().Value)
+}
+
+func TestWatcher_StartStop(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []WatcherOption
+	}{
+		{
+			name: "file watch",
+			opts: []WatcherOption{WithFileWatch()},
+		},
+		{
+			name: "poll",
+			opts: []WatcherOption{WithPollInterval(10 * time.Millisecond)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeTestWatchConfig(t, dir, "first")
+
+			w, err := NewWatcher("cmd", dir, "test", newTestWatchConfig, tt.opts...)
+			require.NoError(t, err)
+
+			var (
+				mu  sync.Mutex
+				got string
+			)
+
+			w.Subscribe(func(_ context.Context, cfg *testWatchConfig) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				got = cfg.Value
+			})
+
+			ctx := context.Background()
+
+			require.NoError(t, w.Start(ctx))
+			require.Error(t, w.Start(ctx), "the watcher is already started")
+
+			writeTestWatchConfig(t, dir, "second")
+
+			require.Eventually(t, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+
+				return got == "second"
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.NoError(t, w.Stop(ctx))
+			require.NoError(t, w.Stop(ctx))
+		})
+	}
+}
+
+func TestWatcher_Start_error(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatchConfig(t, dir, "first")
+
+	w, err := NewWatcher("cmd", dir, "test", newTestWatchConfig, WithFileWatch())
+	require.NoError(t, err)
+
+	w.configFile = filepath.Join(dir, "missing", "config.json")
+
+	require.Error(t, w.Start(context.Background()))
+}
+
+func TestWatcher_Start_noConfigFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatchConfig(t, dir, "first")
+
+	w, err := NewWatcher("cmd", dir, "test", newTestWatchConfig, WithFileWatch())
+	require.NoError(t, err)
+
+	// no local configuration file: the file watch is skipped
+	w.configFile = ""
+	require.Empty(t, w.configFileUsed())
+
+	ctx := context.Background()
+
+	require.NoError(t, w.Start(ctx))
+	require.NoError(t, w.Stop(ctx))
+
+	// the watcher can be started again after being stopped
+	require.NoError(t, w.Start(ctx))
+	require.NoError(t, w.Stop(ctx))
+}
+
+func Test_isConfigFileEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ev   fsnotify.Event
+		want bool
+	}{
+		{name: "main file", ev: fsnotify.Event{Name: "/etc/app/app.json", Op: fsnotify.Write}, want: true},
+		{name: "overlay file", ev: fsnotify.Event{Name: "/etc/app/app.local.json", Op: fsnotify.Create}, want: true},
+		{name: "removed file", ev: fsnotify.Event{Name: "/etc/app/app.yaml", Op: fsnotify.Remove}, want: true},
+		{name: "other file", ev: fsnotify.Event{Name: "/etc/app/config.json", Op: fsnotify.Write}, want: false},
+		{name: "prefix file", ev: fsnotify.Event{Name: "/etc/app/application.json", Op: fsnotify.Write}, want: false},
+		{name: "chmod", ev: fsnotify.Event{Name: "/etc/app/app.json", Op: fsnotify.Chmod}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, isConfigFileEvent(tt.ev, configFileName("/etc/app/app.json")))
+		})
+	}
+}
+
+func TestWatcher_Stop_timeout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatchConfig(t, dir, "first")
+
+	w, err := NewWatcher("cmd", dir, "test", newTestWatchConfig)
+	require.NoError(t, err)
+
+	// simulate a watch loop that never completes
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := w.done
+
+	require.ErrorIs(t, w.Stop(ctx), context.Canceled)
+
+	// a second stop does not close the stop channel again
+	require.ErrorIs(t, w.Stop(ctx), context.Canceled)
+
+	close(done)
+
+	require.NoError(t, w.Stop(context.Background()))
+	require.NoError(t, w.Stop(context.Background()))
+}
@@ -0,0 +1,313 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultEjectionFailures is the default number of consecutive dial failures that ejects an endpoint.
+	DefaultEjectionFailures = 3
+
+	// DefaultEjectionTime is the default time an endpoint is excluded from the balancing after being ejected.
+	DefaultEjectionTime = 30 * time.Second
+)
+
+// Strategy is the algorithm used to select the endpoint of each new connection.
+type Strategy int
+
+const (
+	// RoundRobin selects the endpoints in turn (default).
+	RoundRobin Strategy = iota
+
+	// LeastInFlight selects the endpoint with the lowest number of open connections.
+	LeastInFlight
+
+	// PowerOfTwoChoices selects the endpoint with the lowest number of open connections between two random ones.
+	PowerOfTwoChoices
+)
+
+// DialFunc is the signature of the function used to dial the selected endpoint.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// BalancerOption is a type alias for a function that configures the Balancer.
+type BalancerOption func(b *Balancer)
+
+// WithStrategy sets the endpoint selection algorithm (default RoundRobin).
+func WithStrategy(s Strategy) BalancerOption {
+	return func(b *Balancer) {
+		b.strategy = s
+	}
+}
+
+// WithEjection sets the number of consecutive dial failures that ejects an endpoint,
+// and the time the endpoint is excluded from the balancing
+// (default DefaultEjectionFailures and DefaultEjectionTime).
+// A zero number of failures disables the outlier ejection.
+func WithEjection(failures int, ejectionTime time.Duration) BalancerOption {
+	return func(b *Balancer) {
+		b.ejectionFailures = failures
+		b.ejectionTime = ejectionTime
+	}
+}
+
+// WithDialFunc sets the function used to dial the selected endpoint (default net.Dialer.DialContext).
+func WithDialFunc(fn DialFunc) BalancerOption {
+	return func(b *Balancer) {
+		b.dialFn = fn
+	}
+}
+
+// Balancer is a client-side load balancing dialer that spreads the
+// connections across all the IP addresses resolved for a host.
+//
+// The addresses are resolved via the DNS cache, so they are refreshed when
+// the cache entries expire. The endpoints failing to connect are temporarily
+// ejected (outlier detection); when all the endpoints are ejected, all of them
+// are tried again.
+type Balancer struct {
+	cache            *Cache
+	strategy         Strategy
+	ejectionFailures int
+	ejectionTime     time.Duration
+	dialFn           DialFunc
+	nowFn            func() time.Time
+
+	mux   sync.Mutex
+	hosts map[string]*balancerHost
+}
+
+// balancerHost contains the endpoints of a host:port address.
+type balancerHost struct {
+	next      uint64
+	endpoints map[string]*endpoint
+}
+
+// endpoint contains the state of a resolved address.
+type endpoint struct {
+	address      string
+	inFlight     atomic.Int64
+	failures     int
+	ejectedUntil time.Time
+}
+
+// NewBalancer creates a new load balancing dialer using the DNS cache to resolve the hosts.
+// The DialContext method can be used with github.com/Vonage/gosrvlib/pkg/httpclient.WithDialContext
+// or in place of the DialContext in http.Transport.
+func (c *Cache) NewBalancer(opts ...BalancerOption) *Balancer {
+	var dialer net.Dialer
+
+	b := &Balancer{
+		cache:            c,
+		strategy:         RoundRobin,
+		ejectionFailures: DefaultEjectionFailures,
+		ejectionTime:     DefaultEjectionTime,
+		dialFn:           dialer.DialContext,
+		nowFn:            time.Now,
+		hosts:            make(map[string]*balancerHost),
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(b)
+	}
+
+	return b
+}
+
+// DialContext dials the network and address specified by the parameters,
+// connecting to one of the resolved IP addresses selected by the balancing strategy.
+// If the connection fails, the other addresses are tried in turn.
+func (b *Balancer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract host and port from %s: %w", address, err)
+	}
+
+	ips, err := b.cache.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found for host %s", host)
+	}
+
+	candidates := b.candidates(address, ips, port)
+
+	var errs []error
+
+	for len(candidates) > 0 {
+		idx := b.pick(address, candidates)
+		ep := candidates[idx]
+
+		conn, err := b.dialFn(ctx, network, ep.address)
+		if err == nil {
+			b.success(ep)
+			return newBalancerConn(conn, ep), nil
+		}
+
+		errs = append(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		b.failure(ctx, ep)
+
+		candidates = append(candidates[:idx], candidates[idx+1:]...)
+	}
+
+	return nil, fmt.Errorf("failed to dial %s: %w", address, errors.Join(errs...))
+}
+
+// candidates returns the endpoints of the resolved addresses that are not ejected,
+// or all the endpoints if all of them are ejected.
+// The endpoints of the addresses no longer resolved are removed.
+func (b *Balancer) candidates(address string, ips []string, port string) []*endpoint {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	h, ok := b.hosts[address]
+	if !ok {
+		h = &balancerHost{endpoints: make(map[string]*endpoint, len(ips))}
+		b.hosts[address] = h
+	}
+
+	resolved := make(map[string]*endpoint, len(ips))
+	all := make([]*endpoint, 0, len(ips))
+	active := make([]*endpoint, 0, len(ips))
+	now := b.nowFn()
+
+	for _, ip := range ips {
+		ep, ok := h.endpoints[ip]
+		if !ok {
+			ep = &endpoint{address: net.JoinHostPort(ip, port)}
+		}
+
+		resolved[ip] = ep
+		all = append(all, ep)
+
+		if !now.Before(ep.ejectedUntil) {
+			active = append(active, ep)
+		}
+	}
+
+	h.endpoints = resolved
+
+	if len(active) == 0 {
+		return all
+	}
+
+	return active
+}
+
+// pick returns the index of the selected candidate.
+//
+//nolint:gosec
+func (b *Balancer) pick(address string, candidates []*endpoint) int {
+	n := len(candidates)
+	if n == 1 {
+		return 0
+	}
+
+	switch b.strategy {
+	case LeastInFlight:
+		start := int(b.nextIndex(address) % uint64(n))
+		best := start
+
+		for i := 1; i < n; i++ {
+			j := (start + i) % n
+			if candidates[j].inFlight.Load() < candidates[best].inFlight.Load() {
+				best = j
+			}
+		}
+
+		return best
+	case PowerOfTwoChoices:
+		i := rand.Intn(n)
+		j := rand.Intn(n - 1)
+
+		if j >= i {
+			j++
+		}
+
+		if candidates[j].inFlight.Load() < candidates[i].inFlight.Load() {
+			return j
+		}
+
+		return i
+	case RoundRobin:
+	}
+
+	return int(b.nextIndex(address) % uint64(n))
+}
+
+// nextIndex returns the next round-robin counter of the address.
+func (b *Balancer) nextIndex(address string) uint64 {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	h := b.hosts[address]
+	h.next++
+
+	return h.next - 1
+}
+
+// success resets the endpoint failures.
+func (b *Balancer) success(ep *endpoint) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	ep.failures = 0
+	ep.ejectedUntil = time.Time{}
+}
+
+// failure counts a dial failure and ejects the endpoint when the threshold is reached.
+func (b *Balancer) failure(ctx context.Context, ep *endpoint) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	ep.failures++
+
+	if b.ejectionFailures <= 0 || ep.failures < b.ejectionFailures {
+		return
+	}
+
+	ep.failures = 0
+	ep.ejectedUntil = b.nowFn().Add(b.ejectionTime)
+
+	logging.FromContext(ctx).Warn(
+		"endpoint ejected",
+		zap.String("endpoint", ep.address),
+		zap.Duration("ejection_time", b.ejectionTime),
+	)
+}
+
+// balancerConn is a net.Conn that tracks the number of open connections of the endpoint.
+type balancerConn struct {
+	net.Conn
+	ep   *endpoint
+	once sync.Once
+}
+
+func newBalancerConn(conn net.Conn, ep *endpoint) *balancerConn {
+	ep.inFlight.Add(1)
+
+	return &balancerConn{Conn: conn, ep: ep}
+}
+
+// Close closes the connection.
+func (c *balancerConn) Close() error {
+	c.once.Do(func() { c.ep.inFlight.Add(-1) })
+
+	return c.Conn.Close() //nolint:wrapcheck
+}
@@ -0,0 +1,305 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+type testDialer struct {
+	mux    sync.Mutex
+	dials  []string
+	failed map[string]bool
+}
+
+func (d *testDialer) DialContext(_ context.Context, _, address string) (net.Conn, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.dials = append(d.dials, address)
+
+	if d.failed[address] {
+		return nil, errors.New("connection refused")
+	}
+
+	client, server := net.Pipe()
+	_ = server.Close()
+
+	return client, nil
+}
+
+func (d *testDialer) Dials() []string {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	dials := d.dials
+	d.dials = nil
+
+	return dials
+}
+
+func newTestBalancer(t *testing.T, ips []string, opts ...BalancerOption) (*Balancer, *testDialer) {
+	t.Helper()
+
+	resolver := &mockResolver{
+		lookupHost: func(_ context.Context, _ string) ([]string, error) {
+			return ips, nil
+		},
+	}
+
+	d := &testDialer{failed: make(map[string]bool)}
+
+	b := New(resolver, 1, time.Minute).NewBalancer(append([]BalancerOption{WithDialFunc(d.DialContext)}, opts...)...)
+
+	return b, d
+}
+
+func TestCache_NewBalancer(t *testing.T) {
+	t.Parallel()
+
+	b := New(nil, 1, time.Minute).NewBalancer(WithStrategy(PowerOfTwoChoices), WithEjection(5, time.Second))
+	require.Equal(t, PowerOfTwoChoices, b.strategy)
+	require.Equal(t, 5, b.ejectionFailures)
+	require.Equal(t, time.Second, b.ejectionTime)
+	require.NotNil(t, b.dialFn)
+}
+
+func TestBalancer_DialContext_roundRobin(t *testing.T) {
+	t.Parallel()
+
+	b, d := newTestBalancer(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"})
+
+	for range 6 {
+		conn, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+
+	require.Equal(t, []string{
+		"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80",
+		"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80",
+	}, d.Dials())
+}
+
+func TestBalancer_DialContext_leastInFlight(t *testing.T) {
+	t.Parallel()
+
+	b, d := newTestBalancer(t, []string{"10.0.0.1", "10.0.0.2"}, WithStrategy(LeastInFlight))
+
+	conn1, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+	require.NoError(t, err)
+
+	// the first endpoint has an open connection
+	for range 3 {
+		conn, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+
+	require.NoError(t, conn1.Close())
+	require.NoError(t, conn1.Close()) // the counter is only decremented once
+
+	require.Equal(t, []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.2:80", "10.0.0.2:80"}, d.Dials())
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	for _, ep := range b.hosts["example.com:80"].endpoints {
+		require.Equal(t, int64(0), ep.inFlight.Load())
+	}
+}
+
+func TestBalancer_DialContext_powerOfTwoChoices(t *testing.T) {
+	t.Parallel()
+
+	b, d := newTestBalancer(t, []string{"10.0.0.1", "10.0.0.2"}, WithStrategy(PowerOfTwoChoices))
+
+	var conns []net.Conn
+
+	// with two endpoints the choice is always the least loaded one
+	for range 4 {
+		conn, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+		require.NoError(t, err)
+
+		conns = append(conns, conn)
+	}
+
+	count := make(map[string]int)
+	for _, addr := range d.Dials() {
+		count[addr]++
+	}
+
+	require.Equal(t, map[string]int{"10.0.0.1:80": 2, "10.0.0.2:80": 2}, count)
+
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
+	}
+}
+
+func TestBalancer_DialContext_ejection(t *testing.T) {
+	t.Parallel()
+
+	b, d := newTestBalancer(t, []string{"10.0.0.1", "10.0.0.2"}, WithEjection(2, time.Minute))
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.nowFn = func() time.Time { return now }
+
+	d.failed["10.0.0.1:80"] = true
+
+	// the failing endpoint is skipped
+	for range 4 {
+		conn, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+
+	require.Equal(t, []string{
+		"10.0.0.1:80", "10.0.0.2:80",
+		"10.0.0.2:80",
+		"10.0.0.1:80", "10.0.0.2:80", // ejected after the second failure
+		"10.0.0.2:80",
+	}, d.Dials())
+
+	// all the endpoints are tried when all of them are ejected
+	d.failed["10.0.0.2:80"] = true
+
+	for range 2 {
+		_, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+		require.Error(t, err)
+	}
+
+	d.Dials()
+
+	_, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+	require.Error(t, err)
+	require.Len(t, d.Dials(), 2)
+
+	// the ejection expires
+	d.failed = map[string]bool{}
+	now = now.Add(time.Minute)
+
+	for range 2 {
+		conn, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+
+	require.ElementsMatch(t, []string{"10.0.0.1:80", "10.0.0.2:80"}, d.Dials())
+}
+
+func TestBalancer_DialContext_resolve(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux sync.Mutex
+		ips = []string{"10.0.0.1", "10.0.0.2"}
+	)
+
+	resolver := &mockResolver{
+		lookupHost: func(_ context.Context, _ string) ([]string, error) {
+			mux.Lock()
+			defer mux.Unlock()
+
+			return ips, nil
+		},
+	}
+
+	d := &testDialer{}
+	cache := New(resolver, 1, time.Minute)
+	b := cache.NewBalancer(WithDialFunc(d.DialContext))
+
+	conn, err := b.DialContext(t.Context(), "tcp", "example.com:80")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	mux.Lock()
+	ips = []string{"10.0.0.3"}
+	mux.Unlock()
+
+	// the cached addresses are used until the entry expires
+	conn, err = b.DialContext(t.Context(), "tcp", "example.com:80")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	cache.Remove("example.com")
+
+	conn, err = b.DialContext(t.Context(), "tcp", "example.com:80")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Equal(t, []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80"}, d.Dials())
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	require.Len(t, b.hosts["example.com:80"].endpoints, 1)
+}
+
+func TestBalancer_DialContext_errors(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestBalancer(t, []string{})
+
+	_, err := b.DialContext(t.Context(), "tcp", "example.com")
+	require.Error(t, err)
+
+	_, err = b.DialContext(t.Context(), "tcp", "example.com:80")
+	require.Error(t, err)
+
+	resolver := &mockResolver{
+		lookupHost: func(_ context.Context, _ string) ([]string, error) {
+			return nil, errors.New("lookup error")
+		},
+	}
+
+	_, err = New(resolver, 1, time.Minute).NewBalancer().DialContext(t.Context(), "tcp", "example.com:80")
+	require.Error(t, err)
+
+	// context canceled
+	b, d := newTestBalancer(t, []string{"10.0.0.1", "10.0.0.2"})
+	d.failed["10.0.0.1:80"] = true
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err = b.DialContext(ctx, "tcp", "example.com:80")
+	require.Error(t, err)
+	require.Len(t, d.Dials(), 1)
+}
+
+func TestBalancer_httpclient(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	resolver := &mockResolver{
+		lookupHost: func(_ context.Context, _ string) ([]string, error) {
+			return []string{"127.0.0.1"}, nil
+		},
+	}
+
+	b := New(resolver, 1, time.Minute).NewBalancer(WithStrategy(LeastInFlight))
+	client := httpclient.New(httpclient.WithDialContext(b.DialContext))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://upstream.test:"+port, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
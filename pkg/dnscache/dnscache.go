@@ -22,6 +22,14 @@ Each cache entry has a set time-to-live (TTL), so it will automatically expire.
 However, it is also possible to force the removal of a specific DNS entry or
 reset the entire cache.
 
+The Balancer returned by NewBalancer is a client-side load balancing dialer
+that spreads the connections across all the resolved IP addresses, using the
+round-robin, least-in-flight, or power-of-two-choices strategies. The
+endpoints failing to connect are temporarily ejected (outlier detection), and
+the addresses are resolved again when the cache entries expire. The
+Balancer.DialContext method can be used with
+github.com/Vonage/gosrvlib/pkg/httpclient.WithDialContext.
+
 This package is ideal for any Go application that relies heavily on DNS lookups.
 */
 package dnscache
@@ -9,7 +9,11 @@ import (
 	"github.com/Vonage/gosrvlib/pkg/logging"
 	"github.com/Vonage/gosrvlib/pkg/redact"
 	"github.com/Vonage/gosrvlib/pkg/traceid"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
 	"github.com/Vonage/gosrvlib/pkg/uidc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -20,6 +24,7 @@ type Client struct {
 	logPrefix         string
 	traceIDHeaderName string
 	redactFn          RedactFn
+	hedge             *hedger
 }
 
 // defaultClient() returns a default client.
@@ -47,6 +52,9 @@ func New(opts ...Option) *Client {
 }
 
 // Do performs the HTTP request with added trace ID and logging.
+// A client span is started with the OpenTelemetry global TracerProvider (see the tracing package),
+// and the W3C trace context is injected in the request headers.
+// If hedging is enabled (see WithHedgeDelay), the idempotent requests may be sent twice.
 //
 //nolint:gocognit
 func (c *Client) Do(r *http.Request) (*http.Response, error) {
@@ -58,7 +66,10 @@ func (c *Client) Do(r *http.Request) (*http.Response, error) {
 	l := logging.FromContext(ctx).With(zap.String(c.logPrefix+"component", c.component))
 	debug := l.Check(zap.DebugLevel, "debug") != nil
 
-	var err error
+	var (
+		resp *http.Response
+		err  error
+	)
 
 	defer func() {
 		resTime := time.Now().UTC()
@@ -83,8 +94,37 @@ func (c *Client) Do(r *http.Request) (*http.Response, error) {
 	reqID := traceid.FromContext(ctx, uidc.NewID128())
 	ctx = traceid.NewContext(ctx, reqID)
 	r.Header.Set(c.traceIDHeaderName, reqID)
+
+	ctx, span := tracing.Start(
+		ctx,
+		r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("server.address", r.URL.Host),
+			attribute.String("url.full", r.URL.Redacted()),
+			attribute.String(traceid.DefaultLogKey, reqID),
+		),
+	)
+
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+
+		tracing.End(span, err)
+	}()
+
+	tracing.Inject(ctx, propagation.HeaderCarrier(r.Header))
 	r = r.WithContext(ctx)
 
+	if sc := span.SpanContext(); sc.IsValid() {
+		l = l.With(
+			zap.String(c.logPrefix+logging.TraceIDLogKey, sc.TraceID().String()),
+			zap.String(c.logPrefix+logging.SpanIDLogKey, sc.SpanID().String()),
+		)
+	}
+
 	l = l.With(
 		zap.String(c.logPrefix+traceid.DefaultLogKey, reqID),
 		zap.Time(c.logPrefix+"request_time", reqTime),
@@ -103,9 +143,11 @@ func (c *Client) Do(r *http.Request) (*http.Response, error) {
 		}
 	}
 
-	var resp *http.Response
-
-	resp, err = c.client.Do(r)
+	if c.hedge.enabled(r) {
+		resp, err = c.doHedged(r)
+	} else {
+		resp, err = c.client.Do(r)
+	}
 
 	if debug && resp != nil {
 		respDump, errd := httputil.DumpResponse(resp, true)
@@ -0,0 +1,312 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+)
+
+const (
+	// DefaultHedgeMaxInFlight is the default maximum number of hedge requests in flight.
+	DefaultHedgeMaxInFlight = 10
+
+	// DefaultHedgeDelay is the hedge delay used with WithHedgePercentile until
+	// enough latency samples are collected, if WithHedgeDelay is not set.
+	DefaultHedgeDelay = 1 * time.Second
+
+	// hedgeMinSamples is the minimum number of latency samples required to use the learned hedge delay.
+	hedgeMinSamples = 10
+
+	// hedgeOperation is the operation name used to count the rejected hedge requests as errors with the metrics client.
+	hedgeOperation = "hedge"
+
+	// hedgeRejected is the error code of the hedge requests rejected because the in-flight budget is exhausted.
+	hedgeRejected = "rejected"
+)
+
+// Hedge events counted with the hedge counter.
+const (
+	hedgeSent = "sent"
+	hedgeWon  = "won"
+)
+
+// hedgeMethods are the default HTTP methods that can be hedged.
+var hedgeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+// hedger contains the configuration and state of the hedged requests.
+type hedger struct {
+	delay       time.Duration
+	percentile  float64
+	maxInFlight int64
+	methods     []string
+	metric      metrics.Client
+	counter     metrics.Counter
+	inFlight    atomic.Int64
+
+	mux     sync.Mutex
+	samples []time.Duration
+	next    int
+	count   int
+}
+
+// hedgeResult is the result of one of the hedged requests.
+type hedgeResult struct {
+	resp    *http.Response
+	err     error
+	cancel  context.CancelFunc
+	latency time.Duration
+	idx     int // 0 for the original request, 1 for the hedge request
+}
+
+func newHedger() *hedger {
+	return &hedger{
+		maxInFlight: DefaultHedgeMaxInFlight,
+		methods:     hedgeMethods,
+		metric:      &metrics.Default{},
+	}
+}
+
+// incCounter increments the hedge counter for the event, if set.
+func (h *hedger) incCounter(component, event string) {
+	if h.counter != nil {
+		h.counter.Inc(component, event)
+	}
+}
+
+// enabled returns true if the request can be hedged.
+// Only the idempotent methods with a replayable body can be hedged.
+func (h *hedger) enabled(r *http.Request) bool {
+	if h == nil || (h.delay <= 0 && h.percentile <= 0) || !slices.Contains(h.methods, r.Method) {
+		return false
+	}
+
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
+
+// hedgeDelay returns the time to wait before sending the hedge request:
+// the learned latency percentile if available, or the fixed delay.
+func (h *hedger) hedgeDelay() time.Duration {
+	if h.percentile <= 0 {
+		return h.delay
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.count < min(hedgeMinSamples, len(h.samples)) {
+		if h.delay <= 0 {
+			return DefaultHedgeDelay
+		}
+
+		return h.delay
+	}
+
+	sorted := slices.Clone(h.samples[:h.count])
+	slices.Sort(sorted)
+
+	idx := int(math.Ceil(h.percentile/100*float64(len(sorted)))) - 1
+
+	return sorted[max(idx, 0)]
+}
+
+// observe records the latency of a successful request.
+func (h *hedger) observe(d time.Duration) {
+	if h.percentile <= 0 {
+		return
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.samples[h.next] = d
+	h.next = (h.next + 1) % len(h.samples)
+	h.count = min(h.count+1, len(h.samples))
+}
+
+// acquire reserves a slot in the hedge in-flight budget.
+func (h *hedger) acquire() bool {
+	if h.inFlight.Add(1) > h.maxInFlight {
+		h.inFlight.Add(-1)
+		return false
+	}
+
+	return true
+}
+
+func (h *hedger) release() {
+	h.inFlight.Add(-1)
+}
+
+// doHedged sends the request and, if no response is received within the hedge delay,
+// a second identical request. The first successful response is returned and the other request is canceled.
+// A transport error or a 5xx response is returned only if no other request is pending.
+func (c *Client) doHedged(r *http.Request) (*http.Response, error) {
+	h := c.hedge
+	results := make(chan hedgeResult, 2)
+	pending := 1
+	cancels := []context.CancelFunc{c.sendHedged(r, results, 0)}
+
+	timer := time.NewTimer(h.hedgeDelay())
+	defer timer.Stop()
+
+	var last hedgeResult
+
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if !h.acquire() {
+				h.metric.IncErrorCounter(c.component, hedgeOperation, hedgeRejected)
+				continue
+			}
+
+			hr, err := cloneHedgeRequest(r)
+			if err != nil {
+				h.release()
+				continue
+			}
+
+			h.incCounter(c.component, hedgeSent)
+
+			pending++
+
+			cancels = append(cancels, c.sendHedged(hr, results, 1))
+		case res := <-results:
+			pending--
+
+			if res.err != nil || (pending > 0 && isServerError(res.resp)) {
+				// wait for the other request, but keep the last failed response as fallback
+				if res.resp == nil && last.resp != nil {
+					res.cancel()
+					continue
+				}
+
+				discardHedgeResult(last)
+				last = res
+
+				if res.resp == nil {
+					res.cancel()
+				}
+
+				continue
+			}
+
+			discardHedgeResult(last)
+
+			if !isServerError(res.resp) {
+				h.observe(res.latency)
+			}
+
+			if res.idx > 0 {
+				h.incCounter(c.component, hedgeWon)
+			}
+
+			timer.Stop()
+
+			for i, cancel := range cancels {
+				if i != res.idx {
+					cancel()
+				}
+			}
+
+			go discardHedgeResults(results, pending)
+
+			// the request context is canceled when the response body is closed
+			res.resp.Body = &cancelOnCloseBody{ReadCloser: res.resp.Body, cancel: res.cancel}
+
+			return res.resp, nil
+		}
+	}
+
+	if last.resp != nil {
+		last.resp.Body = &cancelOnCloseBody{ReadCloser: last.resp.Body, cancel: last.cancel}
+
+		return last.resp, nil
+	}
+
+	return nil, last.err
+}
+
+// isServerError returns true if the response has a 5xx status code.
+func isServerError(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// sendHedged sends the request in background with a cancelable context, and returns the cancel function.
+func (c *Client) sendHedged(r *http.Request, results chan<- hedgeResult, idx int) context.CancelFunc {
+	ctx, cancel := context.WithCancel(r.Context())
+	r = r.WithContext(ctx)
+
+	go func() {
+		if idx > 0 {
+			defer c.hedge.release()
+		}
+
+		start := time.Now()
+		resp, err := c.client.Do(r) //nolint:bodyclose
+
+		results <- hedgeResult{
+			resp:    resp,
+			err:     err,
+			cancel:  cancel,
+			latency: time.Since(start),
+			idx:     idx,
+		}
+	}()
+
+	return cancel
+}
+
+// cloneHedgeRequest returns a copy of the request with a new body.
+func cloneHedgeRequest(r *http.Request) (*http.Request, error) {
+	hr := r.Clone(r.Context())
+
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		hr.Body = body
+	}
+
+	return hr, nil
+}
+
+// discardHedgeResults waits for the canceled requests and closes their responses.
+func discardHedgeResults(results <-chan hedgeResult, pending int) {
+	for range pending {
+		discardHedgeResult(<-results)
+	}
+}
+
+// discardHedgeResult closes the response, if any, and cancels the request.
+func discardHedgeResult(res hedgeResult) {
+	if res.resp != nil {
+		_ = res.resp.Body.Close()
+	}
+
+	if res.cancel != nil {
+		res.cancel()
+	}
+}
+
+// cancelOnCloseBody cancels the request context when the response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.cancel()
+
+	return err //nolint:wrapcheck
+}
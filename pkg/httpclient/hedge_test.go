@@ -0,0 +1,406 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+type testHedgeMetrics struct {
+	metrics.Default
+
+	mux    sync.Mutex
+	events []string
+}
+
+func (m *testHedgeMetrics) IncErrorCounter(task, operation, code string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.events = append(m.events, task+" "+operation+" "+code)
+}
+
+func (m *testHedgeMetrics) Events() []string {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.events
+}
+
+type testHedgeCounter struct {
+	mux    sync.Mutex
+	events []string
+}
+
+func (m *testHedgeCounter) Inc(labelValues ...string) {
+	m.Add(1, labelValues...)
+}
+
+func (m *testHedgeCounter) Add(_ float64, labelValues ...string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.events = append(m.events, strings.Join(labelValues, " "))
+}
+
+func (m *testHedgeCounter) Events() []string {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.events
+}
+
+// newHedgeTestServer returns a server where the first request is slow and the following ones are fast.
+func newHedgeTestServer(t *testing.T, slow time.Duration) (*httptest.Server, chan struct{}) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	canceled := make(chan struct{}, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				canceled <- struct{}{}
+				return
+			case <-time.After(slow):
+			}
+
+			_, _ = w.Write([]byte("slow"))
+
+			return
+		}
+
+		_, _ = w.Write(append([]byte("fast"), body...))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, canceled
+}
+
+func TestClient_Do_hedge(t *testing.T) {
+	t.Parallel()
+
+	srv, canceled := newHedgeTestServer(t, 5*time.Second)
+	m := &testHedgeMetrics{}
+	counter := &testHedgeCounter{}
+
+	c := New(
+		withTestTransport(),
+		WithComponent("test"),
+		WithHedgeDelay(100*time.Millisecond),
+		WithHedgeMetrics(m),
+		WithHedgeCounter(counter),
+		WithHedgeMethods(http.MethodGet, http.MethodPut),
+	)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPut, srv.URL, strings.NewReader("-body"))
+	require.NoError(t, err)
+
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "fast-body", string(body))
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the slow request was not canceled")
+	}
+
+	require.Equal(t, []string{"test sent", "test won"}, counter.Events())
+	require.Empty(t, m.Events(), "the sent and won hedge requests are not errors")
+	require.Eventually(t, func() bool { return c.hedge.inFlight.Load() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestClient_Do_hedgeNotNeeded(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newHedgeTestServer(t, 0)
+	m := &testHedgeMetrics{}
+	counter := &testHedgeCounter{}
+
+	c := New(withTestTransport(), WithHedgeDelay(time.Second), WithHedgeMetrics(m), WithHedgeCounter(counter))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "slow", string(body))
+	require.Empty(t, m.Events())
+	require.Empty(t, counter.Events())
+}
+
+func TestClient_Do_hedgeBudget(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newHedgeTestServer(t, 100*time.Millisecond)
+	m := &testHedgeMetrics{}
+	counter := &testHedgeCounter{}
+
+	c := New(withTestTransport(), WithHedgeDelay(10*time.Millisecond), WithHedgeMaxInFlight(0), WithHedgeMetrics(m), WithHedgeCounter(counter))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "slow", string(body))
+	require.Equal(t, []string{"- hedge rejected"}, m.Events())
+	require.Empty(t, counter.Events())
+}
+
+func TestClient_Do_hedgeErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	errTransport := errors.New("transport error")
+
+	slowErr := func(_ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				time.Sleep(50 * time.Millisecond)
+			}
+
+			return nil, errTransport
+		})
+	}
+
+	c := New(WithHedgeDelay(10*time.Millisecond), WithRoundTripper(slowErr))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, errTransport)
+	require.Nil(t, resp)
+	require.Equal(t, int32(2), calls.Load())
+
+	// the primary request fails before the hedge delay
+	c = New(WithHedgeDelay(time.Second), WithRoundTripper(slowErr))
+
+	resp, err = c.Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, errTransport)
+	require.Nil(t, resp)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_Do_hedgeServerError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		slowStatus int
+		fastStatus int
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "fast server error",
+			slowStatus: http.StatusOK,
+			fastStatus: http.StatusServiceUnavailable,
+			wantStatus: http.StatusOK,
+			wantBody:   "slow",
+		},
+		{
+			name:       "both server errors",
+			slowStatus: http.StatusBadGateway,
+			fastStatus: http.StatusServiceUnavailable,
+			wantStatus: http.StatusBadGateway,
+			wantBody:   "slow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if calls.Add(1) == 1 {
+					time.Sleep(100 * time.Millisecond)
+					w.WriteHeader(tt.slowStatus)
+					_, _ = w.Write([]byte("slow"))
+
+					return
+				}
+
+				w.WriteHeader(tt.fastStatus)
+				_, _ = w.Write([]byte("fast"))
+			}))
+			defer srv.Close()
+
+			c := New(withTestTransport(), WithHedgeDelay(10*time.Millisecond))
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+
+			resp, err := c.Do(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantBody, string(body))
+			require.Equal(t, int32(2), calls.Load())
+		})
+	}
+}
+
+func TestClient_Do_hedgeServerErrorAndTransportError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	errTransport := errors.New("transport error")
+
+	rt := func(_ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				time.Sleep(50 * time.Millisecond)
+				return nil, errTransport
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader("unavailable")),
+			}, nil
+		})
+	}
+
+	c := New(WithHedgeDelay(10*time.Millisecond), WithRoundTripper(rt))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	// the failed response is preferred to the transport error
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func Test_hedger_enabled(t *testing.T) {
+	t.Parallel()
+
+	var h *hedger
+
+	get, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	require.False(t, h.enabled(get))
+
+	h = newHedger()
+	require.False(t, h.enabled(get))
+
+	h.delay = time.Second
+	require.True(t, h.enabled(get))
+
+	post, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/", nil)
+	require.NoError(t, err)
+	require.False(t, h.enabled(post))
+
+	h.methods = []string{http.MethodPost}
+
+	post, err = http.NewRequestWithContext(t.Context(), http.MethodPost, "/", io.MultiReader(strings.NewReader("stream")))
+	require.NoError(t, err)
+	require.False(t, h.enabled(post))
+}
+
+func Test_hedger_hedgeDelay(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	WithHedgeDelay(time.Second)(c)
+	WithHedgePercentile(90, 20)(c)
+
+	h := c.hedge
+
+	// not enough samples
+	for i := range 9 {
+		h.observe(time.Duration(i+1) * time.Millisecond)
+	}
+
+	require.Equal(t, time.Second, h.hedgeDelay())
+
+	h.observe(10 * time.Millisecond)
+	require.Equal(t, 9*time.Millisecond, h.hedgeDelay())
+
+	// the oldest samples are replaced
+	for range 20 {
+		h.observe(100 * time.Millisecond)
+	}
+
+	require.Equal(t, 100*time.Millisecond, h.hedgeDelay())
+
+	h.percentile = 0
+	h.observe(time.Hour)
+	require.Equal(t, time.Second, h.hedgeDelay())
+
+	// only the percentile is set
+	c = &Client{}
+	WithHedgePercentile(90, 20)(c)
+
+	require.Equal(t, DefaultHedgeDelay, c.hedge.hedgeDelay())
+}
+
+func Test_cloneHedgeRequest(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPut, "/", strings.NewReader("body"))
+	require.NoError(t, err)
+
+	hr, err := cloneHedgeRequest(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(hr.Body)
+	require.NoError(t, err)
+	require.Equal(t, "body", string(body))
+
+	req.GetBody = func() (io.ReadCloser, error) { return nil, errors.New("error") }
+
+	_, err = cloneHedgeRequest(req)
+	require.Error(t, err)
+}
+
+// withTestTransport uses a dedicated transport, as other tests modify the default one.
+func withTestTransport() Option {
+	return WithRoundTripper(func(_ http.RoundTripper) http.RoundTripper {
+		return &http.Transport{}
+	})
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
@@ -3,5 +3,14 @@ Package httpclient provides a configurable and instrumented HTTP client with
 common options. It includes support for trace ID headers and common logging
 capabilities, such as the ability to dump redacted request and response
 messages.
+
+The client can also send hedged requests to reduce the tail latency of the
+idempotent requests against replicated backends: if no response is received
+within a fixed delay (WithHedgeDelay) or a latency percentile learned from the
+recent requests (WithHedgePercentile), a second identical request is sent and
+the first successful response is returned, while the other request is
+canceled. The number of hedge requests in flight is limited by
+WithHedgeMaxInFlight, and the hedge events can be counted with
+WithHedgeMetrics.
 */
 package httpclient
@@ -5,6 +5,8 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
 )
 
 // InstrumentRoundTripper is an alias for a RoundTripper function.
@@ -63,12 +65,89 @@ func WithLogPrefix(prefix string) Option {
 
 // WithDialContext sets the DialContext function for the HTTP client.
 // The DialContext function is used to establish network connections.
-// It allows customizing the behavior of the client's underlying transport.
+// It allows customizing the behavior of the client's underlying transport
+// (e.g. github.com/Vonage/gosrvlib/pkg/dnscache.Balancer.DialContext).
+// The transport is cloned, so the shared http.DefaultTransport is never modified.
 func WithDialContext(fn DialContextFunc) Option {
 	return func(c *Client) {
 		t, ok := c.client.Transport.(*http.Transport)
 		if ok {
+			t = t.Clone()
 			t.DialContext = fn
+			c.client.Transport = t
 		}
 	}
 }
+
+// WithHedgeDelay enables the hedged requests to reduce the tail latency.
+// If no response is received within the delay, a second identical request is
+// sent and the first successful (non-5xx) response is returned, while the other
+// request is canceled. Only the idempotent GET, HEAD and OPTIONS requests are hedged
+// (see WithHedgeMethods), and only if the body is nil or can be obtained
+// again via the GetBody function.
+// With WithHedgePercentile the delay is only used until enough latency samples are collected.
+func WithHedgeDelay(delay time.Duration) Option {
+	return func(c *Client) {
+		c.hedger().delay = delay
+	}
+}
+
+// WithHedgePercentile enables the hedged requests (see WithHedgeDelay) with
+// a delay equal to the specified latency percentile (e.g. 95) of the last
+// successful requests. The samples parameter is the number of recent latencies to keep.
+// Until enough samples are collected, the WithHedgeDelay value is used, or DefaultHedgeDelay if not set.
+func WithHedgePercentile(percentile float64, samples int) Option {
+	return func(c *Client) {
+		h := c.hedger()
+		h.percentile = min(max(percentile, 0), 100)
+		h.samples = make([]time.Duration, max(samples, 1))
+		h.next = 0
+		h.count = 0
+	}
+}
+
+// WithHedgeMaxInFlight sets the maximum number of hedge requests in flight
+// for the client (default DefaultHedgeMaxInFlight).
+// When the budget is exhausted the requests are not hedged.
+func WithHedgeMaxInFlight(n int) Option {
+	return func(c *Client) {
+		c.hedger().maxInFlight = int64(n)
+	}
+}
+
+// WithHedgeMethods sets the HTTP methods of the requests that can be hedged (default GET, HEAD and OPTIONS).
+func WithHedgeMethods(methods ...string) Option {
+	return func(c *Client) {
+		c.hedger().methods = methods
+	}
+}
+
+// WithHedgeMetrics sets the metrics client used to count the rejected hedge requests.
+// The hedge requests not sent because the in-flight budget is exhausted are counted
+// as errors with IncErrorCounter("<component>", "hedge", "rejected").
+// See WithHedgeCounter to count the sent and won hedge requests.
+func WithHedgeMetrics(m metrics.Client) Option {
+	return func(c *Client) {
+		c.hedger().metric = m
+	}
+}
+
+// WithHedgeCounter sets the counter used to count the hedge requests.
+// The counter must be registered with the "component" and "event" label names
+// (e.g. metrics.Client.Counter("http_client_hedge_total", "Hedged HTTP requests.", "component", "event")),
+// and can be shared by multiple clients.
+// The event is "sent" for the hedge requests and "won" when the hedge response is returned.
+func WithHedgeCounter(counter metrics.Counter) Option {
+	return func(c *Client) {
+		c.hedger().counter = counter
+	}
+}
+
+// hedger returns the hedge configuration, creating it if required.
+func (c *Client) hedger() *hedger {
+	if c.hedge == nil {
+		c.hedge = newHedger()
+	}
+
+	return c.hedge
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/metrics"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,3 +81,59 @@ func TestWithDialContext(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, out)
 }
+
+func TestWithHedgeDelay(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	WithHedgeDelay(50 * time.Millisecond)(c)
+	require.Equal(t, 50*time.Millisecond, c.hedge.delay)
+	require.Equal(t, int64(DefaultHedgeMaxInFlight), c.hedge.maxInFlight)
+}
+
+func TestWithHedgePercentile(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	WithHedgePercentile(95, 100)(c)
+	require.InDelta(t, 95, c.hedge.percentile, 0)
+	require.Len(t, c.hedge.samples, 100)
+
+	WithHedgePercentile(101, 0)(c)
+	require.InDelta(t, 100, c.hedge.percentile, 0)
+	require.Len(t, c.hedge.samples, 1)
+}
+
+func TestWithHedgeMaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	WithHedgeMaxInFlight(3)(c)
+	require.Equal(t, int64(3), c.hedge.maxInFlight)
+}
+
+func TestWithHedgeMethods(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	WithHedgeMethods(http.MethodPut)(c)
+	require.Equal(t, []string{http.MethodPut}, c.hedge.methods)
+}
+
+func TestWithHedgeMetrics(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	m := &metrics.Default{}
+	WithHedgeMetrics(m)(c)
+	require.Equal(t, m, c.hedge.metric)
+}
+
+func TestWithHedgeCounter(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	m := &testHedgeCounter{}
+	WithHedgeCounter(m)(c)
+	require.Equal(t, m, c.hedge.counter)
+}
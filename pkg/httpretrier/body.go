@@ -0,0 +1,141 @@
+package httpretrier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBodyMemoryLimit is the default maximum size of a request body buffered in memory.
+	DefaultBodyMemoryLimit = 1 << 20 // 1 MiB
+
+	// DefaultBodyMaxSize is the default maximum size of a replayable request body.
+	DefaultBodyMaxSize = 100 << 20 // 100 MiB
+)
+
+// ErrBodyTooLarge is the error wrapped by BodyReplayError when the request body exceeds the maximum size.
+var ErrBodyTooLarge = errors.New("request body exceeds the maximum replayable size")
+
+// BodyReplayError is returned when the request body cannot be replayed for the retries.
+// The request is not sent when the body cannot be buffered.
+type BodyReplayError struct {
+	Err error
+}
+
+// Error returns the error message.
+func (e *BodyReplayError) Error() string {
+	return "request body cannot be replayed: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *BodyReplayError) Unwrap() error {
+	return e.Err
+}
+
+// replayableBody contains a copy of a request body that can be read multiple times.
+type replayableBody struct {
+	data []byte
+	file *os.File
+	size int64
+}
+
+// prepareBody makes the request body replayable when the request does not provide the GetBody function.
+// The body is buffered in memory up to the memory limit, and in a temporary file beyond it.
+// The returned function must be called to release the resources:
+// it restores the original request body fields, so the request never refers to the removed temporary file.
+func (c *HTTPRetrier) prepareBody(r *http.Request) (func(), error) {
+	if r.Body == nil || r.Body == http.NoBody || r.GetBody != nil {
+		return func() {}, nil
+	}
+
+	body, contentLength := r.Body, r.ContentLength
+
+	rb, err := c.newReplayableBody(r.Context(), body)
+
+	logging.Close(r.Context(), body, "error while closing request body")
+
+	if err != nil {
+		return nil, &BodyReplayError{Err: err}
+	}
+
+	r.GetBody = rb.reader
+	r.Body, _ = rb.reader() // never returns an error
+	r.ContentLength = rb.size
+
+	return func() {
+		r.GetBody = nil
+		r.Body = body
+		r.ContentLength = contentLength
+
+		rb.close(r.Context())
+	}, nil
+}
+
+// newReplayableBody reads the whole body into memory or into a temporary file.
+func (c *HTTPRetrier) newReplayableBody(ctx context.Context, body io.Reader) (*replayableBody, error) {
+	buf := &bytes.Buffer{}
+
+	n, err := io.CopyN(buf, body, c.bodyMemoryLimit+1)
+	if errors.Is(err, io.EOF) {
+		if n > c.bodyMaxSize {
+			return nil, ErrBodyTooLarge
+		}
+
+		return &replayableBody{data: buf.Bytes(), size: n}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error while reading request body: %w", err)
+	}
+
+	file, err := os.CreateTemp(c.bodyTempDir, "httpretrier-body-*")
+	if err != nil {
+		return nil, fmt.Errorf("error while creating request body temporary file: %w", err)
+	}
+
+	rb := &replayableBody{file: file}
+
+	// read one byte more than the limit to detect larger bodies
+	rb.size, err = io.Copy(file, io.LimitReader(io.MultiReader(buf, body), c.bodyMaxSize+1))
+	if err == nil && rb.size > c.bodyMaxSize {
+		err = ErrBodyTooLarge
+	}
+
+	if err != nil {
+		rb.close(ctx)
+		return nil, fmt.Errorf("error while buffering request body: %w", err)
+	}
+
+	return rb, nil
+}
+
+// reader returns a new reader of the body content.
+func (rb *replayableBody) reader() (io.ReadCloser, error) {
+	if rb.file != nil {
+		return io.NopCloser(io.NewSectionReader(rb.file, 0, rb.size)), nil
+	}
+
+	return io.NopCloser(bytes.NewReader(rb.data)), nil
+}
+
+// close removes the temporary file, if any.
+func (rb *replayableBody) close(ctx context.Context) {
+	if rb.file == nil {
+		return
+	}
+
+	logging.Close(ctx, rb.file, "error while closing request body temporary file")
+
+	err := os.Remove(rb.file.Name())
+	if err != nil {
+		logging.FromContext(ctx).Error("error while removing request body temporary file", zap.Error(err))
+	}
+}
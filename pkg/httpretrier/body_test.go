@@ -0,0 +1,216 @@
+package httpretrier
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBodyReplayError(t *testing.T) {
+	t.Parallel()
+
+	err := error(&BodyReplayError{Err: ErrBodyTooLarge})
+	require.Equal(t, "request body cannot be replayed: request body exceeds the maximum replayable size", err.Error())
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+
+	var rerr *BodyReplayError
+
+	require.ErrorAs(t, err, &rerr)
+}
+
+func TestHTTPRetrier_prepareBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		body        func() io.Reader
+		memoryLimit int64
+		maxSize     int64
+		tempDir     func(t *testing.T) string
+		wantFiles   int
+		wantErr     error
+	}{
+		{
+			name:        "memory",
+			body:        func() io.Reader { return io.MultiReader(strings.NewReader("0123456789")) },
+			memoryLimit: 10,
+			maxSize:     20,
+		},
+		{
+			name:        "temporary file",
+			body:        func() io.Reader { return io.MultiReader(strings.NewReader("0123456789")) },
+			memoryLimit: 4,
+			maxSize:     10,
+			wantFiles:   1,
+		},
+		{
+			name:        "too large in memory",
+			body:        func() io.Reader { return io.MultiReader(strings.NewReader("0123456789")) },
+			memoryLimit: 20,
+			maxSize:     5,
+			wantErr:     ErrBodyTooLarge,
+		},
+		{
+			name:        "too large in temporary file",
+			body:        func() io.Reader { return io.MultiReader(strings.NewReader("0123456789")) },
+			memoryLimit: 4,
+			maxSize:     9,
+			wantErr:     ErrBodyTooLarge,
+		},
+		{
+			name:        "read error",
+			body:        func() io.Reader { return iotest.ErrReader(io.ErrUnexpectedEOF) },
+			memoryLimit: 4,
+			maxSize:     9,
+			wantErr:     io.ErrUnexpectedEOF,
+		},
+		{
+			name:        "temporary file error",
+			body:        func() io.Reader { return io.MultiReader(strings.NewReader("0123456789")) },
+			memoryLimit: 4,
+			maxSize:     10,
+			tempDir:     func(t *testing.T) string { t.Helper(); return filepath.Join(t.TempDir(), "missing") },
+			wantErr:     os.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if tt.tempDir != nil {
+				dir = tt.tempDir(t)
+			}
+
+			c, err := New(nil, WithBodyMemoryLimit(tt.memoryLimit), WithBodyMaxSize(tt.maxSize), WithBodyTempDir(dir))
+			require.NoError(t, err)
+
+			r, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/", tt.body())
+			require.NoError(t, err)
+			require.Nil(t, r.GetBody)
+
+			body, contentLength := r.Body, r.ContentLength
+
+			release, err := c.prepareBody(r)
+			if tt.wantErr != nil {
+				var rerr *BodyReplayError
+
+				require.ErrorAs(t, err, &rerr)
+				require.ErrorIs(t, err, tt.wantErr)
+
+				files, _ := os.ReadDir(dir)
+				require.Empty(t, files)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(10), r.ContentLength)
+
+			files, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			require.Len(t, files, tt.wantFiles)
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, "0123456789", string(data))
+
+			for range 2 {
+				body, err := r.GetBody()
+				require.NoError(t, err)
+
+				data, err := io.ReadAll(body)
+				require.NoError(t, err)
+				require.Equal(t, "0123456789", string(data))
+			}
+
+			release()
+
+			files, err = os.ReadDir(dir)
+			require.NoError(t, err)
+			require.Empty(t, files)
+
+			// the original request fields are restored
+			require.Nil(t, r.GetBody)
+			require.Equal(t, body, r.Body)
+			require.Equal(t, contentLength, r.ContentLength)
+		})
+	}
+}
+
+func TestHTTPRetrier_prepareBody_noop(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	for _, body := range []io.Reader{nil, http.NoBody, bytes.NewReader([]byte("rewindable"))} {
+		r, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/", body)
+		require.NoError(t, err)
+
+		getBody := r.GetBody
+
+		release, err := c.prepareBody(r)
+		require.NoError(t, err)
+
+		release()
+
+		require.Equal(t, getBody == nil, r.GetBody == nil)
+	}
+}
+
+func TestHTTPRetrier_Do_streamedBody(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockHTTP := NewMockHTTPClient(ctrl)
+
+	var bodies []string
+
+	mockHTTP.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		bodies = append(bodies, string(data))
+
+		return nil, errors.New("network error")
+	}).Times(3)
+
+	c, err := New(mockHTTP,
+		WithAttempts(3),
+		WithDelay(time.Millisecond),
+		WithJitter(time.Millisecond),
+		WithBodyMemoryLimit(2),
+		WithBodyTempDir(t.TempDir()),
+	)
+	require.NoError(t, err)
+
+	r, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/", io.MultiReader(strings.NewReader("streamed")))
+	require.NoError(t, err)
+
+	resp, err := c.Do(r) //nolint:bodyclose
+	require.Error(t, err)
+	require.Nil(t, resp)
+	require.Equal(t, []string{"streamed", "streamed", "streamed"}, bodies)
+
+	// the body cannot be replayed
+	c, err = New(mockHTTP, WithBodyMaxSize(2))
+	require.NoError(t, err)
+
+	r, err = http.NewRequestWithContext(t.Context(), http.MethodPost, "/", io.MultiReader(strings.NewReader("streamed")))
+	require.NoError(t, err)
+
+	resp, err = c.Do(r) //nolint:bodyclose
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+	require.Nil(t, resp)
+}
@@ -15,7 +15,24 @@ cases:
 Additionally, it allows you to set the maximum number of retries, the delay
 after the first failed attempt, the time multiplication factor to determine the
 successive delay value, and the jitter used to introduce randomness and avoid
-request collisions.
+request collisions. The jitter can be added to the exponential delay (default),
+or applied with the "full" or "decorrelated" strategies (see
+WithJitterStrategy).
+
+The 429 (Too Many Requests) and 503 (Service Unavailable) responses containing
+the "Retry-After" header (in seconds or as HTTP-date), or the
+"X-RateLimit-Reset" header, override the computed delay with the time
+requested by the server. The WithMaxDelay and WithMaxRetryTime options cap the
+single delay and the total retry time respectively: the retrier stops and
+returns the last response instead of waiting longer. Without WithMaxDelay, the
+delays requested by the server are capped by DefaultMaxServerDelay.
+
+The request bodies without the GetBody function (e.g. streamed bodies or
+custom readers) are buffered before the first attempt, so they can be safely
+sent again with each retry. The bodies larger than the memory limit are stored
+in a temporary file (see WithBodyMemoryLimit, WithBodyMaxSize and
+WithBodyTempDir). A BodyReplayError is returned without sending the request
+when the body cannot be buffered.
 */
 package httpretrier
 
@@ -25,6 +42,8 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/logging"
@@ -42,6 +61,28 @@ const (
 
 	// DefaultJitter is the maximum random Jitter time between retries.
 	DefaultJitter = 100 * time.Millisecond
+
+	// DefaultMaxServerDelay is the maximum delay requested by the server that
+	// is honored when WithMaxDelay is not set: a longer delay stops the retries.
+	DefaultMaxServerDelay = 1 * time.Minute
+
+	// rateLimitResetEpoch is the minimum X-RateLimit-Reset header value interpreted as Unix time instead of seconds.
+	rateLimitResetEpoch = 1_000_000_000
+)
+
+// JitterStrategy defines how the randomness is applied to the retry delays.
+type JitterStrategy int
+
+const (
+	// JitterAdditive adds a random time up to the jitter value to the exponential delay (default).
+	JitterAdditive JitterStrategy = iota
+
+	// JitterFull uses a random delay between zero and the exponential delay.
+	JitterFull
+
+	// JitterDecorrelated uses a random delay between the initial delay and
+	// three times the previous delay, capped by WithMaxDelay.
+	JitterDecorrelated
 )
 
 // RetryIfFn is the signature of the function used to decide when retry.
@@ -58,6 +99,13 @@ type HTTPRetrier struct {
 	delayFactor       float64
 	delay             time.Duration
 	jitter            time.Duration
+	jitterStrategy    JitterStrategy
+	maxDelay          time.Duration
+	maxRetryTime      time.Duration
+	bodyMemoryLimit   int64
+	bodyMaxSize       int64
+	bodyTempDir       string
+	startTime         time.Time
 	attempts          uint
 	remainingAttempts uint
 	retryIfFn         RetryIfFn
@@ -77,6 +125,9 @@ func defaultHTTPRetrier() *HTTPRetrier {
 		jitter:      DefaultJitter,
 		retryIfFn:   defaultRetryIf,
 		resetTimer:  make(chan time.Duration, 1),
+
+		bodyMemoryLimit: DefaultBodyMemoryLimit,
+		bodyMaxSize:     DefaultBodyMaxSize,
 	}
 }
 
@@ -97,9 +148,20 @@ func New(httpClient HTTPClient, opts ...Option) (*HTTPRetrier, error) {
 }
 
 // Do attempts to run the request according to the retry rules.
+// As with http.Client.Do, the request body is consumed and closed:
+// a request without the GetBody function cannot be sent again after Do returns.
 func (c *HTTPRetrier) Do(r *http.Request) (*http.Response, error) {
 	c.nextDelay = float64(c.delay)
 	c.remainingAttempts = c.attempts
+	c.startTime = time.Now()
+
+	release, err := c.prepareBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancel = cancel
 
@@ -201,7 +263,7 @@ func (c *HTTPRetrier) run(r *http.Request) bool {
 	if r.GetBody != nil {
 		bodyRC, err = r.GetBody()
 		if err != nil {
-			c.doError = fmt.Errorf("error while reading request body: %w", err)
+			c.doError = &BodyReplayError{Err: fmt.Errorf("error while reading request body: %w", err)}
 			return true
 		}
 	}
@@ -213,6 +275,11 @@ func (c *HTTPRetrier) run(r *http.Request) bool {
 		return true
 	}
 
+	delay, ok := c.retryDelay()
+	if !ok {
+		return true
+	}
+
 	if c.doError == nil {
 		// we only close the body between attempts
 		logging.Close(r.Context(), c.doResponse.Body, "error while closing response body")
@@ -221,9 +288,96 @@ func (c *HTTPRetrier) run(r *http.Request) bool {
 	// set the original body for the next request
 	r.Body = bodyRC
 
-	c.resetTimer <- time.Duration(int64(c.nextDelay) + rand.Int63n(int64(c.jitter))) //nolint:gosec
-
-	c.nextDelay *= c.delayFactor
+	c.resetTimer <- delay
 
 	return false
 }
+
+// retryDelay returns the delay before the next attempt,
+// or false if the retry limits do not allow waiting.
+func (c *HTTPRetrier) retryDelay() (time.Duration, bool) {
+	delay := c.backoffDelay()
+
+	if d, ok := retryAfterDelay(c.doResponse, time.Now()); ok {
+		maxServerDelay := c.maxDelay
+		if maxServerDelay == 0 {
+			maxServerDelay = DefaultMaxServerDelay
+		}
+
+		if d > maxServerDelay {
+			return 0, false
+		}
+
+		delay = d
+	} else if c.maxDelay > 0 {
+		delay = min(delay, c.maxDelay)
+	}
+
+	if c.maxRetryTime > 0 && time.Since(c.startTime)+delay > c.maxRetryTime {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// backoffDelay returns the next delay computed with the configured jitter strategy.
+//
+//nolint:gosec
+func (c *HTTPRetrier) backoffDelay() time.Duration {
+	var delay time.Duration
+
+	switch c.jitterStrategy {
+	case JitterFull:
+		delay = time.Duration(rand.Int63n(int64(c.nextDelay) + 1))
+		c.nextDelay *= c.delayFactor
+	case JitterDecorrelated:
+		base := int64(c.delay)
+		delay = time.Duration(base + rand.Int63n(max(int64(c.nextDelay*3)-base, 0)+1))
+
+		if c.maxDelay > 0 {
+			delay = min(delay, c.maxDelay)
+		}
+
+		c.nextDelay = float64(delay)
+	default:
+		delay = time.Duration(int64(c.nextDelay) + rand.Int63n(int64(c.jitter)))
+		c.nextDelay *= c.delayFactor
+	}
+
+	return delay
+}
+
+// retryAfterDelay returns the delay requested by the server with the
+// Retry-After or X-RateLimit-Reset headers of a 429 or 503 response.
+// The Retry-After header can contain seconds or an HTTP-date.
+// The X-RateLimit-Reset header can contain seconds or a Unix time in seconds.
+func retryAfterDelay(r *http.Response, now time.Time) (time.Duration, bool) {
+	if r == nil || (r.StatusCode != http.StatusTooManyRequests && r.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	if v := strings.TrimSpace(r.Header.Get("Retry-After")); v != "" {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Duration(max(sec, 0)) * time.Second, true
+		}
+
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+
+	if v := strings.TrimSpace(r.Header.Get("X-RateLimit-Reset")); v != "" {
+		sec, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		if sec >= rateLimitResetEpoch {
+			return max(time.Unix(sec, 0).Sub(now), 0), true
+		}
+
+		return time.Duration(max(sec, 0)) * time.Second, true
+	}
+
+	return 0, false
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
@@ -277,7 +278,9 @@ func TestHTTPRetrier_Do(t *testing.T) {
 		setupMocks            func(mock *MockHTTPClient)
 		ctxTimeout            time.Duration
 		body                  io.Reader
+		opts                  []Option
 		wantRemainingAttempts uint
+		wantStatus            int
 		wantErr               bool
 		requestBodyError      bool
 	}{
@@ -355,6 +358,52 @@ func TestHTTPRetrier_Do(t *testing.T) {
 			wantRemainingAttempts: 3,
 			wantErr:               true,
 		},
+		{
+			name: "success after Retry-After delay",
+			setupMocks: func(mock *MockHTTPClient) {
+				rErr := &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     http.Header{"Retry-After": []string{"0"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte{})),
+				}
+				rOK := &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte{})),
+				}
+				mock.EXPECT().Do(gomock.Any()).Return(rErr, nil)
+				mock.EXPECT().Do(gomock.Any()).Return(rOK, nil)
+			},
+			opts:                  []Option{WithDelay(time.Hour)},
+			wantRemainingAttempts: 2,
+			wantStatus:            http.StatusOK,
+		},
+		{
+			name: "stop when Retry-After exceeds the max delay",
+			setupMocks: func(mock *MockHTTPClient) {
+				rErr := &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{"Retry-After": []string{"10"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte{})),
+				}
+				mock.EXPECT().Do(gomock.Any()).Return(rErr, nil)
+			},
+			opts:                  []Option{WithMaxDelay(time.Second)},
+			wantRemainingAttempts: 3,
+			wantStatus:            http.StatusServiceUnavailable,
+		},
+		{
+			name: "stop when the max retry time is reached",
+			setupMocks: func(mock *MockHTTPClient) {
+				rErr := &http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       io.NopCloser(bytes.NewReader([]byte{})),
+				}
+				mock.EXPECT().Do(gomock.Any()).Return(rErr, nil).Times(2)
+			},
+			opts:                  []Option{WithMaxRetryTime(200 * time.Millisecond)},
+			wantRemainingAttempts: 2,
+			wantStatus:            http.StatusInternalServerError,
+		},
 		{
 			name:                  "request body error",
 			requestBodyError:      true,
@@ -401,7 +450,7 @@ func TestHTTPRetrier_Do(t *testing.T) {
 				WithJitter(50 * time.Millisecond),
 			}
 
-			retrier, err := New(mockHTTP, opts...)
+			retrier, err := New(mockHTTP, append(opts, tt.opts...)...)
 			require.NoError(t, err)
 
 			resp, err := retrier.Do(r)
@@ -409,6 +458,11 @@ func TestHTTPRetrier_Do(t *testing.T) {
 				_ = resp.Body.Close()
 			}
 
+			if tt.wantStatus != 0 {
+				require.NotNil(t, resp)
+				require.Equal(t, tt.wantStatus, resp.StatusCode)
+			}
+
 			require.Equal(t, tt.wantErr, err != nil, "Do() error = %v, wantErr %v", err, tt.wantErr)
 			require.Equal(t, tt.wantRemainingAttempts, retrier.remainingAttempts, "Do() remainingAttempts = %v, wantRemainingAttempts %v", err, tt.wantErr)
 		})
@@ -427,3 +481,224 @@ func TestHTTPRetrier_setTimer(t *testing.T) {
 
 	<-c.timer.C
 }
+
+func TestHTTPRetrier_backoffDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		strategy JitterStrategy
+		maxDelay time.Duration
+		wantMin  []time.Duration
+		wantMax  []time.Duration
+	}{
+		{
+			name:     "additive",
+			strategy: JitterAdditive,
+			wantMin:  []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+			wantMax:  []time.Duration{110 * time.Millisecond, 210 * time.Millisecond, 410 * time.Millisecond},
+		},
+		{
+			name:     "full",
+			strategy: JitterFull,
+			wantMin:  []time.Duration{0, 0, 0},
+			wantMax:  []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:     "decorrelated",
+			strategy: JitterDecorrelated,
+			maxDelay: 250 * time.Millisecond,
+			wantMin:  []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+			wantMax:  []time.Duration{250 * time.Millisecond, 250 * time.Millisecond, 250 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := defaultHTTPRetrier()
+			c.delay = 100 * time.Millisecond
+			c.jitter = 10 * time.Millisecond
+			c.jitterStrategy = tt.strategy
+			c.maxDelay = tt.maxDelay
+			c.nextDelay = float64(c.delay)
+
+			for i := range tt.wantMin {
+				d := c.backoffDelay()
+				require.GreaterOrEqual(t, d, tt.wantMin[i])
+				require.LessOrEqual(t, d, tt.wantMax[i])
+			}
+		})
+	}
+}
+
+func TestHTTPRetrier_retryDelay(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+	c.nextDelay = float64(time.Minute)
+	c.maxDelay = 2 * time.Second
+	c.startTime = time.Now()
+
+	// the computed delay is capped
+	d, ok := c.retryDelay()
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, d)
+
+	// the server delay is honored
+	c.doResponse = &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+	}
+
+	d, ok = c.retryDelay()
+	require.True(t, ok)
+	require.Equal(t, time.Second, d)
+
+	// the max retry time is exceeded
+	c.maxRetryTime = 500 * time.Millisecond
+
+	_, ok = c.retryDelay()
+	require.False(t, ok)
+}
+
+func TestHTTPRetrier_retryDelay_maxServerDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		maxDelay   time.Duration
+		retryAfter string
+		want       time.Duration
+		wantOK     bool
+	}{
+		{
+			name:       "default cap honored",
+			retryAfter: "60",
+			want:       time.Minute,
+			wantOK:     true,
+		},
+		{
+			name:       "default cap exceeded",
+			retryAfter: "86400",
+		},
+		{
+			name:       "max delay exceeded",
+			maxDelay:   2 * time.Hour,
+			retryAfter: "86400",
+		},
+		{
+			name:       "max delay honored",
+			maxDelay:   48 * time.Hour,
+			retryAfter: "86400",
+			want:       24 * time.Hour,
+			wantOK:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := defaultHTTPRetrier()
+			c.nextDelay = float64(time.Second)
+			c.maxDelay = tt.maxDelay
+			c.startTime = time.Now()
+			c.doResponse = &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{tt.retryAfter}},
+			}
+
+			d, ok := c.retryDelay()
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func Test_retryAfterDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "no header",
+			status: http.StatusTooManyRequests,
+		},
+		{
+			name:   "status not supported",
+			status: http.StatusInternalServerError,
+			header: http.Header{"Retry-After": []string{"3"}},
+		},
+		{
+			name:   "retry-after seconds",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"Retry-After": []string{" 3 "}},
+			want:   3 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "retry-after negative seconds",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"Retry-After": []string{"-3"}},
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "retry-after HTTP date",
+			status: http.StatusServiceUnavailable,
+			header: http.Header{"Retry-After": []string{now.Add(90 * time.Second).Format(http.TimeFormat)}},
+			want:   90 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "retry-after past HTTP date",
+			status: http.StatusServiceUnavailable,
+			header: http.Header{"Retry-After": []string{now.Add(-time.Hour).Format(http.TimeFormat)}},
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "invalid retry-after with rate limit reset",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"Retry-After": []string{"invalid"}, "X-Ratelimit-Reset": []string{"5"}},
+			want:   5 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "rate limit reset unix time",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"X-Ratelimit-Reset": []string{strconv.FormatInt(now.Add(time.Minute).Unix(), 10)}},
+			want:   time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "invalid rate limit reset",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"X-Ratelimit-Reset": []string{"invalid"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &http.Response{StatusCode: tt.status, Header: tt.header}
+
+			got, ok := retryAfterDelay(r, now)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := retryAfterDelay(nil, now)
+	require.False(t, ok)
+}
@@ -75,3 +75,84 @@ func WithJitter(jitter time.Duration) Option {
 		return nil
 	}
 }
+
+// WithJitterStrategy sets how the randomness is applied to the retry delays (default JitterAdditive).
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func WithJitterStrategy(strategy JitterStrategy) Option {
+	return func(r *HTTPRetrier) error {
+		if strategy < JitterAdditive || strategy > JitterDecorrelated {
+			return errors.New("invalid jitter strategy")
+		}
+
+		r.jitterStrategy = strategy
+
+		return nil
+	}
+}
+
+// WithMaxDelay sets the maximum delay between two attempts.
+// The computed delays are capped to this value, while a longer delay requested
+// by the server with the Retry-After or X-RateLimit-Reset headers stops the retries.
+// Without this option, the server delays longer than DefaultMaxServerDelay stop the retries.
+func WithMaxDelay(delay time.Duration) Option {
+	return func(r *HTTPRetrier) error {
+		if int64(delay) < 1 {
+			return errors.New("max delay must be greater than zero")
+		}
+
+		r.maxDelay = delay
+
+		return nil
+	}
+}
+
+// WithMaxRetryTime sets the maximum total time spent retrying a request.
+// The retrier stops and returns the last response if the next attempt would start after this limit.
+func WithMaxRetryTime(d time.Duration) Option {
+	return func(r *HTTPRetrier) error {
+		if int64(d) < 1 {
+			return errors.New("max retry time must be greater than zero")
+		}
+
+		r.maxRetryTime = d
+
+		return nil
+	}
+}
+
+// WithBodyMemoryLimit sets the maximum size of a request body buffered in memory (default DefaultBodyMemoryLimit).
+// Larger bodies are stored in a temporary file. The body is only buffered when the request GetBody function is nil.
+func WithBodyMemoryLimit(limit int64) Option {
+	return func(r *HTTPRetrier) error {
+		if limit < 0 {
+			return errors.New("body memory limit must be positive")
+		}
+
+		r.bodyMemoryLimit = limit
+
+		return nil
+	}
+}
+
+// WithBodyMaxSize sets the maximum size of a replayable request body (default DefaultBodyMaxSize).
+// Larger bodies fail with a BodyReplayError wrapping ErrBodyTooLarge.
+func WithBodyMaxSize(size int64) Option {
+	return func(r *HTTPRetrier) error {
+		if size < 1 {
+			return errors.New("body max size must be greater than zero")
+		}
+
+		r.bodyMaxSize = size
+
+		return nil
+	}
+}
+
+// WithBodyTempDir sets the directory of the temporary files used to buffer the large request bodies.
+// The default is the operating system temporary directory.
+func WithBodyTempDir(dir string) Option {
+	return func(r *HTTPRetrier) error {
+		r.bodyTempDir = dir
+		return nil
+	}
+}
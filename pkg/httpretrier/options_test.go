@@ -98,3 +98,83 @@ func TestWithJitter(t *testing.T) {
 	err = WithJitter(v)(c)
 	require.Error(t, err)
 }
+
+func TestWithJitterStrategy(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithJitterStrategy(JitterDecorrelated)(c)
+	require.NoError(t, err)
+	require.Equal(t, JitterDecorrelated, c.jitterStrategy)
+
+	err = WithJitterStrategy(JitterStrategy(-1))(c)
+	require.Error(t, err)
+
+	err = WithJitterStrategy(JitterDecorrelated + 1)(c)
+	require.Error(t, err)
+}
+
+func TestWithMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	v := 7 * time.Second
+	err := WithMaxDelay(v)(c)
+	require.NoError(t, err)
+	require.Equal(t, v, c.maxDelay)
+
+	err = WithMaxDelay(0)(c)
+	require.Error(t, err)
+}
+
+func TestWithMaxRetryTime(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	v := 13 * time.Second
+	err := WithMaxRetryTime(v)(c)
+	require.NoError(t, err)
+	require.Equal(t, v, c.maxRetryTime)
+
+	err = WithMaxRetryTime(0)(c)
+	require.Error(t, err)
+}
+
+func TestWithBodyMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithBodyMemoryLimit(1024)(c)
+	require.NoError(t, err)
+	require.Equal(t, int64(1024), c.bodyMemoryLimit)
+
+	err = WithBodyMemoryLimit(-1)(c)
+	require.Error(t, err)
+}
+
+func TestWithBodyMaxSize(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithBodyMaxSize(2048)(c)
+	require.NoError(t, err)
+	require.Equal(t, int64(2048), c.bodyMaxSize)
+
+	err = WithBodyMaxSize(0)(c)
+	require.Error(t, err)
+}
+
+func TestWithBodyTempDir(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithBodyTempDir("/tmp/test")(c)
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/test", c.bodyTempDir)
+}
@@ -0,0 +1,438 @@
+package httpserver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	// EncodingBrotli is the Content-Encoding value for the Brotli compression.
+	EncodingBrotli = "br"
+
+	// EncodingGzip is the Content-Encoding value for the Gzip compression.
+	EncodingGzip = "gzip"
+
+	// EncodingDeflate is the Content-Encoding value for the Deflate (zlib) compression.
+	EncodingDeflate = "deflate"
+
+	// defaultCompressionMinSize is the default minimum response size in bytes to enable the compression.
+	defaultCompressionMinSize = 1024
+)
+
+// DefaultCompressionContentTypes returns the default list of compressible content types.
+func DefaultCompressionContentTypes() []string {
+	return []string{
+		"application/javascript",
+		"application/json",
+		"application/problem+json",
+		"application/problem+xml",
+		"application/xml",
+		"image/svg+xml",
+		"text/*",
+	}
+}
+
+// CompressionOption is a type alias for a function that configures the Compression.
+type CompressionOption func(c *Compression) error
+
+// Compression is a response compression handler that can be used as MiddlewareFn.
+// The encoding is negotiated with the Accept-Encoding request header.
+type Compression struct {
+	encodings    []string
+	minSize      int
+	contentTypes []string
+	pools        map[string]*sync.Pool
+}
+
+type compressEncoder interface {
+	io.WriteCloser
+
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// NewCompression creates a new response compression handler.
+// By default the Brotli, Gzip and Deflate encodings are enabled in this order of preference.
+func NewCompression(opts ...CompressionOption) (*Compression, error) {
+	c := &Compression{
+		encodings:    []string{EncodingBrotli, EncodingGzip, EncodingDeflate},
+		minSize:      defaultCompressionMinSize,
+		contentTypes: DefaultCompressionContentTypes(),
+	}
+
+	for _, applyOpt := range opts {
+		err := applyOpt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.pools = make(map[string]*sync.Pool, len(c.encodings))
+
+	for _, enc := range c.encodings {
+		newFn := compressEncoderFactory(enc)
+		c.pools[enc] = &sync.Pool{New: func() any { return newFn() }}
+	}
+
+	return c, nil
+}
+
+// WithCompressionEncodings sets the enabled encodings in order of preference.
+// The supported encodings are EncodingBrotli, EncodingGzip and EncodingDeflate.
+func WithCompressionEncodings(encodings ...string) CompressionOption {
+	return func(c *Compression) error {
+		if len(encodings) == 0 {
+			return errors.New("at least one compression encoding is required")
+		}
+
+		for _, enc := range encodings {
+			if compressEncoderFactory(enc) == nil {
+				return fmt.Errorf("unsupported compression encoding: %s", enc)
+			}
+		}
+
+		c.encodings = encodings
+
+		return nil
+	}
+}
+
+// WithCompressionMinSize sets the minimum response size in bytes to enable the compression.
+func WithCompressionMinSize(size int) CompressionOption {
+	return func(c *Compression) error {
+		if size < 0 {
+			return errors.New("invalid compression minSize")
+		}
+
+		c.minSize = size
+
+		return nil
+	}
+}
+
+// WithCompressionContentTypes sets the list of compressible content types.
+// A type ending with "/*" matches all the subtypes (e.g.: "text/*").
+func WithCompressionContentTypes(types ...string) CompressionOption {
+	return func(c *Compression) error {
+		if len(types) == 0 {
+			return errors.New("at least one compression content type is required")
+		}
+
+		c.contentTypes = types
+
+		return nil
+	}
+}
+
+// MiddlewareFn is the middleware handler function that compresses the responses.
+func (c *Compression) MiddlewareFn(_ MiddlewareArgs, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := c.negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			c:              c,
+			encoding:       encoding,
+			status:         http.StatusOK,
+		}
+
+		defer cw.close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate returns the preferred encoding accepted by the client, or an empty string.
+func (c *Compression) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qvalues := make(map[string]float64)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		qvalues[name] = parseQValue(params)
+	}
+
+	var (
+		best  string
+		bestQ float64
+	)
+
+	for _, enc := range c.encodings {
+		q, ok := qvalues[enc]
+		if !ok {
+			q, ok = qvalues["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	return best
+}
+
+func parseQValue(params string) float64 {
+	for _, p := range strings.Split(params, ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		if strings.TrimSpace(k) != "q" {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0
+		}
+
+		return q
+	}
+
+	return 1
+}
+
+func (c *Compression) isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range c.contentTypes {
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compressEncoderFactory(encoding string) func() compressEncoder {
+	switch encoding {
+	case EncodingBrotli:
+		return func() compressEncoder { return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression) }
+	case EncodingGzip:
+		return func() compressEncoder { return gzip.NewWriter(io.Discard) }
+	case EncodingDeflate:
+		return func() compressEncoder { return zlib.NewWriter(io.Discard) }
+	}
+
+	return nil
+}
+
+// compressResponseWriter buffers the response until the compression decision can be made.
+type compressResponseWriter struct {
+	http.ResponseWriter
+
+	c           *Compression
+	encoding    string
+	encoder     compressEncoder
+	buf         []byte
+	status      int
+	decided     bool
+	wroteHeader bool
+}
+
+// Unwrap returns the original http.ResponseWriter (used by http.ResponseController).
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader || cw.decided {
+		return
+	}
+
+	if code >= 100 && code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	cw.status = code
+	cw.wroteHeader = true
+
+	if !bodyAllowedForStatus(code) || code == http.StatusPartialContent {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	cw.wroteHeader = true
+
+	if !cw.decided {
+		if !cw.canCompress(b) {
+			cw.decide(false)
+
+			err := cw.flushBuffer()
+			if err != nil {
+				return 0, err
+			}
+
+			return cw.ResponseWriter.Write(b) //nolint:wrapcheck
+		}
+
+		cw.buf = append(cw.buf, b...)
+
+		if len(cw.buf) < cw.c.minSize {
+			return len(b), nil
+		}
+
+		cw.decide(true)
+
+		return len(b), cw.flushBuffer()
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Write(b) //nolint:wrapcheck
+	}
+
+	return cw.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+// ReadFrom allows the use of io.Copy with the compressing writer.
+func (cw *compressResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(writerOnly{cw}, r) //nolint:wrapcheck
+}
+
+// Flush sends any buffered data to the client.
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		cw.decide(len(cw.buf) > 0 && cw.canCompress(nil))
+		_ = cw.flushBuffer()
+	}
+
+	if cw.encoder != nil {
+		_ = cw.encoder.Flush()
+	}
+
+	if fl, ok := cw.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+	}
+}
+
+//nolint:wrapcheck
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the Hijacker is not supported by the ResponseWriter")
+	}
+
+	return hj.Hijack()
+}
+
+// canCompress checks the response headers and content type.
+func (cw *compressResponseWriter) canCompress(b []byte) bool {
+	h := cw.Header()
+
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" ||
+		strings.Contains(h.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+
+	ct := h.Get("Content-Type")
+	if ct == "" {
+		// detect the content type like the standard http.ResponseWriter would do
+		ct = http.DetectContentType(append(cw.buf, b...))
+		h.Set("Content-Type", ct)
+	}
+
+	return cw.c.isCompressible(ct)
+}
+
+// decide sends the response headers with or without compression.
+func (cw *compressResponseWriter) decide(compress bool) {
+	cw.decided = true
+
+	if compress {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		cw.encoder = cw.c.pools[cw.encoding].Get().(compressEncoder) //nolint:forcetypeassert
+		cw.encoder.Reset(cw.ResponseWriter)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+func (cw *compressResponseWriter) flushBuffer() error {
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	var err error
+
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(cw.buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+
+	cw.buf = nil
+
+	return err //nolint:wrapcheck
+}
+
+// close completes the response and releases the encoder.
+func (cw *compressResponseWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// nothing has been written by the handler
+			return
+		}
+
+		cw.decide(false)
+	}
+
+	_ = cw.flushBuffer()
+
+	if cw.encoder != nil {
+		_ = cw.encoder.Close()
+		cw.encoder.Reset(io.Discard)
+		cw.c.pools[cw.encoding].Put(cw.encoder)
+		cw.encoder = nil
+	}
+}
+
+// writerOnly hides the ReadFrom method to avoid infinite recursion in io.Copy.
+type writerOnly struct {
+	io.Writer
+}
+
+// bodyAllowedForStatus reports whether a given response status code permits a body.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,366 @@
+package httpserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	brotli "github.com/aperturerobotics/go-brotli-decoder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []CompressionOption
+		wantErr bool
+	}{
+		{
+			name: "succeed with defaults",
+		},
+		{
+			name: "succeed with options",
+			opts: []CompressionOption{
+				WithCompressionEncodings(EncodingGzip),
+				WithCompressionMinSize(0),
+				WithCompressionContentTypes("application/json"),
+			},
+		},
+		{
+			name:    "fail with no encodings",
+			opts:    []CompressionOption{WithCompressionEncodings()},
+			wantErr: true,
+		},
+		{
+			name:    "fail with unsupported encoding",
+			opts:    []CompressionOption{WithCompressionEncodings("zstd")},
+			wantErr: true,
+		},
+		{
+			name:    "fail with invalid min size",
+			opts:    []CompressionOption{WithCompressionMinSize(-1)},
+			wantErr: true,
+		},
+		{
+			name:    "fail with no content types",
+			opts:    []CompressionOption{WithCompressionContentTypes()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCompression(tt.opts...)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, c)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, c)
+			require.Len(t, c.pools, len(c.encodings))
+		})
+	}
+}
+
+func TestCompression_negotiate(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCompression()
+	require.NoError(t, err)
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: ""},
+		{accept: "identity", want: ""},
+		{accept: "gzip", want: EncodingGzip},
+		{accept: "gzip, deflate, br", want: EncodingBrotli},
+		{accept: "deflate;q=0.5, gzip;q=0.8", want: EncodingGzip},
+		{accept: "br;q=0, gzip", want: EncodingGzip},
+		{accept: "*", want: EncodingBrotli},
+		{accept: "*;q=0.1, deflate", want: EncodingDeflate},
+		{accept: "gzip;q=invalid", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, c.negotiate(tt.accept))
+		})
+	}
+}
+
+func TestCompression_isCompressible(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCompression()
+	require.NoError(t, err)
+
+	require.True(t, c.isCompressible(httputil.MimeApplicationJSON))
+	require.True(t, c.isCompressible("text/html"))
+	require.False(t, c.isCompressible("image/png"))
+	require.False(t, c.isCompressible("textual/plain"))
+	require.False(t, c.isCompressible(""))
+}
+
+func decodeBody(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var (
+		r   io.Reader
+		err error
+	)
+
+	switch encoding {
+	case EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case EncodingGzip:
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case EncodingDeflate:
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	default:
+		return string(body)
+	}
+
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+//nolint:maintidx
+func TestCompression_MiddlewareFn(t *testing.T) {
+	t.Parallel()
+
+	largeJSON := `{"data":"` + strings.Repeat("a", 2048) + `"}`
+
+	tests := []struct {
+		name         string
+		method       string
+		accept       string
+		handler      http.HandlerFunc
+		wantStatus   int
+		wantEncoding string
+		wantBody     string
+		wantETag     string
+	}{
+		{
+			name:   "gzip large JSON",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"abc"`)
+				httputil.SendText(r.Context(), w, http.StatusOK, largeJSON)
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: EncodingGzip,
+			wantBody:     largeJSON,
+			wantETag:     `W/"abc"`,
+		},
+		{
+			name:   "brotli large JSON in chunks",
+			method: http.MethodGet,
+			accept: "br, gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", httputil.MimeApplicationJSON)
+				w.WriteHeader(http.StatusCreated)
+
+				for i := 0; i < len(largeJSON); i += 100 {
+					_, _ = w.Write([]byte(largeJSON[i:min(i+100, len(largeJSON))]))
+				}
+			},
+			wantStatus:   http.StatusCreated,
+			wantEncoding: EncodingBrotli,
+			wantBody:     largeJSON,
+		},
+		{
+			name:   "deflate with io.Copy through ResponseWriterWrapper",
+			method: http.MethodGet,
+			accept: "deflate",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				rw := httputil.NewResponseWriterWrapper(w)
+				rw.Header().Set("Content-Type", "text/plain")
+				n, err := io.Copy(rw, strings.NewReader(largeJSON))
+				require.NoError(t, err)
+				require.Equal(t, len(largeJSON), int(n))
+				require.Equal(t, len(largeJSON), rw.Size())
+				require.Equal(t, http.StatusOK, rw.Status())
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: EncodingDeflate,
+			wantBody:     largeJSON,
+		},
+		{
+			name:   "small response",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				httputil.SendJSON(r.Context(), w, http.StatusOK, "small")
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "\"small\"\n",
+		},
+		{
+			name:   "not compressible content type",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(largeJSON))
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   largeJSON,
+		},
+		{
+			name:   "detected content type",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(largeJSON))
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: EncodingGzip,
+			wantBody:     largeJSON,
+		},
+		{
+			name:   "already encoded",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Content-Encoding", "custom")
+				_, _ = w.Write([]byte(largeJSON))
+			},
+			wantStatus:   http.StatusOK,
+			wantEncoding: "custom",
+			wantBody:     largeJSON,
+		},
+		{
+			name:   "no content",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "status only",
+			method: http.MethodGet,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:   "no accept encoding",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				httputil.SendText(r.Context(), w, http.StatusOK, largeJSON)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   largeJSON,
+		},
+		{
+			name:   "HEAD request",
+			method: http.MethodHead,
+			accept: "gzip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCompression()
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Encoding", tt.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			c.MiddlewareFn(MiddlewareArgs{}, tt.handler).ServeHTTP(rr, req)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tt.wantEncoding, rr.Header().Get("Content-Encoding"))
+			require.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+			require.Equal(t, tt.wantBody, decodeBody(t, tt.wantEncoding, rr.Body.Bytes()))
+			require.Equal(t, tt.wantETag, rr.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestCompression_MiddlewareFn_flush(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCompression(WithCompressionEncodings(EncodingGzip))
+	require.NoError(t, err)
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		_, _ = w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush() //nolint:forcetypeassert
+
+		_, _ = w.Write([]byte("data: second\n\n"))
+		w.(http.Flusher).Flush() //nolint:forcetypeassert
+
+		_, _, err := w.(http.Hijacker).Hijack() //nolint:forcetypeassert
+		require.Error(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+	c.MiddlewareFn(MiddlewareArgs{}, http.HandlerFunc(handler)).ServeHTTP(rr, req)
+
+	require.True(t, rr.Flushed)
+	require.Equal(t, EncodingGzip, rr.Header().Get("Content-Encoding"))
+	require.Equal(t, "data: first\n\ndata: second\n\n", decodeBody(t, EncodingGzip, rr.Body.Bytes()))
+}
+
+func TestCompression_MiddlewareFn_unwrap(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCompression()
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		uw, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		require.True(t, ok)
+		require.Equal(t, rr, uw.Unwrap())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	c.MiddlewareFn(MiddlewareArgs{}, http.HandlerFunc(handler)).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+}
@@ -3,6 +3,8 @@ package httpserver
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -43,13 +45,22 @@ type config struct {
 	serverAddr                  string
 	traceIDHeaderName           string
 	requestTimeout              time.Duration
+	rateLimiter                 *RateLimiter
+	corsPolicy                  *CORSPolicy
+	compression                 *Compression
 	serverReadHeaderTimeout     time.Duration
 	serverReadTimeout           time.Duration
 	serverWriteTimeout          time.Duration
 	shutdownTimeout             time.Duration
+	shutdownDrainDelay          time.Duration
 	tlsConfig                   *tls.Config
+	tlsClientCAs                *x509.CertPool
+	tlsClientAuth               tls.ClientAuthType
+	enableH2C                   bool
+	enableHTTP3                 bool
 	defaultEnabledRoutes        []DefaultRoute
 	indexHandlerFunc            IndexHandlerFunc
+	openAPIInfo                 OpenAPIInfo
 	ipHandlerFunc               http.HandlerFunc
 	metricsHandlerFunc          http.HandlerFunc
 	pingHandlerFunc             http.HandlerFunc
@@ -64,6 +75,7 @@ type config struct {
 	disableRouteLogger          bool
 	shutdownWaitGroup           *sync.WaitGroup
 	shutdownSignalChan          chan struct{}
+	drain                       *drainState
 }
 
 func defaultConfig() *config {
@@ -77,6 +89,7 @@ func defaultConfig() *config {
 		shutdownTimeout:             30 * time.Second,
 		defaultEnabledRoutes:        nil,
 		indexHandlerFunc:            defaultIndexHandler,
+		openAPIInfo:                 OpenAPIInfo{Title: "HTTP API", Version: "1.0.0"},
 		ipHandlerFunc:               defaultIPHandler(GetPublicIPDefaultFunc()),
 		metricsHandlerFunc:          notImplementedHandler,
 		pingHandlerFunc:             defaultPingHandler,
@@ -90,12 +103,21 @@ func defaultConfig() *config {
 		disableDefaultRouteLogger:   make(map[DefaultRoute]bool, len(allDefaultRoutes())),
 		shutdownWaitGroup:           &sync.WaitGroup{},
 		shutdownSignalChan:          make(chan struct{}),
+		drain:                       &drainState{},
 	}
 }
 
 func (c *config) isIndexRouteEnabled() bool {
+	return c.isDefaultRouteEnabled(IndexRoute)
+}
+
+func (c *config) isOpenAPIRouteEnabled() bool {
+	return c.isDefaultRouteEnabled(OpenAPIRoute)
+}
+
+func (c *config) isDefaultRouteEnabled(id DefaultRoute) bool {
 	for _, r := range c.defaultEnabledRoutes {
-		if r == IndexRoute {
+		if r == id {
 			return true
 		}
 	}
@@ -133,13 +155,69 @@ func validateAddr(addr string) error {
 	return nil
 }
 
-func (c *config) commonMiddleware(noRouteLogger bool, rTimeout time.Duration) []MiddlewareFn {
+// configureTLSClientAuth applies the mTLS client authentication settings to the TLS configuration.
+func (c *config) configureTLSClientAuth() error {
+	if c.tlsClientCAs == nil {
+		return nil
+	}
+
+	if c.tlsConfig == nil {
+		return errors.New("mTLS client authentication requires TLS")
+	}
+
+	c.tlsConfig.ClientCAs = c.tlsClientCAs
+	c.tlsConfig.ClientAuth = c.tlsClientAuth
+
+	return nil
+}
+
+// validateProtocols checks the compatibility of the enabled HTTP protocols with the TLS configuration.
+func (c *config) validateProtocols() error {
+	if c.enableH2C && c.tlsConfig != nil {
+		return errors.New("h2c (HTTP/2 cleartext) cannot be enabled with TLS")
+	}
+
+	if c.enableHTTP3 && c.tlsConfig == nil {
+		return errors.New("HTTP/3 requires TLS")
+	}
+
+	return nil
+}
+
+// protocols returns the set of HTTP protocols accepted by the TCP server.
+func (c *config) protocols() *http.Protocols {
+	if !c.enableH2C {
+		return nil // use the net/http defaults
+	}
+
+	p := &http.Protocols{}
+	p.SetHTTP1(true)
+	p.SetUnencryptedHTTP2(true)
+
+	return p
+}
+
+func (c *config) commonMiddleware(noRouteLogger bool, rTimeout time.Duration, rRateLimiter *RateLimiter, rCORS *CORSPolicy) []MiddlewareFn {
 	middleware := []MiddlewareFn{}
 
 	if !c.disableRouteLogger && !noRouteLogger {
 		middleware = append(middleware, LoggerMiddlewareFn)
 	}
 
+	if corsPolicy := c.routeCORSPolicy(rCORS); corsPolicy != nil {
+		middleware = append(middleware, corsPolicy.MiddlewareFn)
+	}
+
+	if c.compression != nil {
+		middleware = append(middleware, c.compression.MiddlewareFn)
+	}
+
+	// the common rate limiter is resolved by the caller (see routeRateLimiter),
+	// as it only applies to the service routes
+	if rRateLimiter != nil {
+		middleware = append(middleware, rRateLimiter.MiddlewareFn)
+	}
+
 	timeout := c.requestTimeout
 	if rTimeout > 0 {
 		timeout = rTimeout
@@ -158,7 +236,7 @@ func (c *config) commonMiddleware(noRouteLogger bool, rTimeout time.Duration) []
 
 func (c *config) setRouter(ctx context.Context) {
 	l := logging.FromContext(ctx)
-	middleware := c.commonMiddleware(false, 0)
+	middleware := c.commonMiddleware(false, 0, nil, nil)
 
 	if c.router.NotFound == nil {
 		c.router.NotFound = ApplyMiddleware(
@@ -1,9 +1,12 @@
 package httpserver
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/testutil"
 	"github.com/stretchr/testify/require"
@@ -123,6 +126,113 @@ func Test_config_isIndexRouteEnabled(t *testing.T) {
 	}
 }
 
+func Test_config_configureTLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	c := &config{}
+	require.NoError(t, c.configureTLSClientAuth())
+
+	c.tlsClientCAs = x509.NewCertPool()
+	c.tlsClientAuth = tls.RequireAndVerifyClientCert
+	require.Error(t, c.configureTLSClientAuth())
+
+	c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	require.NoError(t, c.configureTLSClientAuth())
+	require.Equal(t, c.tlsClientCAs, c.tlsConfig.ClientCAs)
+	require.Equal(t, tls.RequireAndVerifyClientCert, c.tlsConfig.ClientAuth)
+}
+
+func Test_config_validateProtocols(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     *config
+		wantErr bool
+	}{
+		{
+			name: "default",
+			cfg:  &config{},
+		},
+		{
+			name: "h2c without TLS",
+			cfg:  &config{enableH2C: true},
+		},
+		{
+			name:    "h2c with TLS",
+			cfg:     &config{enableH2C: true, tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12}},
+			wantErr: true,
+		},
+		{
+			name: "HTTP/3 with TLS",
+			cfg:  &config{enableHTTP3: true, tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12}},
+		},
+		{
+			name:    "HTTP/3 without TLS",
+			cfg:     &config{enableHTTP3: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cfg.validateProtocols()
+			require.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func Test_config_protocols(t *testing.T) {
+	t.Parallel()
+
+	c := &config{}
+	require.Nil(t, c.protocols())
+
+	c.enableH2C = true
+	p := c.protocols()
+	require.NotNil(t, p)
+	require.True(t, p.HTTP1())
+	require.True(t, p.UnencryptedHTTP2())
+	require.False(t, p.HTTP2())
+}
+
+func Test_config_commonMiddleware(t *testing.T) {
+	t.Parallel()
+
+	globalRL, err := NewRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	routeRL, err := NewRateLimiter(1, 2)
+	require.NoError(t, err)
+
+	cfg := defaultConfig()
+	require.Len(t, cfg.commonMiddleware(false, 0, nil, nil), 1)
+	require.Len(t, cfg.commonMiddleware(true, 0, nil, nil), 0)
+	require.Len(t, cfg.commonMiddleware(true, 0, routeRL, nil), 1)
+
+	cfg.rateLimiter = globalRL
+	cfg.requestTimeout = 1 * time.Second
+	require.Len(t, cfg.commonMiddleware(false, 0, nil, nil), 2, "the global rate limiter is resolved by routeRateLimiter")
+	require.Len(t, cfg.commonMiddleware(false, 0, cfg.routeRateLimiter(nil), nil), 3)
+	require.Equal(t, globalRL, cfg.routeRateLimiter(nil))
+	require.Equal(t, routeRL, cfg.routeRateLimiter(routeRL))
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// the route rate limiter overrides the global one (burst 2 instead of 1)
+	handler := ApplyMiddleware(MiddlewareArgs{}, next, cfg.commonMiddleware(true, 0, routeRL, nil)...)
+
+	for _, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, want, rr.Code)
+	}
+}
+
 func Test_setRouter(t *testing.T) {
 	//nolint:iface
 	type testRouter interface {
@@ -0,0 +1,338 @@
+package httpserver
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	corsHeaderAllowCredentials = "Access-Control-Allow-Credentials"
+	corsHeaderAllowHeaders     = "Access-Control-Allow-Headers"
+	corsHeaderAllowMethods     = "Access-Control-Allow-Methods"
+	corsHeaderAllowOrigin      = "Access-Control-Allow-Origin"
+	corsHeaderExposeHeaders    = "Access-Control-Expose-Headers"
+	corsHeaderMaxAge           = "Access-Control-Max-Age"
+	corsHeaderRequestHeaders   = "Access-Control-Request-Headers"
+	corsHeaderRequestMethod    = "Access-Control-Request-Method"
+	corsWildcard               = "*"
+)
+
+// CORSPolicy contains the Cross-Origin Resource Sharing (CORS) policy.
+//
+// When a policy is set (globally with WithCORS or per Route), the server
+// automatically answers the preflight OPTIONS requests for every bound path
+// and decorates the actual responses with the CORS headers.
+type CORSPolicy struct {
+	// AllowedOrigins is the list of origins allowed to make cross-origin requests.
+	// The special value "*" allows any origin, but it can't be combined with
+	// AllowCredentials: WithCORS rejects this combination, and the "*" value is
+	// ignored in the Route policies allowing credentials, as reflecting any
+	// origin with credentials would disable the same-origin protection.
+	// An origin can contain one "*" wildcard (e.g.: "https://*.example.com").
+	AllowedOrigins []string
+
+	// AllowedOriginPatterns is a list of regular expressions to match the allowed origins.
+	AllowedOriginPatterns []*regexp.Regexp
+
+	// AllowedMethods is the list of methods allowed for cross-origin requests.
+	// If empty, the methods bound to the requested path are allowed.
+	AllowedMethods []string
+
+	// AllowedHeaders is the list of non-simple headers allowed in cross-origin requests.
+	// The special value "*" allows any requested header.
+	AllowedHeaders []string
+
+	// ExposedHeaders is the list of response headers that the browser is allowed to access.
+	ExposedHeaders []string
+
+	// AllowCredentials indicates whether the request can include user credentials like cookies or TLS client certificates.
+	// The credentials are allowed only for the origins explicitly listed or matched by a pattern.
+	AllowCredentials bool
+
+	// MaxAge indicates how long the results of a preflight request can be cached.
+	MaxAge time.Duration
+}
+
+// isPreflight returns true if the request is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get(corsHeaderRequestMethod) != ""
+}
+
+func (p *CORSPolicy) allowAnyOrigin() bool {
+	return slices.Contains(p.AllowedOrigins, corsWildcard)
+}
+
+// validate checks the policy consistency.
+func (p *CORSPolicy) validate() error {
+	if p.AllowCredentials && p.allowAnyOrigin() {
+		return errors.New("the CORS wildcard origin can't be used with AllowCredentials")
+	}
+
+	return nil
+}
+
+func (p *CORSPolicy) isOriginAllowed(origin string) bool {
+	for _, o := range p.AllowedOrigins {
+		if o == corsWildcard && p.AllowCredentials {
+			// never reflect any origin with credentials
+			continue
+		}
+
+		if o == corsWildcard || strings.EqualFold(o, origin) || matchOriginWildcard(o, origin) {
+			return true
+		}
+	}
+
+	for _, re := range p.AllowedOriginPatterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchOriginWildcard matches an origin against a pattern containing one "*" wildcard.
+func matchOriginWildcard(pattern, origin string) bool {
+	prefix, suffix, found := strings.Cut(strings.ToLower(pattern), corsWildcard)
+	if !found {
+		return false
+	}
+
+	origin = strings.ToLower(origin)
+
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
+
+// setOriginHeaders sets the common CORS headers and returns true if the origin is allowed.
+func (p *CORSPolicy) setOriginHeaders(h http.Header, origin string) bool {
+	if origin == "" || !p.isOriginAllowed(origin) {
+		return false
+	}
+
+	if p.allowAnyOrigin() && !p.AllowCredentials {
+		h.Set(corsHeaderAllowOrigin, corsWildcard)
+	} else {
+		h.Set(corsHeaderAllowOrigin, origin)
+	}
+
+	if p.AllowCredentials {
+		h.Set(corsHeaderAllowCredentials, "true")
+	}
+
+	return true
+}
+
+// setResponseHeaders sets the CORS headers for an actual (non-preflight) request.
+func (p *CORSPolicy) setResponseHeaders(h http.Header, origin string) {
+	h.Add("Vary", "Origin")
+
+	if !p.setOriginHeaders(h, origin) {
+		return
+	}
+
+	if len(p.ExposedHeaders) > 0 {
+		h.Set(corsHeaderExposeHeaders, strings.Join(p.ExposedHeaders, ", "))
+	}
+}
+
+// setPreflightHeaders sets the CORS headers for a preflight request.
+// The boundMethods are the methods bound to the requested path.
+func (p *CORSPolicy) setPreflightHeaders(h http.Header, r *http.Request, boundMethods []string) {
+	h.Add("Vary", "Origin")
+	h.Add("Vary", corsHeaderRequestMethod)
+	h.Add("Vary", corsHeaderRequestHeaders)
+
+	methods := p.AllowedMethods
+	if len(methods) == 0 {
+		methods = boundMethods
+	}
+
+	if !slices.Contains(methods, r.Header.Get(corsHeaderRequestMethod)) {
+		return
+	}
+
+	if !p.setOriginHeaders(h, r.Header.Get("Origin")) {
+		return
+	}
+
+	h.Set(corsHeaderAllowMethods, strings.Join(methods, ", "))
+
+	if slices.Contains(p.AllowedHeaders, corsWildcard) {
+		if reqHeaders := r.Header.Get(corsHeaderRequestHeaders); reqHeaders != "" {
+			h.Set(corsHeaderAllowHeaders, reqHeaders)
+		}
+	} else if len(p.AllowedHeaders) > 0 {
+		h.Set(corsHeaderAllowHeaders, strings.Join(p.AllowedHeaders, ", "))
+	}
+
+	if p.MaxAge > 0 {
+		h.Set(corsHeaderMaxAge, strconv.Itoa(int(p.MaxAge.Seconds())))
+	}
+}
+
+// MiddlewareFn is the middleware handler function that decorates the responses with the CORS headers.
+// Preflight requests are ignored as they are handled by the automatic OPTIONS routes.
+func (p *CORSPolicy) MiddlewareFn(_ MiddlewareArgs, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isPreflight(r) {
+			p.setResponseHeaders(w.Header(), r.Header.Get("Origin"))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsRoute is a bound route path split into segments, with its CORS policy (if any).
+type corsRoute struct {
+	segments []string
+	policy   *CORSPolicy
+}
+
+// corsPreflight answers the preflight requests of the paths bound with a CORS policy.
+// It is used as the router GlobalOPTIONS handler, so the routes bound in
+// different method trees (e.g. "GET /users/new" and "POST /users/:id") don't
+// need to share a single OPTIONS route.
+type corsPreflight struct {
+	routes map[string][]corsRoute // by method
+}
+
+// match returns the methods bound to the path with their CORS policy.
+// Each router method tree matches at most one route for a given path.
+func (cp *corsPreflight) match(path string) map[string]*CORSPolicy {
+	segments := strings.Split(path, "/")
+	policies := make(map[string]*CORSPolicy)
+
+	for method, routes := range cp.routes {
+		for _, r := range routes {
+			if matchRouteSegments(r.segments, segments) {
+				policies[method] = r.policy
+				break
+			}
+		}
+	}
+
+	return policies
+}
+
+// ServeHTTP answers the OPTIONS requests without a specific route.
+// The router already set the "Allow" header with the methods bound to the path.
+func (cp *corsPreflight) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	policies := cp.match(r.URL.Path)
+
+	if !hasCORSPolicy(policies) {
+		return // default router behavior for the paths without a CORS policy
+	}
+
+	if isPreflight(r) {
+		if p := policies[r.Header.Get(corsHeaderRequestMethod)]; p != nil {
+			p.setPreflightHeaders(w.Header(), r, slices.Sorted(maps.Keys(policies)))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hasCORSPolicy returns true if any of the bound methods has a CORS policy.
+func hasCORSPolicy(policies map[string]*CORSPolicy) bool {
+	for _, p := range policies {
+		if p != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchRouteSegments returns true if the path segments match the route segments,
+// using the httprouter ":name" and "*name" parameter syntax.
+func matchRouteSegments(route, path []string) bool {
+	for i, s := range route {
+		if strings.HasPrefix(s, "*") {
+			return i < len(path)
+		}
+
+		if i >= len(path) {
+			return false
+		}
+
+		if strings.HasPrefix(s, ":") {
+			if path[i] == "" {
+				return false
+			}
+
+			continue
+		}
+
+		if s != path[i] {
+			return false
+		}
+	}
+
+	return len(route) == len(path)
+}
+
+// bindCORSPreflight sets the router GlobalOPTIONS handler to answer the preflight requests
+// of the paths with a CORS policy, unless already set.
+// The paths with a custom OPTIONS route are handled by the route itself.
+// The preflight requests are only handled if the router HandleOPTIONS option is enabled (default).
+func (c *config) bindCORSPreflight(l *zap.Logger, routes []Route) {
+	if c.router.GlobalOPTIONS != nil {
+		return
+	}
+
+	cp := &corsPreflight{routes: make(map[string][]corsRoute)}
+	enabled := false
+
+	for _, r := range routes {
+		if r.Method == http.MethodOptions {
+			continue
+		}
+
+		policy := c.routeCORSPolicy(r.CORS)
+		enabled = enabled || (policy != nil)
+
+		cp.routes[r.Method] = append(cp.routes[r.Method], corsRoute{
+			segments: strings.Split(r.Path, "/"),
+			policy:   policy,
+		})
+	}
+
+	if !enabled {
+		return
+	}
+
+	l.Debug("binding CORS preflight handler")
+
+	args := MiddlewareArgs{
+		Method:            http.MethodOptions,
+		Path:              "preflight",
+		Description:       "CORS preflight",
+		TraceIDHeaderName: c.traceIDHeaderName,
+		RedactFunc:        c.redactFn,
+		Logger:            l,
+	}
+
+	c.router.GlobalOPTIONS = ApplyMiddleware(args, cp, c.commonMiddleware(false, 0, nil, nil)...)
+}
+
+// routeCORSPolicy returns the route CORS policy, if set, or the global one.
+func (c *config) routeCORSPolicy(rCORS *CORSPolicy) *CORSPolicy {
+	if rCORS != nil {
+		return rCORS
+	}
+
+	return c.corsPolicy
+}
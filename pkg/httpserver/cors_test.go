@@ -0,0 +1,364 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCORSPolicy_isOriginAllowed(t *testing.T) {
+	t.Parallel()
+
+	p := &CORSPolicy{
+		AllowedOrigins:        []string{"https://example.com", "https://*.example.org"},
+		AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^https://app[0-9]+\.example\.net$`)},
+	}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://example.com", want: true},
+		{origin: "HTTPS://EXAMPLE.COM", want: true},
+		{origin: "http://example.com", want: false},
+		{origin: "https://api.example.org", want: true},
+		{origin: "https://.example.org", want: false},
+		{origin: "https://example.org", want: false},
+		{origin: "https://app42.example.net", want: true},
+		{origin: "https://appx.example.net", want: false},
+		{origin: "https://evil.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, p.isOriginAllowed(tt.origin))
+		})
+	}
+
+	require.True(t, (&CORSPolicy{AllowedOrigins: []string{"*"}}).isOriginAllowed("https://any.com"))
+	require.False(t, (&CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}).isOriginAllowed("https://any.com"))
+}
+
+func TestCORSPolicy_validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&CORSPolicy{AllowedOrigins: []string{"*"}}).validate())
+	require.NoError(t, (&CORSPolicy{AllowedOrigins: []string{"https://example.com"}, AllowCredentials: true}).validate())
+	require.Error(t, (&CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}).validate())
+}
+
+func TestCORSPolicy_MiddlewareFn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		policy          *CORSPolicy
+		method          string
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+		wantExpose      string
+	}{
+		{
+			name:       "any origin",
+			policy:     &CORSPolicy{AllowedOrigins: []string{"*"}, ExposedHeaders: []string{"X-One", "X-Two"}},
+			method:     http.MethodGet,
+			origin:     "https://example.com",
+			wantOrigin: "*",
+			wantExpose: "X-One, X-Two",
+		},
+		{
+			name:   "any origin with credentials is never reflected",
+			policy: &CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method: http.MethodGet,
+			origin: "https://example.com",
+		},
+		{
+			name:            "explicit origin with credentials",
+			policy:          &CORSPolicy{AllowedOrigins: []string{"*", "https://example.com"}, AllowCredentials: true},
+			method:          http.MethodGet,
+			origin:          "https://example.com",
+			wantOrigin:      "https://example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:   "origin not allowed",
+			policy: &CORSPolicy{AllowedOrigins: []string{"https://example.com"}},
+			method: http.MethodGet,
+			origin: "https://evil.com",
+		},
+		{
+			name:   "no origin",
+			policy: &CORSPolicy{AllowedOrigins: []string{"*"}},
+			method: http.MethodGet,
+		},
+		{
+			name:   "preflight ignored",
+			policy: &CORSPolicy{AllowedOrigins: []string{"*"}},
+			method: http.MethodOptions,
+			origin: "https://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			req.Header.Set(corsHeaderRequestMethod, http.MethodGet)
+
+			rr := httptest.NewRecorder()
+			tt.policy.MiddlewareFn(MiddlewareArgs{}, next).ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, tt.wantOrigin, rr.Header().Get(corsHeaderAllowOrigin))
+			require.Equal(t, tt.wantCredentials, rr.Header().Get(corsHeaderAllowCredentials))
+			require.Equal(t, tt.wantExpose, rr.Header().Get(corsHeaderExposeHeaders))
+		})
+	}
+}
+
+type corsBinder struct{}
+
+func (b *corsBinder) BindHTTP(_ context.Context) []Route {
+	handler := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	return []Route{
+		{Method: http.MethodGet, Path: "/items/:id", Handler: handler},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/items/:itemid",
+			Handler: handler,
+			CORS: &CORSPolicy{
+				AllowedOrigins: []string{"https://admin.example.com"},
+				AllowedMethods: []string{http.MethodDelete},
+			},
+		},
+		{Method: http.MethodGet, Path: "/users/new", Handler: handler},
+		{Method: http.MethodPost, Path: "/users/:id", Handler: handler},
+		{Method: http.MethodPost, Path: "/custom", Handler: handler},
+		{Method: http.MethodOptions, Path: "/custom", Handler: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusTeapot) }},
+	}
+}
+
+func Test_loadRoutes_cors(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	require.NoError(t, WithCORS(&CORSPolicy{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedHeaders: []string{"*"},
+		MaxAge:         10 * time.Minute,
+	})(cfg))
+
+	ctx := t.Context()
+	cfg.setRouter(ctx)
+	loadRoutes(ctx, zap.NewNop(), &corsBinder{}, cfg)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		origin      string
+		reqMethod   string
+		reqHeaders  string
+		wantStatus  int
+		wantOrigin  string
+		wantMethods string
+		wantHeaders string
+		wantMaxAge  string
+		wantAllow   string
+	}{
+		{
+			name:        "preflight with global policy",
+			method:      http.MethodOptions,
+			path:        "/items/123",
+			origin:      "https://www.example.com",
+			reqMethod:   http.MethodGet,
+			reqHeaders:  "X-Custom",
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://www.example.com",
+			wantMethods: "DELETE, GET",
+			wantHeaders: "X-Custom",
+			wantMaxAge:  "600",
+			wantAllow:   "DELETE, GET, OPTIONS",
+		},
+		{
+			name:        "preflight with route policy",
+			method:      http.MethodOptions,
+			path:        "/items/123",
+			origin:      "https://admin.example.com",
+			reqMethod:   http.MethodDelete,
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://admin.example.com",
+			wantMethods: "DELETE",
+			wantAllow:   "DELETE, GET, OPTIONS",
+		},
+		{
+			name:       "preflight rejected by route policy",
+			method:     http.MethodOptions,
+			path:       "/items/123",
+			origin:     "https://www.example.com",
+			reqMethod:  http.MethodDelete,
+			wantStatus: http.StatusNoContent,
+			wantAllow:  "DELETE, GET, OPTIONS",
+		},
+		{
+			name:       "preflight with unbound method",
+			method:     http.MethodOptions,
+			path:       "/items/123",
+			origin:     "https://www.example.com",
+			reqMethod:  http.MethodPut,
+			wantStatus: http.StatusNoContent,
+			wantAllow:  "DELETE, GET, OPTIONS",
+		},
+		{
+			name:       "plain OPTIONS request",
+			method:     http.MethodOptions,
+			path:       "/items/123",
+			wantStatus: http.StatusNoContent,
+			wantAllow:  "DELETE, GET, OPTIONS",
+		},
+		{
+			name:        "preflight with static and param routes",
+			method:      http.MethodOptions,
+			path:        "/users/new",
+			origin:      "https://www.example.com",
+			reqMethod:   http.MethodPost,
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://www.example.com",
+			wantMethods: "GET, POST",
+			wantMaxAge:  "600",
+			wantAllow:   "GET, OPTIONS, POST",
+		},
+		{
+			name:        "preflight with param route",
+			method:      http.MethodOptions,
+			path:        "/users/42",
+			origin:      "https://www.example.com",
+			reqMethod:   http.MethodPost,
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://www.example.com",
+			wantMethods: "POST",
+			wantMaxAge:  "600",
+			wantAllow:   "OPTIONS, POST",
+		},
+		{
+			name:       "preflight with unknown path",
+			method:     http.MethodOptions,
+			path:       "/unknown",
+			origin:     "https://www.example.com",
+			reqMethod:  http.MethodGet,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "custom OPTIONS route",
+			method:     http.MethodOptions,
+			path:       "/custom",
+			origin:     "https://www.example.com",
+			reqMethod:  http.MethodPost,
+			wantStatus: http.StatusTeapot,
+		},
+		{
+			name:       "actual request",
+			method:     http.MethodGet,
+			path:       "/items/123",
+			origin:     "https://www.example.com",
+			wantStatus: http.StatusOK,
+			wantOrigin: "https://www.example.com",
+		},
+		{
+			name:       "actual request with route policy",
+			method:     http.MethodDelete,
+			path:       "/items/123",
+			origin:     "https://www.example.com",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			if tt.reqMethod != "" {
+				req.Header.Set(corsHeaderRequestMethod, tt.reqMethod)
+			}
+
+			if tt.reqHeaders != "" {
+				req.Header.Set(corsHeaderRequestHeaders, tt.reqHeaders)
+			}
+
+			rr := httptest.NewRecorder()
+			cfg.router.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tt.wantOrigin, rr.Header().Get(corsHeaderAllowOrigin))
+			require.Equal(t, tt.wantMethods, rr.Header().Get(corsHeaderAllowMethods))
+			require.Equal(t, tt.wantHeaders, rr.Header().Get(corsHeaderAllowHeaders))
+			require.Equal(t, tt.wantMaxAge, rr.Header().Get(corsHeaderMaxAge))
+			require.Equal(t, tt.wantAllow, rr.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestNew_cors(t *testing.T) {
+	t.Parallel()
+
+	// the routes in different method trees don't conflict on the preflight requests
+	h, err := New(t.Context(), &corsBinder{}, WithCORS(&CORSPolicy{AllowedOrigins: []string{"*"}}))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+}
+
+func Test_matchRouteSegments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		route string
+		path  string
+		want  bool
+	}{
+		{route: "/", path: "/", want: true},
+		{route: "/users/new", path: "/users/new", want: true},
+		{route: "/users/new", path: "/users/old", want: false},
+		{route: "/users/:id", path: "/users/new", want: true},
+		{route: "/users/:id", path: "/users/", want: false},
+		{route: "/users/:id", path: "/users/1/x", want: false},
+		{route: "/users/:id/x", path: "/users/1", want: false},
+		{route: "/files/*path", path: "/files/", want: true},
+		{route: "/files/*path", path: "/files/a/b", want: true},
+		{route: "/files/*path", path: "/files", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route+" "+tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got := matchRouteSegments(strings.Split(tt.route, "/"), strings.Split(tt.path, "/"))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package httpserver
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// drainState tracks the in-flight requests and the draining phase that precedes the server shutdown.
+type drainState struct {
+	draining atomic.Bool
+	inFlight atomic.Int64
+}
+
+// trackHandler counts the in-flight requests.
+func (d *drainState) trackHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.inFlight.Add(1)
+		defer d.inFlight.Add(-1)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// readinessHandler returns 503 Service Unavailable while the server is draining,
+// so load balancers can stop routing new traffic to this instance.
+func (d *drainState) readinessHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if d.draining.Load() {
+			w.Header().Set("Connection", "close")
+			notAvailableHandler(w, r)
+
+			return
+		}
+
+		next(w, r)
+	}
+}
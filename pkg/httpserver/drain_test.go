@@ -0,0 +1,44 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_drainState_trackHandler(t *testing.T) {
+	t.Parallel()
+
+	d := &drainState{}
+
+	handler := d.trackHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		require.Equal(t, int64(1), d.inFlight.Load())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, int64(0), d.inFlight.Load())
+}
+
+func Test_drainState_readinessHandler(t *testing.T) {
+	t.Parallel()
+
+	d := &drainState{}
+	handler := d.readinessHandler(defaultPingHandler)
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	d.draining.Store(true)
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.Equal(t, "close", rr.Header().Get("Connection"))
+}
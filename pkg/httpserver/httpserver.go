@@ -5,6 +5,8 @@ and options.
 Optional common routes are defined in the routes.go file. The routes include:
   - /ip: Returns the public IP address of the service instance.
   - /metrics: Returns Prometheus metrics (default and custom).
+  - /openapi.json: Returns the OpenAPI 3.1 document generated from the routes
+    (only enabled explicitly with WithEnableDefaultRoutes(OpenAPIRoute)).
   - /ping: Pings the service to check if it is alive.
   - /pprof: Returns pprof profiling data for the selected profile.
   - /status: Checks and returns the health status of the service, including
@@ -17,12 +19,16 @@ package httpserver
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/httputil"
 	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/quic-go/quic-go/http3"
 	"go.uber.org/zap"
 )
 
@@ -43,11 +49,18 @@ func (b *nopBinder) BindHTTP(_ context.Context) []Route { return nil }
 
 // HTTPServer defines the HTTP Server object.
 type HTTPServer struct {
-	cfg        *config
-	ctx        context.Context //nolint:containedctx
-	httpServer *http.Server
-	listener   net.Listener
-	logger     *zap.Logger
+	cfg         *config
+	ctx         context.Context //nolint:containedctx
+	httpServer  *http.Server
+	listener    net.Listener
+	http3Server *http3.Server
+	packetConn  net.PacketConn
+	logger      *zap.Logger
+
+	http3Once   sync.Once
+	http3Cancel context.CancelFunc
+	http3Done   chan struct{}
+	http3Err    error
 }
 
 // Start configures and start a new HTTP server.
@@ -79,6 +92,16 @@ func New(ctx context.Context, binder Binder, opts ...Option) (*HTTPServer, error
 		zap.String("addr", cfg.serverAddr),
 	)
 
+	err := cfg.validateProtocols()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.configureTLSClientAuth()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg.setRouter(ctx)
 	loadRoutes(ctx, logger, binder, cfg)
 
@@ -87,21 +110,49 @@ func New(ctx context.Context, binder Binder, opts ...Option) (*HTTPServer, error
 		return nil, err
 	}
 
-	return &HTTPServer{
-			cfg: cfg,
-			ctx: ctx,
-			httpServer: &http.Server{
-				Addr:              cfg.serverAddr,
-				Handler:           cfg.router,
-				ReadHeaderTimeout: cfg.serverReadHeaderTimeout,
-				ReadTimeout:       cfg.serverReadTimeout,
-				TLSConfig:         cfg.tlsConfig,
-				WriteTimeout:      cfg.serverWriteTimeout,
-			},
-			listener: listener,
-			logger:   logger,
+	var handler http.Handler = cfg.router
+
+	if cfg.tlsClientCAs != nil {
+		handler = clientIdentityHandler(handler)
+	}
+
+	handler = cfg.drain.trackHandler(handler)
+
+	h := &HTTPServer{
+		cfg: cfg,
+		ctx: ctx,
+		httpServer: &http.Server{
+			Addr:              cfg.serverAddr,
+			Handler:           handler,
+			ReadHeaderTimeout: cfg.serverReadHeaderTimeout,
+			ReadTimeout:       cfg.serverReadTimeout,
+			TLSConfig:         cfg.tlsConfig,
+			WriteTimeout:      cfg.serverWriteTimeout,
+			Protocols:         cfg.protocols(),
 		},
-		nil
+		listener: listener,
+		logger:   logger,
+	}
+
+	if !cfg.enableHTTP3 {
+		return h, nil
+	}
+
+	// HTTP/3 uses the same port number of the TCP listener
+	h.packetConn, err = packetListener(ctx, listener.Addr().String())
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	h.http3Server = &http3.Server{
+		Handler:   handler,
+		TLSConfig: cfg.tlsConfig,
+	}
+
+	h.httpServer.Handler = altSvcHandler(h.http3Server, cfg.drain, handler)
+
+	return h, nil
 }
 
 // StartServerCtx starts the current server and return without blocking.
@@ -116,6 +167,9 @@ func (h *HTTPServer) StartServerCtx(ctx context.Context) {
 			h.logger.Warn("context canceled")
 		}
 
+		// The drain runs before the shutdown timeout starts.
+		h.drain(context.Background()) //nolint:contextcheck
+
 		// The shutdown context is independent from the parent context.
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), h.cfg.shutdownTimeout)
 		defer cancel()
@@ -128,6 +182,12 @@ func (h *HTTPServer) StartServerCtx(ctx context.Context) {
 		h.serve()
 	}()
 
+	if h.http3Server != nil {
+		go func() {
+			h.serveHTTP3()
+		}()
+	}
+
 	h.cfg.shutdownWaitGroup.Add(1)
 
 	h.logger.Info("listening for http requests")
@@ -139,11 +199,31 @@ func (h *HTTPServer) StartServer() {
 }
 
 // Shutdown gracefully shuts down the server without interrupting any active connections.
+// If a drain delay is configured (see WithShutdownDrainDelay) and the server is
+// not already drained, the server first enters a draining phase that consumes
+// part of the ctx deadline: the callers must budget for it, setting a deadline
+// of at least the drain delay plus the time required to complete the in-flight
+// requests. StartServerCtx runs the drain before creating the shutdown deadline.
 // Wraps the standard net/http/Server_Shutdown method.
 func (h *HTTPServer) Shutdown(ctx context.Context) error {
-	h.logger.Debug("shutting down http server")
+	h.drain(ctx)
+
+	h.logger.Debug("shutting down http server", zap.Int64("inflight_requests", h.cfg.drain.inFlight.Load()))
 
 	err := h.httpServer.Shutdown(ctx)
+
+	if h.http3Server != nil {
+		h3Err := h.shutdownHTTP3(ctx)
+
+		closeErr := h.packetConn.Close()
+		if errors.Is(closeErr, net.ErrClosed) {
+			// the connection is already closed by the HTTP/3 server
+			closeErr = nil
+		}
+
+		err = errors.Join(err, h3Err, closeErr)
+	}
+
 	h.cfg.shutdownWaitGroup.Add(-1)
 
 	h.logger.Debug("http server shutdown complete", zap.Error(err))
@@ -151,6 +231,69 @@ func (h *HTTPServer) Shutdown(ctx context.Context) error {
 	return err //nolint:wrapcheck
 }
 
+// drain flags the server as not ready, disables the keep-alives and waits for the drain delay,
+// so the load balancers can stop routing new traffic before the listeners are closed.
+func (h *HTTPServer) drain(ctx context.Context) {
+	if h.cfg.shutdownDrainDelay <= 0 || h.cfg.drain.draining.Swap(true) {
+		return
+	}
+
+	h.httpServer.SetKeepAlivesEnabled(false)
+
+	if h.http3Server != nil {
+		// HTTP/3 has no keep-alives: stop accepting new connections and
+		// send GOAWAY to the clients, so they switch to the TCP listener.
+		h.startHTTP3Shutdown()
+	}
+
+	h.logger.Info("draining http server",
+		zap.Duration("drain_delay", h.cfg.shutdownDrainDelay),
+		zap.Int64("inflight_requests", h.cfg.drain.inFlight.Load()),
+	)
+
+	timer := time.NewTimer(h.cfg.shutdownDrainDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	h.logger.Info("http server drain completed", zap.Int64("inflight_requests", h.cfg.drain.inFlight.Load()))
+}
+
+// startHTTP3Shutdown starts the graceful shutdown of the HTTP/3 server in background, only once.
+func (h *HTTPServer) startHTTP3Shutdown() {
+	h.http3Once.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		h.http3Cancel = cancel
+		h.http3Done = make(chan struct{})
+
+		go func() {
+			defer close(h.http3Done)
+
+			h.http3Err = h.http3Server.Shutdown(ctx)
+		}()
+	})
+}
+
+// shutdownHTTP3 waits for the graceful shutdown of the HTTP/3 server,
+// and closes all the connections when the context is done.
+func (h *HTTPServer) shutdownHTTP3(ctx context.Context) error {
+	h.startHTTP3Shutdown()
+
+	select {
+	case <-h.http3Done:
+		return h.http3Err
+	case <-ctx.Done():
+		h.http3Cancel()
+		<-h.http3Done
+
+		return ctx.Err()
+	}
+}
+
 func (h *HTTPServer) serve() {
 	err := h.httpServer.Serve(h.listener)
 	if err == http.ErrServerClosed {
@@ -161,6 +304,16 @@ func (h *HTTPServer) serve() {
 	h.logger.Error("unexpected http server failure", zap.Error(err))
 }
 
+func (h *HTTPServer) serveHTTP3() {
+	err := h.http3Server.Serve(h.packetConn)
+	if errors.Is(err, http.ErrServerClosed) {
+		h.logger.Debug("closed http3 server")
+		return
+	}
+
+	h.logger.Error("unexpected http3 server failure", zap.Error(err))
+}
+
 func netListener(ctx context.Context, serverAddr string, tlsConfig *tls.Config) (net.Listener, error) {
 	var (
 		ls  net.Listener
@@ -182,10 +335,44 @@ func netListener(ctx context.Context, serverAddr string, tlsConfig *tls.Config)
 	return ls, nil
 }
 
+func packetListener(ctx context.Context, serverAddr string) (net.PacketConn, error) {
+	var lc net.ListenConfig
+
+	pc, err := lc.ListenPacket(ctx, "udp", serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the http3 server address listener: %w", err)
+	}
+
+	return pc, nil
+}
+
+// clientIdentityHandler injects the verified TLS client identity in the request context.
+func clientIdentityHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := httputil.ClientIdentityFromTLS(r.TLS); ok {
+			r = r.WithContext(httputil.WithClientIdentity(r.Context(), id))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// altSvcHandler advertises the HTTP/3 endpoint on the TCP responses, except while draining.
+func altSvcHandler(h3 *http3.Server, d *drainState, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor < 3 && !d.draining.Load() {
+			_ = h3.SetQUICHeaders(w.Header())
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config) {
 	l.Debug("loading default routes")
 
 	routes := newDefaultRoutes(cfg)
+	numDefaultRoutes := len(routes)
 
 	l.Debug("loading service routes")
 
@@ -195,11 +382,17 @@ func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config)
 
 	l.Debug("applying routes")
 
-	for _, r := range routes {
+	for i, r := range routes {
 		l.Debug("binding route", zap.String("path", r.Path))
 
+		// the common rate limiter does not apply to the default routes (e.g. status, ping and metrics)
+		rateLimiter := r.RateLimiter
+		if i >= numDefaultRoutes {
+			rateLimiter = cfg.routeRateLimiter(r.RateLimiter)
+		}
+
 		// Add default and custom middleware functions
-		middleware := cfg.commonMiddleware(r.DisableLogger, r.Timeout)
+		middleware := cfg.commonMiddleware(r.DisableLogger, r.Timeout, rateLimiter, r.CORS)
 		middleware = append(middleware, r.Middleware...)
 
 		args := MiddlewareArgs{
@@ -216,12 +409,47 @@ func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config)
 		cfg.router.Handler(r.Method, r.Path, handler)
 	}
 
+	// attach OpenAPI document if enabled
+	if cfg.isOpenAPIRouteEnabled() {
+		l.Debug("enabling OpenAPI document handler")
+
+		_, disableLogger := cfg.disableDefaultRouteLogger[OpenAPIRoute]
+
+		openAPIRoute := Route{
+			Method:        http.MethodGet,
+			Path:          openAPIHandlerPath,
+			Description:   "Returns the OpenAPI document of this service.",
+			Handler:       defaultOpenAPIHandler(cfg.openAPIInfo, routes),
+			DisableLogger: disableLogger,
+		}
+
+		middleware := cfg.commonMiddleware(disableLogger, 0, nil, nil)
+
+		args := MiddlewareArgs{
+			Method:            openAPIRoute.Method,
+			Path:              openAPIRoute.Path,
+			Description:       openAPIRoute.Description,
+			TraceIDHeaderName: cfg.traceIDHeaderName,
+			RedactFunc:        cfg.redactFn,
+			Logger:            l,
+		}
+
+		handler := ApplyMiddleware(args, openAPIRoute.Handler, middleware...)
+
+		cfg.router.Handler(args.Method, args.Path, handler)
+
+		routes = append(routes, openAPIRoute)
+	}
+
+	// attach the automatic CORS preflight handler
+	cfg.bindCORSPreflight(l, routes)
+
 	// attach route index if enabled
 	if cfg.isIndexRouteEnabled() {
 		l.Debug("enabling route index handler")
 
 		_, disableLogger := cfg.disableDefaultRouteLogger[IndexRoute]
-		middleware := cfg.commonMiddleware(disableLogger, 0)
+		middleware := cfg.commonMiddleware(disableLogger, 0, nil, nil)
 
 		args := MiddlewareArgs{
 			Method:            http.MethodGet,
@@ -266,6 +494,10 @@ func defaultStatusHandler(w http.ResponseWriter, r *http.Request) {
 	httputil.SendStatus(r.Context(), w, http.StatusOK)
 }
 
+func notAvailableHandler(w http.ResponseWriter, r *http.Request) {
+	httputil.SendStatus(r.Context(), w, http.StatusServiceUnavailable)
+}
+
 func notImplementedHandler(w http.ResponseWriter, r *http.Request) {
 	httputil.SendStatus(r.Context(), w, http.StatusNotImplemented)
 }
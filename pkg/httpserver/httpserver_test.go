@@ -4,10 +4,18 @@ package httpserver
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -15,8 +23,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/httputil"
 	"github.com/Vonage/gosrvlib/pkg/logging"
 	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -467,3 +477,405 @@ func Test_Serve_error(t *testing.T) {
 
 	h.serve()
 }
+
+// testCert is a test certificate with the PEM encoded data.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert generates a certificate signed by the parent, or self-signed if the parent is nil.
+func newTestCert(t *testing.T, cn string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if isCA {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+		tpl.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	parentCert, parentKey := tpl, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+// newTestTLSCertData generates a self-signed certificate for the loopback address.
+func newTestTLSCertData(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	c := newTestCert(t, "localhost", nil, false)
+
+	return c.certPEM, c.keyPEM
+}
+
+// freeTestAddr returns a loopback address with a free TCP port.
+func freeTestAddr(t *testing.T) string {
+	t.Helper()
+
+	var lc net.ListenConfig
+
+	l, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := l.Addr().String()
+
+	require.NoError(t, l.Close())
+
+	return addr
+}
+
+type protoBinder struct{}
+
+func (b *protoBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method: http.MethodGet,
+			Path:   "/proto",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				httputil.SendText(r.Context(), w, http.StatusOK, r.Proto)
+			},
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/identity",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				id, ok := httputil.GetClientIdentity(r)
+				if !ok {
+					httputil.SendText(r.Context(), w, http.StatusOK, "anonymous")
+					return
+				}
+
+				httputil.SendText(r.Context(), w, http.StatusOK, id.CommonName)
+			},
+		},
+	}
+}
+
+func startProtoServer(t *testing.T, opts ...Option) (*HTTPServer, string) {
+	t.Helper()
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	opts = append(opts,
+		WithServerAddr(freeTestAddr(t)),
+		WithShutdownTimeout(1*time.Second),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+
+	h, err := New(t.Context(), &protoBinder{}, opts...)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	t.Cleanup(func() {
+		close(shutdownSG)
+		shutdownWG.Wait()
+	})
+
+	return h, h.listener.Addr().String()
+}
+
+func getProto(t *testing.T, client *http.Client, url string) (string, *http.Response) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	return string(body), resp
+}
+
+func TestNew_h2c(t *testing.T) {
+	t.Parallel()
+
+	_, addr := startProtoServer(t, WithH2C())
+
+	protocols := &http.Protocols{}
+	protocols.SetUnencryptedHTTP2(true)
+
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+	proto, _ := getProto(t, client, "http://"+addr+"/proto")
+	require.Equal(t, "HTTP/2.0", proto)
+
+	proto, _ = getProto(t, &http.Client{}, "http://"+addr+"/proto")
+	require.Equal(t, "HTTP/1.1", proto)
+}
+
+func TestNew_http3(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM := newTestTLSCertData(t)
+
+	_, addr := startProtoServer(t, WithTLSCertData(certPEM, keyPEM), WithHTTP3())
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+
+	tr := &http3.Transport{TLSClientConfig: tlsConfig}
+	defer func() { _ = tr.Close() }()
+
+	proto, _ := getProto(t, &http.Client{Transport: tr}, "https://"+addr+"/proto")
+	require.Equal(t, "HTTP/3.0", proto)
+
+	proto, resp := getProto(t, &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, "https://"+addr+"/proto")
+	require.Equal(t, "HTTP/1.1", proto)
+
+	_, port, _ := net.SplitHostPort(addr)
+	require.Contains(t, resp.Header.Get("Alt-Svc"), `h3=":`+port+`"`)
+}
+
+func TestHTTPServer_Shutdown_http3(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM := newTestTLSCertData(t)
+
+	h, err := New(t.Context(), NopBinder(),
+		WithServerAddr(freeTestAddr(t)),
+		WithTLSCertData(certPEM, keyPEM),
+		WithHTTP3(),
+	)
+	require.NoError(t, err)
+
+	// the packet connection already closed by the HTTP/3 server is not reported as error
+	require.NoError(t, h.packetConn.Close())
+
+	h.cfg.shutdownWaitGroup.Add(1)
+
+	require.NoError(t, h.Shutdown(t.Context()))
+}
+
+func TestNew_mTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCert(t, "ca", nil, true)
+	server := newTestCert(t, "localhost", ca, false)
+	client := newTestCert(t, "client", ca, false)
+	other := newTestCert(t, "other", nil, false)
+
+	certFile, keyFile := writeTestCertFiles(t, t.TempDir(), server, time.Now())
+
+	_, addr := startProtoServer(t,
+		WithTLSCertFiles(certFile, keyFile),
+		WithTLSClientAuth(ca.certPEM, tls.VerifyClientCertIfGiven),
+		WithHTTP3(),
+	)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+
+	newClient := func(c *testCert) *http.Client {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: rootCAs}
+
+		if c != nil {
+			cert, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+			require.NoError(t, err)
+
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+
+	id, _ := getProto(t, newClient(client), "https://"+addr+"/identity")
+	require.Equal(t, "client", id)
+
+	id, _ = getProto(t, newClient(nil), "https://"+addr+"/identity")
+	require.Equal(t, "anonymous", id)
+
+	// certificates not issued by the client CA are not sent
+	id, _ = getProto(t, newClient(other), "https://"+addr+"/identity")
+	require.Equal(t, "anonymous", id)
+
+	_, err := New(t.Context(), NopBinder(), WithTLSClientAuth(ca.certPEM, tls.RequireAndVerifyClientCert))
+	require.EqualError(t, err, "mTLS client authentication requires TLS")
+}
+
+func TestHTTPServer_Shutdown_drain(t *testing.T) {
+	t.Parallel()
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+	addr := freeTestAddr(t)
+
+	h, err := New(t.Context(), &protoBinder{},
+		WithServerAddr(addr),
+		WithEnableDefaultRoutes(PingRoute),
+		WithShutdownDrainDelay(500*time.Millisecond),
+		WithShutdownTimeout(1*time.Second),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	client := &http.Client{}
+
+	get := func(path string) *http.Response {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+addr+path, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		return resp
+	}
+
+	require.Equal(t, http.StatusOK, get("/ping").StatusCode)
+
+	close(shutdownSG)
+
+	require.Eventually(t, h.cfg.drain.draining.Load, time.Second, 10*time.Millisecond)
+
+	// the readiness routes fail while the other requests are still served
+	resp := get("/ping")
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.True(t, resp.Close)
+
+	resp = get("/proto")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, resp.Close)
+
+	shutdownWG.Wait()
+
+	// the drain is executed only once
+	shutdownWG.Add(1)
+	require.NoError(t, h.Shutdown(t.Context()))
+}
+
+func TestHTTPServer_Shutdown_drainHTTP3(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM := newTestTLSCertData(t)
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &protoBinder{},
+		WithServerAddr(freeTestAddr(t)),
+		WithTLSCertData(certPEM, keyPEM),
+		WithHTTP3(),
+		WithShutdownDrainDelay(500*time.Millisecond),
+		WithShutdownTimeout(1*time.Second),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	addr := h.listener.Addr().String()
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+
+	_, resp := getProto(t, &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, "https://"+addr+"/proto")
+	require.NotEmpty(t, resp.Header.Get("Alt-Svc"))
+
+	close(shutdownSG)
+
+	require.Eventually(t, h.cfg.drain.draining.Load, time.Second, 10*time.Millisecond)
+
+	// the TCP requests are still served without advertising HTTP/3
+	proto, resp := getProto(t, &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, "https://"+addr+"/proto")
+	require.Equal(t, "HTTP/1.1", proto)
+	require.Empty(t, resp.Header.Get("Alt-Svc"))
+
+	// the HTTP/3 listener doesn't accept new connections
+	tr := &http3.Transport{TLSClientConfig: tlsConfig}
+	defer func() { _ = tr.Close() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+addr+"/proto", nil)
+	require.NoError(t, err)
+
+	resp, err = tr.RoundTrip(req) //nolint:bodyclose
+	require.Error(t, err)
+	require.Nil(t, resp)
+
+	shutdownWG.Wait()
+}
+
+func TestNew_protocols_error(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM := newTestTLSCertData(t)
+
+	_, err := New(t.Context(), NopBinder(), WithHTTP3())
+	require.EqualError(t, err, "HTTP/3 requires TLS")
+
+	_, err = New(t.Context(), NopBinder(), WithH2C(), WithTLSCertData(certPEM, keyPEM))
+	require.EqualError(t, err, "h2c (HTTP/2 cleartext) cannot be enabled with TLS")
+
+	// the UDP port is already bound
+	var lc net.ListenConfig
+
+	pc, err := lc.ListenPacket(t.Context(), "udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = pc.Close() }()
+
+	_, err = New(t.Context(), NopBinder(), WithServerAddr(pc.LocalAddr().String()), WithHTTP3(), WithTLSCertData(certPEM, keyPEM))
+	require.ErrorContains(t, err, "failed creating the http3 server address listener")
+}
+
+func Test_serveHTTP3_error(t *testing.T) {
+	t.Parallel()
+
+	h := &HTTPServer{
+		cfg:         defaultConfig(),
+		ctx:         t.Context(),
+		http3Server: &http3.Server{},
+		logger:      logging.NopLogger(),
+	}
+
+	h.serveHTTP3()
+}
@@ -8,7 +8,12 @@ import (
 	libhttputil "github.com/Vonage/gosrvlib/pkg/httputil"
 	"github.com/Vonage/gosrvlib/pkg/logging"
 	"github.com/Vonage/gosrvlib/pkg/traceid"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
 	"github.com/Vonage/gosrvlib/pkg/uidc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -37,10 +42,13 @@ type MiddlewareArgs struct {
 type MiddlewareFn func(args MiddlewareArgs, next http.Handler) http.Handler
 
 // RequestInjectHandler wraps all incoming requests and injects a logger in the request scoped context.
+// The trace ID is read from the traceIDHeaderName header, or from the W3C traceparent or B3 headers (see traceid.FromHTTPRequest).
+// The W3C trace context ("traceparent" and "tracestate" headers) is extracted from the request,
+// and a server span is started with the OpenTelemetry global TracerProvider (see the tracing package).
 func RequestInjectHandler(logger *zap.Logger, traceIDHeaderName string, redactFn RedactFn, next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		reqTime := time.Now().UTC()
-		reqID := traceid.FromHTTPRequestHeader(r, traceIDHeaderName, uidc.NewID128())
+		reqID := traceid.FromHTTPRequest(r, traceIDHeaderName, uidc.NewID128())
 
 		l := logger.With(
 			zap.String(traceid.DefaultLogKey, reqID),
@@ -54,17 +62,48 @@ func RequestInjectHandler(logger *zap.Logger, traceIDHeaderName string, redactFn
 			zap.String("request_x_forwarded_for", r.Header.Get("X-Forwarded-For")),
 		)
 
+		if id, ok := libhttputil.ClientIdentityFromTLS(r.TLS); ok {
+			l = l.With(zap.String("request_client_subject", id.Subject))
+		}
+
 		if l.Check(zap.DebugLevel, "debug") != nil {
 			reqDump, _ := httputil.DumpRequest(r, true)
 			l = l.With(zap.String("request", redactFn(string(reqDump))))
 		}
 
-		ctx := r.Context()
+		ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Start(
+			ctx,
+			r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String(traceid.DefaultLogKey, reqID),
+			),
+		)
+		defer span.End()
+
 		ctx = libhttputil.WithRequestTime(ctx, reqTime)
 		ctx = traceid.NewContext(ctx, reqID)
 		ctx = logging.WithLogger(ctx, l)
+		ctx = logging.WithSpanContext(ctx)
+
+		if !span.IsRecording() {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		rw := libhttputil.NewResponseWriterWrapper(w)
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
 
-		next.ServeHTTP(w, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.response.status_code", rw.Status()))
+
+		if rw.Status() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.Status()))
+		}
 	}
 
 	return http.HandlerFunc(fn)
@@ -1,6 +1,8 @@
 package httpserver
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +14,13 @@ import (
 	"github.com/Vonage/gosrvlib/pkg/traceid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -89,3 +98,97 @@ func TestRequestInjectHandler(t *testing.T) {
 	// message
 	require.Equal(t, "injected", logEntry.Message)
 }
+
+func TestRequestInjectHandler_clientIdentity(t *testing.T) {
+	t.Parallel()
+
+	nextHandler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("injected")
+	})
+
+	ctx, logs := testutil.ContextWithLogObserver(zapcore.DebugLevel)
+	handler := RequestInjectHandler(logging.FromContext(ctx), traceid.DefaultHeader, redact.HTTPData, nextHandler)
+
+	client := newTestCert(t, "client", nil, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{client.cert}}}
+	handler.ServeHTTP(nil, req)
+
+	logEntries := logs.All()
+	require.Len(t, logEntries, 1, "expected only 1 log message")
+	require.Equal(t, "CN=client", logEntries[0].ContextMap()["request_client_subject"])
+}
+
+func TestRequestInjectHandler_traceContext(t *testing.T) {
+	t.Parallel()
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var spanID string
+
+	nextHandler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		sc := trace.SpanContextFromContext(r.Context())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+
+		spanID = sc.SpanID().String()
+
+		logging.FromContext(r.Context()).Info("injected")
+	})
+
+	ctx, logs := testutil.ContextWithLogObserver(zapcore.DebugLevel)
+	handler := RequestInjectHandler(logging.FromContext(ctx), traceid.DefaultHeader, redact.HTTPData, nextHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", traceparent)
+	handler.ServeHTTP(nil, req)
+
+	logEntries := logs.All()
+	require.Len(t, logEntries, 1, "expected only 1 log message")
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", logEntries[0].ContextMap()[logging.TraceIDLogKey])
+	require.Equal(t, spanID, logEntries[0].ContextMap()[logging.SpanIDLogKey])
+}
+
+func TestRequestInjectHandler_b3(t *testing.T) {
+	t.Parallel()
+
+	nextHandler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7", traceid.FromContext(r.Context(), ""))
+	})
+
+	handler := RequestInjectHandler(logging.NopLogger(), traceid.DefaultHeader, redact.HTTPData, nextHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(traceid.B3Header, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1")
+	handler.ServeHTTP(nil, req)
+}
+
+//nolint:paralleltest
+func TestRequestInjectHandler_span(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	handler := RequestInjectHandler(logging.NopLogger(), traceid.DefaultHeader, redact.HTTPData, nextHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", http.StatusServiceUnavailable))
+}
@@ -0,0 +1,440 @@
+package httpserver
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+)
+
+const (
+	// OpenAPIVersion is the version of the OpenAPI specification used by the generated documents.
+	OpenAPIVersion = "3.1.0"
+
+	openAPISchemaRefPrefix = "#/components/schemas/"
+	openAPIMimeJSON        = "application/json"
+)
+
+var (
+	openAPITimeType      = reflect.TypeFor[time.Time]()
+	openAPISchemaNameFix = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+)
+
+// RouteOpenAPI contains the optional OpenAPI metadata of a route.
+// The request and response schemas are generated by reflecting the Go types of the provided values.
+type RouteOpenAPI struct {
+	// OperationID is the unique identifier of the operation.
+	OperationID string
+
+	// Summary is a short summary of the operation.
+	// If empty, the route Description is used as summary,
+	// otherwise the route Description is used as operation description.
+	Summary string
+
+	// Tags is a list of tags used to group the operations.
+	Tags []string
+
+	// Parameters is a list of additional (query, header or cookie) parameters.
+	// The path parameters are automatically extracted from the route path.
+	Parameters []OpenAPIParameter
+
+	// Request is a value of the request body type (e.g.: MyRequest{}).
+	Request any
+
+	// RequestContentType is the request body media type. Defaults to "application/json".
+	RequestContentType string
+
+	// Responses maps the HTTP status codes to a value of the response body type (nil for no body).
+	Responses map[int]any
+
+	// ResponseContentType is the response body media type. Defaults to "application/json".
+	ResponseContentType string
+
+	// Deprecated marks the operation as deprecated.
+	Deprecated bool
+}
+
+// OpenAPIDocument is the root object of an OpenAPI document.
+type OpenAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents         `json:"components,omitempty"`
+}
+
+// OpenAPIInfo contains the metadata about the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem maps the lowercase HTTP methods to the operations available on a single path.
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation describes a single API operation on a path.
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+}
+
+// OpenAPIParameter describes a single operation parameter.
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIRequestBody describes a single request body.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a single response from an API Operation.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType provides the schema for a media type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIComponents holds the reusable schemas.
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas,omitempty"`
+}
+
+// OpenAPISchema is a subset of the JSON Schema used by OpenAPI 3.1.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+}
+
+// NewOpenAPIDocument builds an OpenAPI 3.1 document from the given routes.
+func NewOpenAPIDocument(info OpenAPIInfo, routes []Route) *OpenAPIDocument {
+	g := newOpenAPISchemaGenerator()
+
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]OpenAPIPathItem),
+	}
+
+	for _, r := range routes {
+		path, params := openAPIPath(r.Path)
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(OpenAPIPathItem)
+			doc.Paths[path] = item
+		}
+
+		item[strings.ToLower(r.Method)] = g.operation(r, params)
+	}
+
+	if len(g.schemas) > 0 {
+		doc.Components = &OpenAPIComponents{Schemas: g.schemas}
+	}
+
+	return doc
+}
+
+// openAPIPath converts the httprouter path into the OpenAPI format
+// (e.g.: "/items/:id" to "/items/{id}") and returns the path parameters.
+func openAPIPath(path string) (string, []OpenAPIParameter) {
+	segments := strings.Split(path, "/")
+	params := []OpenAPIParameter{}
+
+	for i, s := range segments {
+		if s == "" || (s[0] != ':' && s[0] != '*') {
+			continue
+		}
+
+		name := s[1:]
+		segments[i] = "{" + name + "}"
+
+		p := OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		}
+
+		if s[0] == '*' {
+			p.Description = "Catch-all parameter matching the remaining path."
+		}
+
+		params = append(params, p)
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+type openAPISchemaGenerator struct {
+	schemas map[string]*OpenAPISchema
+	names   map[reflect.Type]string
+}
+
+func newOpenAPISchemaGenerator() *openAPISchemaGenerator {
+	return &openAPISchemaGenerator{
+		schemas: make(map[string]*OpenAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (g *openAPISchemaGenerator) operation(r Route, pathParams []OpenAPIParameter) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		Summary:    r.Description,
+		Parameters: pathParams,
+		Responses:  make(map[string]OpenAPIResponse),
+	}
+
+	meta := r.OpenAPI
+	if meta == nil {
+		op.Responses["default"] = OpenAPIResponse{Description: "Default response"}
+		return op
+	}
+
+	op.OperationID = meta.OperationID
+	op.Tags = meta.Tags
+	op.Deprecated = meta.Deprecated
+	op.Parameters = append(op.Parameters, meta.Parameters...)
+
+	if meta.Summary != "" && meta.Summary != r.Description {
+		op.Summary = meta.Summary
+		op.Description = r.Description
+	}
+
+	if meta.Request != nil {
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  g.content(meta.RequestContentType, meta.Request),
+		}
+	}
+
+	for code, body := range meta.Responses {
+		resp := OpenAPIResponse{Description: http.StatusText(code)}
+
+		if body != nil {
+			resp.Content = g.content(meta.ResponseContentType, body)
+		}
+
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+
+	if len(op.Responses) == 0 {
+		op.Responses["default"] = OpenAPIResponse{Description: "Default response"}
+	}
+
+	return op
+}
+
+func (g *openAPISchemaGenerator) content(contentType string, v any) map[string]OpenAPIMediaType {
+	if contentType == "" {
+		contentType = openAPIMimeJSON
+	}
+
+	return map[string]OpenAPIMediaType{
+		contentType: {Schema: g.schema(reflect.TypeOf(v))},
+	}
+}
+
+// schema returns the JSON schema of the given type.
+// Named struct types are added to the components and referenced.
+func (g *openAPISchemaGenerator) schema(t reflect.Type) *OpenAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == openAPITimeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	if s := openAPIBasicSchema(t); s != nil {
+		return s
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+
+		return &OpenAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	}
+
+	// interfaces and other types accept any value
+	return &OpenAPISchema{}
+}
+
+func openAPIBasicSchema(t reflect.Type) *OpenAPISchema {
+	zero := float64(0)
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &OpenAPISchema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	}
+
+	return nil
+}
+
+// structRef returns a reference to the named struct schema or an inline schema for anonymous structs.
+func (g *openAPISchemaGenerator) structRef(t reflect.Type) *OpenAPISchema {
+	if t.Name() == "" {
+		return g.structSchema(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = g.schemaName(t)
+		g.names[t] = name
+		g.schemas[name] = &OpenAPISchema{} // placeholder for recursive types
+		g.schemas[name] = g.structSchema(t)
+	}
+
+	return &OpenAPISchema{Ref: openAPISchemaRefPrefix + name}
+}
+
+// schemaName returns a unique component name for the type.
+func (g *openAPISchemaGenerator) schemaName(t reflect.Type) string {
+	name := openAPISchemaNameFix.ReplaceAllString(t.Name(), "_")
+
+	if _, exists := g.schemas[name]; !exists {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	name = openAPISchemaNameFix.ReplaceAllString(pkg+"."+t.Name(), "_")
+
+	for i := 2; ; i++ {
+		if _, exists := g.schemas[name]; !exists {
+			return name
+		}
+
+		name = openAPISchemaNameFix.ReplaceAllString(pkg+"."+t.Name(), "_") + strconv.Itoa(i)
+	}
+}
+
+func (g *openAPISchemaGenerator) structSchema(t reflect.Type) *OpenAPISchema {
+	s := &OpenAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenAPISchema),
+	}
+
+	g.addStructFields(s, t)
+
+	return s
+}
+
+// addStructFields adds the struct fields as schema properties following the encoding/json rules.
+func (g *openAPISchemaGenerator) addStructFields(s *OpenAPISchema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, skip := openAPIFieldName(f)
+		if skip {
+			continue
+		}
+
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				g.addStructFields(s, ft)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(ft)
+
+		// sibling keywords of $ref (like description) are allowed in OpenAPI 3.1
+		fs.Description = f.Tag.Get("description")
+
+		s.Properties[name] = fs
+
+		if isOpenAPIFieldRequired(f) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// openAPIFieldName returns the JSON name of the field and true if the field must be skipped.
+func openAPIFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() && !f.Anonymous {
+		return "", true
+	}
+
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+
+	return name, false
+}
+
+// isOpenAPIFieldRequired returns true if the field is marked as required by the validator tags.
+func isOpenAPIFieldRequired(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func defaultOpenAPIHandler(info OpenAPIInfo, routes []Route) http.HandlerFunc {
+	doc := NewOpenAPIDocument(info, routes)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		httputil.SendJSON(r.Context(), w, http.StatusOK, doc)
+	}
+}
@@ -0,0 +1,225 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type openAPITestBase struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type openAPITestItem struct {
+	openAPITestBase
+
+	Name     string            `json:"name"               validate:"required,max=10" description:"Item name."`
+	Price    float64           `json:"price,omitempty"`
+	Count    uint              `json:"count"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Data     []byte            `json:"data"`
+	Parent   *openAPITestItem  `json:"parent,omitempty"`
+	Extra    any               `json:"extra"`
+	Ignored  string            `json:"-"`
+	NoTag    bool
+	internal string
+}
+
+type openAPITestItemList struct {
+	Items []openAPITestItem `json:"items"`
+}
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	t.Parallel()
+
+	routes := []Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/items/:id",
+			Description: "Get an item.",
+			OpenAPI: &RouteOpenAPI{
+				OperationID: "getItem",
+				Tags:        []string{"items"},
+				Responses: map[int]any{
+					http.StatusOK:       openAPITestItem{},
+					http.StatusNotFound: nil,
+				},
+			},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/items",
+			Description: "Create an item.",
+			OpenAPI: &RouteOpenAPI{
+				Summary:    "Create",
+				Request:    &openAPITestItem{},
+				Responses:  map[int]any{http.StatusCreated: openAPITestItemList{}},
+				Deprecated: true,
+				Parameters: []OpenAPIParameter{
+					{Name: "dry_run", In: "query", Schema: &OpenAPISchema{Type: "boolean"}},
+				},
+			},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/files/*filepath",
+			Description: "Get a file.",
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/items/:id",
+			OpenAPI: &RouteOpenAPI{},
+		},
+	}
+
+	doc := NewOpenAPIDocument(OpenAPIInfo{Title: "Test", Version: "1.2.3"}, routes)
+
+	require.Equal(t, OpenAPIVersion, doc.OpenAPI)
+	require.Equal(t, "Test", doc.Info.Title)
+	require.Len(t, doc.Paths, 3)
+
+	get := doc.Paths["/items/{id}"]["get"]
+	require.NotNil(t, get)
+	require.Equal(t, "getItem", get.OperationID)
+	require.Equal(t, "Get an item.", get.Summary)
+	require.Empty(t, get.Description)
+	require.Equal(t, []string{"items"}, get.Tags)
+	require.Len(t, get.Parameters, 1)
+	require.Equal(t, "id", get.Parameters[0].Name)
+	require.Equal(t, "path", get.Parameters[0].In)
+	require.True(t, get.Parameters[0].Required)
+	require.Equal(t, "#/components/schemas/openAPITestItem", get.Responses["200"].Content["application/json"].Schema.Ref)
+	require.Equal(t, "Not Found", get.Responses["404"].Description)
+	require.Empty(t, get.Responses["404"].Content)
+
+	del := doc.Paths["/items/{id}"]["delete"]
+	require.NotNil(t, del)
+	require.Contains(t, del.Responses, "default")
+
+	post := doc.Paths["/items"]["post"]
+	require.NotNil(t, post)
+	require.Equal(t, "Create", post.Summary)
+	require.Equal(t, "Create an item.", post.Description)
+	require.True(t, post.Deprecated)
+	require.Len(t, post.Parameters, 1)
+	require.True(t, post.RequestBody.Required)
+	require.Equal(t, "#/components/schemas/openAPITestItem", post.RequestBody.Content["application/json"].Schema.Ref)
+
+	files := doc.Paths["/files/{filepath}"]["get"]
+	require.NotNil(t, files)
+	require.Equal(t, "filepath", files.Parameters[0].Name)
+	require.NotEmpty(t, files.Parameters[0].Description)
+	require.Contains(t, files.Responses, "default")
+
+	require.NotNil(t, doc.Components)
+	require.Len(t, doc.Components.Schemas, 2)
+
+	item := doc.Components.Schemas["openAPITestItem"]
+	require.NotNil(t, item)
+	require.Equal(t, "object", item.Type)
+	require.Equal(t, []string{"name"}, item.Required)
+	require.Equal(t, &OpenAPISchema{Type: "integer", Format: "int64"}, item.Properties["id"])
+	require.Equal(t, &OpenAPISchema{Type: "string", Format: "date-time"}, item.Properties["created_at"])
+	require.Equal(t, &OpenAPISchema{Type: "string", Description: "Item name."}, item.Properties["name"])
+	require.Equal(t, &OpenAPISchema{Type: "number", Format: "double"}, item.Properties["price"])
+	require.Equal(t, "integer", item.Properties["count"].Type)
+	require.InDelta(t, 0, *item.Properties["count"].Minimum, 0)
+	require.Equal(t, &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "string"}}, item.Properties["tags"])
+	require.Equal(t, &OpenAPISchema{Type: "object", AdditionalProperties: &OpenAPISchema{Type: "string"}}, item.Properties["labels"])
+	require.Equal(t, &OpenAPISchema{Type: "string", Format: "byte"}, item.Properties["data"])
+	require.Equal(t, &OpenAPISchema{Ref: "#/components/schemas/openAPITestItem"}, item.Properties["parent"])
+	require.Equal(t, &OpenAPISchema{}, item.Properties["extra"])
+	require.Equal(t, &OpenAPISchema{Type: "boolean"}, item.Properties["NoTag"])
+	require.NotContains(t, item.Properties, "Ignored")
+	require.NotContains(t, item.Properties, "internal")
+
+	list := doc.Components.Schemas["openAPITestItemList"]
+	require.NotNil(t, list)
+	require.Equal(t, "#/components/schemas/openAPITestItem", list.Properties["items"].Items.Ref)
+
+	_, err := json.Marshal(doc)
+	require.NoError(t, err)
+}
+
+// openAPIPkgIndex is used to reference the package Index type where it is shadowed.
+type openAPIPkgIndex = Index
+
+func Test_openAPISchemaGenerator_schemaName(t *testing.T) {
+	t.Parallel()
+
+	type Index struct {
+		Name string `json:"name"`
+	}
+
+	g := newOpenAPISchemaGenerator()
+
+	require.Equal(t, "#/components/schemas/Index", g.schema(reflect.TypeOf(Index{})).Ref)
+	require.Equal(t, "#/components/schemas/Index", g.schema(reflect.TypeOf(&Index{})).Ref)
+	require.Equal(t, "#/components/schemas/httpserver.Index", g.schema(reflect.TypeOf(openAPIPkgIndex{})).Ref)
+
+	// force a second collision
+	g.schemas["httpserver.OpenAPIInfo"] = &OpenAPISchema{}
+	g.schemas["OpenAPIInfo"] = &OpenAPISchema{}
+	require.Equal(t, "#/components/schemas/httpserver.OpenAPIInfo2", g.schema(reflect.TypeOf(OpenAPIInfo{})).Ref)
+
+	require.Equal(t, "object", g.schema(reflect.TypeOf(struct{ A int }{})).Type)
+}
+
+type openAPIBinder struct{}
+
+func (b *openAPIBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/items/:id",
+			Description: "Get an item.",
+			Handler:     func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) },
+			OpenAPI: &RouteOpenAPI{
+				Responses: map[int]any{http.StatusOK: openAPITestItem{}},
+			},
+		},
+	}
+}
+
+func Test_loadRoutes_openAPI(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	require.NoError(t, WithEnableDefaultRoutes(OpenAPIRoute, IndexRoute, PingRoute)(cfg))
+	require.NoError(t, WithOpenAPIInfo(OpenAPIInfo{Title: "Service", Version: "2.0.0"})(cfg))
+
+	ctx := t.Context()
+	cfg.setRouter(ctx)
+	loadRoutes(ctx, zap.NewNop(), &openAPIBinder{}, cfg)
+
+	rr := httptest.NewRecorder()
+	cfg.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var doc OpenAPIDocument
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	require.Equal(t, "Service", doc.Info.Title)
+	require.Contains(t, doc.Paths, "/items/{id}")
+	require.Contains(t, doc.Paths, "/ping")
+	require.NotContains(t, doc.Paths, "/openapi.json")
+
+	rr = httptest.NewRecorder()
+	cfg.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var index Index
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &index))
+	require.Len(t, index.Routes, 3)
+	require.Equal(t, "/openapi.json", index.Routes[2].Path)
+}
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
@@ -55,6 +56,60 @@ func WithRequestTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithRateLimit sets a token-bucket rate limiter for all the service routes.
+// Rejected requests receive a 429 Too Many Requests status with the Retry-After header.
+// The rate limiter is shared across routes, unless overridden by the Route.RateLimiter value,
+// but each route has separate buckets.
+// The default routes (e.g. status, ping and metrics) and the 404, 405 and panic handlers are not rate limited.
+func WithRateLimit(rl *RateLimiter) Option {
+	return func(cfg *config) error {
+		if rl == nil {
+			return errors.New("rateLimiter is required")
+		}
+
+		cfg.rateLimiter = rl
+
+		return nil
+	}
+}
+
+// WithCORS sets the Cross-Origin Resource Sharing (CORS) policy for all routes.
+// The preflight OPTIONS requests are automatically answered for every bound path,
+// unless a custom OPTIONS route is defined for the same path, via the router
+// GlobalOPTIONS handler (not replaced if already set with a custom WithRouter).
+// The policy can be overridden by the Route.CORS value.
+// The wildcard origin "*" can't be combined with AllowCredentials.
+func WithCORS(policy *CORSPolicy) Option {
+	return func(cfg *config) error {
+		if policy == nil {
+			return errors.New("corsPolicy is required")
+		}
+
+		err := policy.validate()
+		if err != nil {
+			return err
+		}
+
+		cfg.corsPolicy = policy
+
+		return nil
+	}
+}
+
+// WithCompression enables the response compression for all routes.
+// The encoding (e.g.: br, gzip, deflate) is negotiated with the Accept-Encoding request header.
+func WithCompression(c *Compression) Option {
+	return func(cfg *config) error {
+		if c == nil {
+			return errors.New("compression is required")
+		}
+
+		cfg.compression = c
+
+		return nil
+	}
+}
+
 // WithServerReadHeaderTimeout sets the read header timeout.
 func WithServerReadHeaderTimeout(timeout time.Duration) Option {
 	return func(cfg *config) error {
@@ -107,6 +162,25 @@ func WithShutdownTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithShutdownDrainDelay sets the duration of the draining phase that precedes the server shutdown.
+// During this phase the ping and status default routes return 503 Service Unavailable,
+// the keep-alives are disabled, the HTTP/3 listener (if enabled) stops accepting new
+// connections, and the new TCP requests are still served,
+// so the load balancers have time to stop routing traffic to this instance.
+// StartServerCtx runs the drain before the shutdown timeout starts, while the
+// callers of HTTPServer.Shutdown must include the drain delay in the context deadline.
+func WithShutdownDrainDelay(delay time.Duration) Option {
+	return func(cfg *config) error {
+		if delay < 0 {
+			return errors.New("invalid shutdownDrainDelay")
+		}
+
+		cfg.shutdownDrainDelay = delay
+
+		return nil
+	}
+}
+
 // WithShutdownWaitGroup sets the shared waiting group to communicate externally when the server is shutdown.
 func WithShutdownWaitGroup(wg *sync.WaitGroup) Option {
 	return func(cfg *config) error {
@@ -151,6 +225,69 @@ func WithTLSCertData(pemCert, pemKey []byte) Option {
 	}
 }
 
+// WithTLSCertFiles enable TLS with the certificate and key loaded from the given PEM files.
+// The files are checked for changes during the TLS handshakes, at most every 30 seconds,
+// and the certificate is automatically reloaded without restarting the server.
+// If the updated files are invalid, the previous certificate is kept.
+func WithTLSCertFiles(certFile, keyFile string) Option {
+	return func(cfg *config) error {
+		cr, err := newTLSCertReloader(certFile, keyFile, defaultTLSCertReloadInterval)
+		if err != nil {
+			return fmt.Errorf("failed configuring TLS: %w", err)
+		}
+
+		cfg.tlsConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: cr.GetCertificate,
+		}
+
+		return nil
+	}
+}
+
+// WithTLSClientAuth enables the mutual TLS (mTLS) client certificate authentication.
+// The clientCAPEM contains the PEM encoded CA certificates used to verify the client certificates,
+// and authType sets the verification mode: tls.RequireAndVerifyClientCert or tls.VerifyClientCertIfGiven.
+// The verified client identity is available in the request context (see httputil.GetClientIdentity) and logs.
+// It requires TLS to be enabled with WithTLSCertData or WithTLSCertFiles.
+func WithTLSClientAuth(clientCAPEM []byte, authType tls.ClientAuthType) Option {
+	return func(cfg *config) error {
+		if authType != tls.RequireAndVerifyClientCert && authType != tls.VerifyClientCertIfGiven {
+			return fmt.Errorf("invalid TLS client authentication type: %s", authType)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(clientCAPEM) {
+			return errors.New("failed configuring mTLS: invalid client CA certificates")
+		}
+
+		cfg.tlsClientCAs = pool
+		cfg.tlsClientAuth = authType
+
+		return nil
+	}
+}
+
+// WithH2C enables HTTP/2 cleartext (h2c) connections on the non-TLS listener, alongside HTTP/1.
+// This is intended for internal traffic (e.g. service mesh) where TLS is terminated elsewhere.
+// It cannot be used in combination with WithTLSCertData.
+func WithH2C() Option {
+	return func(cfg *config) error {
+		cfg.enableH2C = true
+		return nil
+	}
+}
+
+// WithHTTP3 enables an experimental HTTP/3 (QUIC) listener on the UDP port matching the TCP server address.
+// The TCP responses advertise the HTTP/3 endpoint with the Alt-Svc header.
+// HTTP/3 requires TLS (see WithTLSCertData).
+func WithHTTP3() Option {
+	return func(cfg *config) error {
+		cfg.enableHTTP3 = true
+		return nil
+	}
+}
+
 // WithEnableDefaultRoutes sets the default routes to be enabled on the server.
 func WithEnableDefaultRoutes(ids ...DefaultRoute) Option {
 	return func(cfg *config) error {
@@ -159,7 +296,7 @@ func WithEnableDefaultRoutes(ids ...DefaultRoute) Option {
 	}
 }
 
-// WithEnableAllDefaultRoutes enables all default routes on the server.
+// WithEnableAllDefaultRoutes enables all default routes on the server, except the OpenAPIRoute.
 func WithEnableAllDefaultRoutes() Option {
 	return func(cfg *config) error {
 		cfg.defaultEnabledRoutes = allDefaultRoutes()
@@ -180,6 +317,19 @@ func WithIndexHandlerFunc(handler IndexHandlerFunc) Option {
 	}
 }
 
+// WithOpenAPIInfo sets the API metadata of the OpenAPI document served by the OpenAPIRoute.
+func WithOpenAPIInfo(info OpenAPIInfo) Option {
+	return func(cfg *config) error {
+		if info.Title == "" || info.Version == "" {
+			return errors.New("openAPIInfo title and version are required")
+		}
+
+		cfg.openAPIInfo = info
+
+		return nil
+	}
+}
+
 // WithIPHandlerFunc replaces the default ip handler function.
 func WithIPHandlerFunc(handler http.HandlerFunc) Option {
 	return func(cfg *config) error {
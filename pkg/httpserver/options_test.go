@@ -1,6 +1,7 @@
 package httpserver
 
 import (
+	"crypto/tls"
 	"net/http"
 	"reflect"
 	"sync"
@@ -53,6 +54,55 @@ func TestWithRequestTimeout(t *testing.T) {
 	require.Equal(t, v, cfg.requestTimeout)
 }
 
+func TestWithRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithRateLimit(nil)(cfg)
+	require.Error(t, err)
+
+	v, err := NewRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	err = WithRateLimit(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.rateLimiter)
+}
+
+func TestWithCORS(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithCORS(nil)(cfg)
+	require.Error(t, err)
+
+	err = WithCORS(&CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true})(cfg)
+	require.Error(t, err)
+
+	v := &CORSPolicy{AllowedOrigins: []string{"*"}}
+	err = WithCORS(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.corsPolicy)
+}
+
+func TestWithCompression(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithCompression(nil)(cfg)
+	require.Error(t, err)
+
+	v, err := NewCompression()
+	require.NoError(t, err)
+
+	err = WithCompression(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.compression)
+}
+
 func TestWithServerReadHeaderTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -109,6 +159,20 @@ func TestWithShutdownTimeout(t *testing.T) {
 	require.Equal(t, v, cfg.shutdownTimeout)
 }
 
+func TestWithShutdownDrainDelay(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithShutdownDrainDelay(-1)(cfg)
+	require.Error(t, err)
+
+	v := 5 * time.Second
+	err = WithShutdownDrainDelay(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.shutdownDrainDelay)
+}
+
 func TestWithShutdownWaitGroup(t *testing.T) {
 	t.Parallel()
 
@@ -204,6 +268,103 @@ YlAqGKDZ+A+l
 	}
 }
 
+func TestWithTLSCertFiles(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCert(t, "localhost", nil, false)
+	certFile, keyFile := writeTestCertFiles(t, t.TempDir(), c, time.Now())
+
+	cfg := defaultConfig()
+
+	err := WithTLSCertFiles(certFile, "missing.pem")(cfg)
+	require.Error(t, err)
+	require.Nil(t, cfg.tlsConfig)
+
+	err = WithTLSCertFiles(certFile, keyFile)(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.tlsConfig)
+
+	cert, err := cfg.tlsConfig.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, c.cert.Raw, cert.Leaf.Raw)
+}
+
+func TestWithTLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCert(t, "ca", nil, true)
+
+	tests := []struct {
+		name     string
+		caPEM    []byte
+		authType tls.ClientAuthType
+		wantErr  bool
+	}{
+		{
+			name:     "succeed with required client certificate",
+			caPEM:    ca.certPEM,
+			authType: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:     "succeed with optional client certificate",
+			caPEM:    ca.certPEM,
+			authType: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:     "fail with not verified client certificate",
+			caPEM:    ca.certPEM,
+			authType: tls.RequireAnyClientCert,
+			wantErr:  true,
+		},
+		{
+			name:     "fail with invalid CA",
+			caPEM:    []byte("invalid"),
+			authType: tls.RequireAndVerifyClientCert,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := defaultConfig()
+
+			err := WithTLSClientAuth(tt.caPEM, tt.authType)(cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, cfg.tlsClientCAs)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, cfg.tlsClientCAs)
+			require.Equal(t, tt.authType, cfg.tlsClientAuth)
+		})
+	}
+}
+
+func TestWithH2C(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithH2C()(cfg)
+	require.NoError(t, err)
+	require.True(t, cfg.enableH2C)
+}
+
+func TestWithHTTP3(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithHTTP3()(cfg)
+	require.NoError(t, err)
+	require.True(t, cfg.enableHTTP3)
+}
+
 func TestWithEnableDefaultRoutes(t *testing.T) {
 	t.Parallel()
 
@@ -220,6 +381,7 @@ func TestWithEnableAllDefaultRoutes(t *testing.T) {
 	err := WithEnableAllDefaultRoutes()(cfg)
 	require.NoError(t, err)
 	require.Equal(t, allDefaultRoutes(), cfg.defaultEnabledRoutes)
+	require.NotContains(t, cfg.defaultEnabledRoutes, OpenAPIRoute)
 }
 
 func TestWithIndexHandlerFunc(t *testing.T) {
@@ -240,6 +402,20 @@ func TestWithIndexHandlerFunc(t *testing.T) {
 	require.Equal(t, reflect.ValueOf(v).Pointer(), reflect.ValueOf(cfg.indexHandlerFunc).Pointer())
 }
 
+func TestWithOpenAPIInfo(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithOpenAPIInfo(OpenAPIInfo{})(cfg)
+	require.Error(t, err)
+
+	v := OpenAPIInfo{Title: "Test API", Version: "1.2.3", Description: "Test description."}
+	err = WithOpenAPIInfo(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.openAPIInfo)
+}
+
 func TestWithIPHandlerFunc(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,268 @@
+package httpserver
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitTask is the task name used to count the rejected requests with the metrics client.
+	rateLimitTask = "ratelimit"
+
+	// defaultRateLimitBucketTTL is the minimum time after which an idle bucket is removed.
+	defaultRateLimitBucketTTL = 1 * time.Minute
+)
+
+// RateLimitKeyFunc returns the key used to group the requests that share the same token bucket.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimitOption is a type alias for a function that configures the RateLimiter.
+type RateLimitOption func(rl *RateLimiter) error
+
+// RateLimiter is a token-bucket rate limiter that can be used as MiddlewareFn.
+// Each key (e.g.: client IP address) has a separate bucket of size "burst" that is refilled at "limit" tokens per second.
+type RateLimiter struct {
+	limit       rate.Limit
+	burst       int
+	keyFn       RateLimitKeyFunc
+	handlerFn   http.HandlerFunc
+	metric      metrics.Client
+	bucketTTL   time.Duration
+	mux         sync.Mutex
+	buckets     map[string]*rateLimitBucket
+	lastCleanup time.Time
+}
+
+type rateLimitBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a new token-bucket rate limiter.
+// The limit is the number of requests per second allowed for each key,
+// and burst is the maximum number of requests that can be served at once.
+func NewRateLimiter(limit float64, burst int, opts ...RateLimitOption) (*RateLimiter, error) {
+	if limit <= 0 {
+		return nil, errors.New("invalid rate limit")
+	}
+
+	if burst < 1 {
+		return nil, errors.New("invalid rate limit burst")
+	}
+
+	rl := &RateLimiter{
+		limit:       rate.Limit(limit),
+		burst:       burst,
+		keyFn:       RateLimitKeyRemoteIP,
+		handlerFn:   defaultRateLimitHandlerFunc,
+		metric:      &metrics.Default{},
+		bucketTTL:   defaultRateLimitBucketTTL,
+		buckets:     make(map[string]*rateLimitBucket),
+		lastCleanup: time.Now(),
+	}
+
+	for _, applyOpt := range opts {
+		err := applyOpt(rl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// An idle bucket can only be removed after it has been completely refilled,
+	// otherwise a client could reset its limit by pausing.
+	refill := time.Duration(float64(burst) / limit * float64(time.Second))
+	rl.bucketTTL = max(rl.bucketTTL, refill)
+
+	return rl, nil
+}
+
+// WithRateLimitKeyFunc sets the function used to extract the rate limiting key from the request.
+// The default is RateLimitKeyRemoteIP.
+func WithRateLimitKeyFunc(fn RateLimitKeyFunc) RateLimitOption {
+	return func(rl *RateLimiter) error {
+		if fn == nil {
+			return errors.New("rate limit keyFunc is required")
+		}
+
+		rl.keyFn = fn
+
+		return nil
+	}
+}
+
+// WithRateLimitHandlerFunc replaces the default handler called when a request is rejected.
+// The Retry-After header is already set when the handler is called.
+func WithRateLimitHandlerFunc(handler http.HandlerFunc) RateLimitOption {
+	return func(rl *RateLimiter) error {
+		if handler == nil {
+			return errors.New("rate limit handlerFunc is required")
+		}
+
+		rl.handlerFn = handler
+
+		return nil
+	}
+}
+
+// WithRateLimitMetrics sets the metrics client used to count the rejected requests.
+// The requests are counted with IncErrorCounter("ratelimit", "<METHOD> <PATH>", "429").
+func WithRateLimitMetrics(m metrics.Client) RateLimitOption {
+	return func(rl *RateLimiter) error {
+		if m == nil {
+			return errors.New("rate limit metrics client is required")
+		}
+
+		rl.metric = m
+
+		return nil
+	}
+}
+
+// WithRateLimitBucketTTL sets the minimum time after which the bucket of an idle key is removed.
+func WithRateLimitBucketTTL(ttl time.Duration) RateLimitOption {
+	return func(rl *RateLimiter) error {
+		if ttl <= 0 {
+			return errors.New("invalid rate limit bucketTTL")
+		}
+
+		rl.bucketTTL = ttl
+
+		return nil
+	}
+}
+
+// MiddlewareFn is the middleware handler function that enforces the rate limit.
+// Rejected requests receive a 429 Too Many Requests status with the Retry-After header.
+func (rl *RateLimiter) MiddlewareFn(args MiddlewareArgs, next http.Handler) http.Handler {
+	operation := strings.TrimSpace(args.Method + " " + args.Path)
+	code := strconv.Itoa(http.StatusTooManyRequests)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// each route has separate buckets when the limiter is shared across routes
+		delay, ok := rl.allow(operation+" "+rl.keyFn(r), time.Now())
+		if ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rl.metric.IncErrorCounter(rateLimitTask, operation, code)
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		rl.handlerFn(w, r)
+	})
+}
+
+// allow consumes a token from the bucket of the specified key.
+// If no token is available it returns false and the time to wait before retrying.
+func (rl *RateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	rl.cleanup(now)
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &rateLimitBucket{limiter: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.buckets[key] = b
+	}
+
+	b.lastSeen = now
+
+	res := b.limiter.ReserveN(now, 1)
+
+	delay := res.DelayFrom(now)
+	if delay == 0 {
+		return 0, true
+	}
+
+	res.CancelAt(now)
+
+	return delay, false
+}
+
+// cleanup removes the buckets that have been idle for longer than the bucket TTL.
+func (rl *RateLimiter) cleanup(now time.Time) {
+	if now.Sub(rl.lastCleanup) < rl.bucketTTL {
+		return
+	}
+
+	for k, b := range rl.buckets {
+		if now.Sub(b.lastSeen) >= rl.bucketTTL {
+			delete(rl.buckets, k)
+		}
+	}
+
+	rl.lastCleanup = now
+}
+
+// RateLimitKeyRemoteIP returns the IP address of the remote client as rate limiting key.
+func RateLimitKeyRemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// RateLimitKeyXForwardedFor returns the first (client) IP address of the X-Forwarded-For header as rate limiting key.
+// It falls back to the remote IP address if the header is not set.
+// This should only be used when the service runs behind a trusted proxy.
+func RateLimitKeyXForwardedFor(r *http.Request) string {
+	xff := r.Header.Get("X-Forwarded-For")
+	if xff == "" {
+		return RateLimitKeyRemoteIP(r)
+	}
+
+	ip, _, _ := strings.Cut(xff, ",")
+
+	return strings.TrimSpace(ip)
+}
+
+// RateLimitKeyAuthSubject returns the authenticated subject stored in the request context as rate limiting key
+// (see httputil.WithAuthSubject).
+// It falls back to the remote IP address for unauthenticated requests.
+func RateLimitKeyAuthSubject(r *http.Request) string {
+	subject, ok := httputil.GetAuthSubject(r)
+	if !ok || subject == "" {
+		return RateLimitKeyRemoteIP(r)
+	}
+
+	return "subject:" + subject
+}
+
+// RateLimitKeyHeader returns a function that uses the value of the specified header as rate limiting key.
+// It falls back to the remote IP address if the header is not set.
+func RateLimitKeyHeader(name string) RateLimitKeyFunc {
+	return func(r *http.Request) string {
+		v := r.Header.Get(name)
+		if v == "" {
+			return RateLimitKeyRemoteIP(r)
+		}
+
+		return "header:" + v
+	}
+}
+
+// routeRateLimiter returns the route rate limiter, if set, or the common one.
+func (c *config) routeRateLimiter(rRateLimiter *RateLimiter) *RateLimiter {
+	if rRateLimiter != nil {
+		return rRateLimiter
+	}
+
+	return c.rateLimiter
+}
+
+func defaultRateLimitHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	httputil.SendStatus(r.Context(), w, http.StatusTooManyRequests)
+}
@@ -0,0 +1,377 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testRateLimitMetrics struct {
+	metrics.Default
+
+	count atomic.Int32
+	task  atomic.Value
+	op    atomic.Value
+	code  atomic.Value
+}
+
+func (m *testRateLimitMetrics) IncErrorCounter(task, operation, code string) {
+	m.count.Add(1)
+	m.task.Store(task)
+	m.op.Store(operation)
+	m.code.Store(code)
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		limit   float64
+		burst   int
+		opts    []RateLimitOption
+		wantErr bool
+	}{
+		{
+			name:    "fail with invalid limit",
+			limit:   0,
+			burst:   1,
+			wantErr: true,
+		},
+		{
+			name:    "fail with invalid burst",
+			limit:   1,
+			burst:   0,
+			wantErr: true,
+		},
+		{
+			name:    "fail with option error",
+			limit:   1,
+			burst:   1,
+			opts:    []RateLimitOption{WithRateLimitKeyFunc(nil)},
+			wantErr: true,
+		},
+		{
+			name:  "succeed",
+			limit: 10,
+			burst: 5,
+			opts: []RateLimitOption{
+				WithRateLimitKeyFunc(RateLimitKeyXForwardedFor),
+				WithRateLimitHandlerFunc(defaultRateLimitHandlerFunc),
+				WithRateLimitMetrics(&metrics.Default{}),
+				WithRateLimitBucketTTL(5 * time.Minute),
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rl, err := NewRateLimiter(tt.limit, tt.burst, tt.opts...)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, rl)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, rl)
+		})
+	}
+}
+
+func TestNewRateLimiter_bucketTTL(t *testing.T) {
+	t.Parallel()
+
+	rl, err := NewRateLimiter(0.01, 10, WithRateLimitBucketTTL(1*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 1000*time.Second, rl.bucketTTL, "the TTL should not be lower than the bucket refill time")
+}
+
+func TestWithRateLimitKeyFunc(t *testing.T) {
+	t.Parallel()
+
+	rl := &RateLimiter{}
+
+	err := WithRateLimitKeyFunc(nil)(rl)
+	require.Error(t, err)
+
+	err = WithRateLimitKeyFunc(RateLimitKeyAuthSubject)(rl)
+	require.NoError(t, err)
+	require.NotNil(t, rl.keyFn)
+}
+
+func TestWithRateLimitHandlerFunc(t *testing.T) {
+	t.Parallel()
+
+	rl := &RateLimiter{}
+
+	err := WithRateLimitHandlerFunc(nil)(rl)
+	require.Error(t, err)
+
+	err = WithRateLimitHandlerFunc(defaultRateLimitHandlerFunc)(rl)
+	require.NoError(t, err)
+	require.NotNil(t, rl.handlerFn)
+}
+
+func TestWithRateLimitMetrics(t *testing.T) {
+	t.Parallel()
+
+	rl := &RateLimiter{}
+
+	err := WithRateLimitMetrics(nil)(rl)
+	require.Error(t, err)
+
+	v := &metrics.Default{}
+	err = WithRateLimitMetrics(v)(rl)
+	require.NoError(t, err)
+	require.Equal(t, v, rl.metric)
+}
+
+func TestWithRateLimitBucketTTL(t *testing.T) {
+	t.Parallel()
+
+	rl := &RateLimiter{}
+
+	err := WithRateLimitBucketTTL(0)(rl)
+	require.Error(t, err)
+
+	v := 3 * time.Minute
+	err = WithRateLimitBucketTTL(v)(rl)
+	require.NoError(t, err)
+	require.Equal(t, v, rl.bucketTTL)
+}
+
+func TestRateLimiter_MiddlewareFn(t *testing.T) {
+	t.Parallel()
+
+	m := &testRateLimitMetrics{}
+
+	rl, err := NewRateLimiter(1, 2, WithRateLimitMetrics(m))
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := rl.MiddlewareFn(MiddlewareArgs{Method: http.MethodGet, Path: "/limited"}, next)
+
+	doRequest := func(remoteAddr string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr.Result()
+	}
+
+	for range 2 {
+		resp := doRequest("192.0.2.1:1234")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	resp := doRequest("192.0.2.1:5678")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	require.Positive(t, retryAfter)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, int32(1), m.count.Load())
+	require.Equal(t, rateLimitTask, m.task.Load())
+	require.Equal(t, "GET /limited", m.op.Load())
+	require.Equal(t, "429", m.code.Load())
+
+	// a different client has its own bucket
+	resp = doRequest("192.0.2.2:1234")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
+type rateLimitBinder struct{}
+
+func (b *rateLimitBinder) BindHTTP(_ context.Context) []Route {
+	handler := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	return []Route{
+		{Method: http.MethodGet, Path: "/a", Handler: handler},
+		{Method: http.MethodGet, Path: "/b", Handler: handler},
+	}
+}
+
+func Test_loadRoutes_rateLimit(t *testing.T) {
+	t.Parallel()
+
+	rl, err := NewRateLimiter(0.001, 1)
+	require.NoError(t, err)
+
+	cfg := defaultConfig()
+	require.NoError(t, WithRateLimit(rl)(cfg))
+	require.NoError(t, WithEnableDefaultRoutes(PingRoute, StatusRoute)(cfg))
+
+	ctx := t.Context()
+	cfg.setRouter(ctx)
+	loadRoutes(ctx, zap.NewNop(), &rateLimitBinder{}, cfg)
+
+	doRequest := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rr := httptest.NewRecorder()
+		cfg.router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	require.Equal(t, http.StatusOK, doRequest("/a"))
+	require.Equal(t, http.StatusTooManyRequests, doRequest("/a"))
+
+	// each route has a separate bucket
+	require.Equal(t, http.StatusOK, doRequest("/b"))
+	require.Equal(t, http.StatusTooManyRequests, doRequest("/b"))
+
+	// the default routes and the not found handler are not rate limited
+	for range 3 {
+		require.Equal(t, http.StatusOK, doRequest("/ping"))
+		require.Equal(t, http.StatusOK, doRequest("/status"))
+		require.Equal(t, http.StatusNotFound, doRequest("/missing"))
+	}
+}
+
+func TestRateLimiter_allow(t *testing.T) {
+	t.Parallel()
+
+	rl, err := NewRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	delay, ok := rl.allow("a", now)
+	require.True(t, ok)
+	require.Zero(t, delay)
+
+	delay, ok = rl.allow("a", now)
+	require.False(t, ok)
+	require.Equal(t, 1*time.Second, delay)
+
+	// a rejected request does not consume tokens
+	delay, ok = rl.allow("a", now.Add(1*time.Second))
+	require.True(t, ok)
+	require.Zero(t, delay)
+}
+
+func TestRateLimiter_cleanup(t *testing.T) {
+	t.Parallel()
+
+	rl, err := NewRateLimiter(1, 1, WithRateLimitBucketTTL(1*time.Minute))
+	require.NoError(t, err)
+
+	now := rl.lastCleanup
+
+	_, _ = rl.allow("a", now)
+	_, _ = rl.allow("b", now.Add(30*time.Second))
+	require.Len(t, rl.buckets, 2)
+
+	_, _ = rl.allow("c", now.Add(61*time.Second))
+	require.Len(t, rl.buckets, 2)
+	require.NotContains(t, rl.buckets, "a")
+	require.Contains(t, rl.buckets, "b")
+	require.Contains(t, rl.buckets, "c")
+}
+
+func TestRateLimitKeyFuncs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		keyFn      RateLimitKeyFunc
+		remoteAddr string
+		headers    map[string]string
+		subject    string
+		want       string
+	}{
+		{
+			name:       "remote IP",
+			keyFn:      RateLimitKeyRemoteIP,
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "remote IP without port",
+			keyFn:      RateLimitKeyRemoteIP,
+			remoteAddr: "192.0.2.1",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "X-Forwarded-For",
+			keyFn:      RateLimitKeyXForwardedFor,
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": " 198.51.100.7, 192.0.2.1"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "X-Forwarded-For fallback",
+			keyFn:      RateLimitKeyXForwardedFor,
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "auth subject",
+			keyFn:      RateLimitKeyAuthSubject,
+			remoteAddr: "192.0.2.1:1234",
+			subject:    "user-1",
+			want:       "subject:user-1",
+		},
+		{
+			name:       "auth subject fallback",
+			keyFn:      RateLimitKeyAuthSubject,
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "header",
+			keyFn:      RateLimitKeyHeader("X-Api-Key"),
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Api-Key": "abc"},
+			want:       "header:abc",
+		},
+		{
+			name:       "header fallback",
+			keyFn:      RateLimitKeyHeader("X-Api-Key"),
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			if tt.subject != "" {
+				req = req.WithContext(httputil.WithAuthSubject(req.Context(), tt.subject))
+			}
+
+			require.Equal(t, tt.want, tt.keyFn(req))
+		})
+	}
+}
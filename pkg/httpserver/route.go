@@ -28,6 +28,17 @@ type Route struct {
 	// Timeout time limit after which a request receives a 503 Service Unavailable.
 	// If set, overrides the common value set with WithRequestTimeout.
 	Timeout time.Duration `json:"-"`
+
+	// RateLimiter is the token-bucket rate limiter applied to this route.
+	// If set, overrides the common rate limiter set with WithRateLimit.
+	RateLimiter *RateLimiter `json:"-"`
+
+	// CORS is the Cross-Origin Resource Sharing policy applied to this route.
+	// If set, overrides the common policy set with WithCORS.
+	CORS *CORSPolicy `json:"-"`
+
+	// OpenAPI contains the optional metadata used to describe this route in the OpenAPI document.
+	OpenAPI *RouteOpenAPI `json:"-"`
 }
 
 // Index contains the list of routes attached to the current service.
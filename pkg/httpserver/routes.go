@@ -20,6 +20,12 @@ const (
 	MetricsRoute       DefaultRoute = "metrics"
 	metricsHandlerPath string       = "/metrics"
 
+	// OpenAPIRoute is the identifier to enable the OpenAPI document handler.
+	// This route is not enabled by WithEnableAllDefaultRoutes,
+	// as it publishes the API description, and must be explicitly enabled with WithEnableDefaultRoutes.
+	OpenAPIRoute       DefaultRoute = "openapi"
+	openAPIHandlerPath string       = "/openapi.json"
+
 	// PingRoute is the identifier to enable the ping handler.
 	PingRoute       DefaultRoute = "ping"
 	pingHandlerPath string       = "/ping"
@@ -44,6 +50,15 @@ func allDefaultRoutes() []DefaultRoute {
 	}
 }
 
+// readinessHandler wraps the handler to report the draining phase, if enabled.
+func (c *config) readinessHandler(handler http.HandlerFunc) http.HandlerFunc {
+	if c.shutdownDrainDelay <= 0 {
+		return handler
+	}
+
+	return c.drain.readinessHandler(handler)
+}
+
 func newDefaultRoutes(cfg *config) []Route {
 	routes := make([]Route, 0, len(cfg.defaultEnabledRoutes)+1)
 
@@ -51,8 +66,8 @@ func newDefaultRoutes(cfg *config) []Route {
 		_, disableLogger := cfg.disableDefaultRouteLogger[id]
 
 		switch id {
-		case IndexRoute:
-			// The index route needs to access all the routes bound to the handler.
+		case IndexRoute, OpenAPIRoute:
+			// The index and OpenAPI routes need to access all the routes bound to the handler.
 		case IPRoute:
 			routes = append(routes, Route{
 				Method:        http.MethodGet,
@@ -73,7 +88,7 @@ func newDefaultRoutes(cfg *config) []Route {
 			routes = append(routes, Route{
 				Method:        http.MethodGet,
 				Path:          pingHandlerPath,
-				Handler:       cfg.pingHandlerFunc,
+				Handler:       cfg.readinessHandler(cfg.pingHandlerFunc),
 				DisableLogger: disableLogger,
 				Description:   "Ping this service.",
 			})
@@ -89,7 +104,7 @@ func newDefaultRoutes(cfg *config) []Route {
 			routes = append(routes, Route{
 				Method:        http.MethodGet,
 				Path:          statusHandlerPath,
-				Handler:       cfg.statusHandlerFunc,
+				Handler:       cfg.readinessHandler(cfg.statusHandlerFunc),
 				DisableLogger: disableLogger,
 				Description:   "Check this service health status.",
 			})
@@ -2,8 +2,10 @@ package httpserver
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -50,3 +52,21 @@ func Test_newDefaultRoutes(t *testing.T) {
 
 	require.Equal(t, 5, boundCount)
 }
+
+func Test_newDefaultRoutes_drain(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.defaultEnabledRoutes = []DefaultRoute{PingRoute, StatusRoute}
+	cfg.shutdownDrainDelay = time.Second
+	cfg.drain.draining.Store(true)
+
+	routes := newDefaultRoutes(cfg)
+	require.Len(t, routes, 2)
+
+	for _, r := range routes {
+		rr := httptest.NewRecorder()
+		r.Handler(rr, httptest.NewRequest(http.MethodGet, r.Path, nil))
+		require.Equal(t, http.StatusServiceUnavailable, rr.Code, r.Path)
+	}
+}
@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultTLSCertReloadInterval is the default minimum interval between checks for changed certificate files.
+const defaultTLSCertReloadInterval = 30 * time.Second
+
+// tlsCertReloader loads a TLS certificate from files and reloads it when the files change.
+type tlsCertReloader struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	certMod   time.Time
+	keyMod    time.Time
+	lastCheck time.Time
+}
+
+func newTLSCertReloader(certFile, keyFile string, checkInterval time.Duration) (*tlsCertReloader, error) {
+	cr := &tlsCertReloader{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		checkInterval: checkInterval,
+	}
+
+	certMod, keyMod, err := cr.modTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cr.load(certMod, keyMod)
+	if err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+// GetCertificate returns the current certificate and can be used as tls.Config.GetCertificate callback.
+// The certificate files are checked for changes at most once every check interval.
+func (cr *tlsCertReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(cr.lastCheck) >= cr.checkInterval {
+		cr.lastCheck = now
+
+		certMod, keyMod, err := cr.modTimes()
+		if err == nil && (!certMod.Equal(cr.certMod) || !keyMod.Equal(cr.keyMod)) {
+			// on error the previous certificate is kept (e.g. files partially written)
+			_ = cr.load(certMod, keyMod)
+		}
+	}
+
+	return cr.cert, nil
+}
+
+func (cr *tlsCertReloader) load(certMod, keyMod time.Time) error {
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed loading TLS certificate files: %w", err)
+	}
+
+	cr.cert = &cert
+	cr.certMod = certMod
+	cr.keyMod = keyMod
+
+	return nil
+}
+
+func (cr *tlsCertReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(cr.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed reading TLS certificate file: %w", err)
+	}
+
+	keyInfo, err := os.Stat(cr.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed reading TLS key file: %w", err)
+	}
+
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
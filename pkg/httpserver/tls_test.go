@@ -0,0 +1,91 @@
+package httpserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertFiles writes the certificate files with the specified modification time.
+func writeTestCertFiles(t *testing.T, dir string, c *testCert, mod time.Time) (string, string) {
+	t.Helper()
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, c.certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, c.keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(certFile, mod, mod))
+	require.NoError(t, os.Chtimes(keyFile, mod, mod))
+
+	return certFile, keyFile
+}
+
+func Test_newTLSCertReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertFiles(t, dir, newTestCert(t, "first", nil, false), time.Now())
+
+	_, err := newTLSCertReloader(filepath.Join(dir, "missing.pem"), keyFile, 0)
+	require.Error(t, err)
+
+	_, err = newTLSCertReloader(certFile, filepath.Join(dir, "missing.pem"), 0)
+	require.Error(t, err)
+
+	_, err = newTLSCertReloader(certFile, certFile, 0)
+	require.Error(t, err)
+
+	cr, err := newTLSCertReloader(certFile, keyFile, 0)
+	require.NoError(t, err)
+	require.NotNil(t, cr.cert)
+}
+
+func Test_tlsCertReloader_GetCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Now()
+
+	first := newTestCert(t, "first", nil, false)
+	certFile, keyFile := writeTestCertFiles(t, dir, first, now)
+
+	cr, err := newTLSCertReloader(certFile, keyFile, time.Hour)
+	require.NoError(t, err)
+
+	cert, err := cr.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "first", cert.Leaf.Subject.CommonName)
+
+	// the files are not checked before the interval
+	second := newTestCert(t, "second", nil, false)
+	writeTestCertFiles(t, dir, second, now.Add(time.Minute))
+
+	cert, err = cr.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "first", cert.Leaf.Subject.CommonName)
+
+	// reload after the interval
+	cr.checkInterval = 0
+
+	cert, err = cr.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "second", cert.Leaf.Subject.CommonName)
+
+	// invalid files keep the previous certificate
+	writeTestCertFiles(t, dir, &testCert{certPEM: second.certPEM, keyPEM: first.keyPEM}, now.Add(2*time.Minute))
+
+	cert, err = cr.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "second", cert.Leaf.Subject.CommonName)
+
+	// missing files keep the previous certificate
+	require.NoError(t, os.Remove(keyFile))
+
+	cert, err = cr.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "second", cert.Leaf.Subject.CommonName)
+}
@@ -0,0 +1,500 @@
+package httputil
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/validator"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/multierr"
+)
+
+const (
+	// DefaultMaxBodySize is the default maximum size in bytes of the request body read by DecodeRequest.
+	DefaultMaxBodySize = 1 << 20 // 1 MiB
+
+	// MimeApplicationForm contains the mime type string for URL-encoded form content.
+	MimeApplicationForm = "application/x-www-form-urlencoded"
+
+	// MimeMultipartForm contains the mime type string for multipart form content.
+	MimeMultipartForm = "multipart/form-data"
+
+	// Struct tags used by DecodeRequest to bind the request parameters.
+	tagPath   = "path"
+	tagQuery  = "query"
+	tagHeader = "header"
+	tagForm   = "form"
+)
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// StructValidator is the interface used by DecodeRequest to validate the decoded object (e.g.: *validator.Validator).
+type StructValidator interface {
+	ValidateStructCtx(ctx context.Context, obj any) error
+}
+
+// FieldError contains the details of an invalid request field.
+type FieldError struct {
+	// Field is the name (namespace) of the invalid field.
+	Field string `json:"field"`
+
+	// Rule is the rule that failed (e.g.: "required", "max", "type").
+	Rule string `json:"rule,omitempty"`
+
+	// Message is the human readable error message.
+	Message string `json:"message"`
+}
+
+// Error returns the field error message.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// DecodeError is the error returned by DecodeRequest.
+// It can be directly sent as response data (e.g.: with SendDecodeError or jsendx.SendDecodeError).
+type DecodeError struct {
+	// StatusCode is the suggested HTTP status code of the response (e.g.: 400, 413, 415).
+	StatusCode int `json:"-"`
+
+	// Message is the general error message.
+	Message string `json:"message"`
+
+	// Fields contains the details of the invalid fields (if any).
+	Fields []FieldError `json:"fields,omitempty"`
+
+	err error
+}
+
+// Error returns a string representation of the error.
+func (e *DecodeError) Error() string {
+	if e.err != nil {
+		return e.Message + ": " + e.err.Error()
+	}
+
+	return e.Message
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.err
+}
+
+// AsDecodeError converts a generic error into a *DecodeError.
+// Errors that are not a *DecodeError are mapped to a 400 Bad Request.
+func AsDecodeError(err error) *DecodeError {
+	if err == nil {
+		return nil
+	}
+
+	var de *DecodeError
+	if errors.As(err, &de) {
+		return de
+	}
+
+	return &DecodeError{StatusCode: http.StatusBadRequest, Message: "invalid request", err: err}
+}
+
+// SendDecodeError sends the error returned by DecodeRequest as JSON response.
+func SendDecodeError(ctx context.Context, w http.ResponseWriter, err error) {
+	de := AsDecodeError(err)
+	SendJSON(ctx, w, de.StatusCode, de)
+}
+
+// DecodeOption is a type alias for a function that configures DecodeRequest.
+type DecodeOption func(c *decodeConfig)
+
+type decodeConfig struct {
+	maxBodySize           int64
+	disallowUnknownFields bool
+	contentTypes          []string
+	validator             StructValidator
+}
+
+// WithDecodeMaxBodySize sets the maximum size in bytes of the request body (default DefaultMaxBodySize).
+func WithDecodeMaxBodySize(size int64) DecodeOption {
+	return func(c *decodeConfig) {
+		c.maxBodySize = size
+	}
+}
+
+// WithDecodeDisallowUnknownFields rejects JSON request bodies containing unknown fields.
+func WithDecodeDisallowUnknownFields() DecodeOption {
+	return func(c *decodeConfig) {
+		c.disallowUnknownFields = true
+	}
+}
+
+// WithDecodeContentTypes restricts the accepted request body media types
+// (e.g.: "application/json", "application/xml", "application/x-www-form-urlencoded", "multipart/form-data").
+func WithDecodeContentTypes(types ...string) DecodeOption {
+	return func(c *decodeConfig) {
+		c.contentTypes = types
+	}
+}
+
+// WithDecodeValidator sets the validator used to validate the decoded object (e.g.: *validator.Validator).
+func WithDecodeValidator(v StructValidator) DecodeOption {
+	return func(c *decodeConfig) {
+		c.validator = v
+	}
+}
+
+// DecodeRequest decodes and validates the HTTP request into a new object of type T.
+//
+// The request body is decoded according to the Content-Type header (JSON, XML or form).
+// Form values are bound to the struct fields with the "form" tag.
+// The path parameters, query parameters and headers are bound to the struct fields
+// with the "path", "query" and "header" tags respectively, and take precedence over the body values.
+//
+// The returned error is always a *DecodeError containing the suggested HTTP status code
+// and the structured field errors.
+func DecodeRequest[T any](r *http.Request, opts ...DecodeOption) (*T, error) {
+	cfg := &decodeConfig{maxBodySize: DefaultMaxBodySize}
+
+	for _, applyOpt := range opts {
+		applyOpt(cfg)
+	}
+
+	obj := new(T)
+
+	err := decodeBody(r, cfg, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bindParams(r, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.validator == nil {
+		return obj, nil
+	}
+
+	err = cfg.validator.ValidateStructCtx(r.Context(), obj)
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	return obj, nil
+}
+
+func decodeBody(r *http.Request, cfg *decodeConfig, obj any) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return &DecodeError{StatusCode: http.StatusUnsupportedMediaType, Message: "missing or invalid Content-Type", err: err}
+	}
+
+	if len(cfg.contentTypes) > 0 && !slices.Contains(cfg.contentTypes, mediaType) {
+		return &DecodeError{StatusCode: http.StatusUnsupportedMediaType, Message: "unsupported Content-Type: " + mediaType}
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, cfg.maxBodySize)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		err = decodeJSON(r.Body, cfg.disallowUnknownFields, obj)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		err = decodeXML(r.Body, obj)
+	case mediaType == MimeApplicationForm || mediaType == MimeMultipartForm:
+		err = decodeForm(r, cfg.maxBodySize, obj)
+	default:
+		return &DecodeError{StatusCode: http.StatusUnsupportedMediaType, Message: "unsupported Content-Type: " + mediaType}
+	}
+
+	return bodyError(err)
+}
+
+func decodeJSON(body io.Reader, disallowUnknownFields bool, obj any) error {
+	dec := json.NewDecoder(body)
+
+	if disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	err := dec.Decode(obj)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if dec.More() {
+		return errors.New("the body must contain a single JSON object")
+	}
+
+	return nil
+}
+
+func decodeXML(body io.Reader, obj any) error {
+	err := xml.NewDecoder(body).Decode(obj)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err //nolint:wrapcheck
+}
+
+func decodeForm(r *http.Request, maxBodySize int64, obj any) error {
+	var err error
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), MimeMultipartForm) {
+		err = r.ParseMultipartForm(maxBodySize)
+	} else {
+		err = r.ParseForm()
+	}
+
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return bindValues(obj, tagForm, func(name string) []string { return r.PostForm[name] })
+}
+
+// bodyError converts the body decoding errors.
+func bodyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var de *DecodeError
+	if errors.As(err, &de) {
+		return de
+	}
+
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return &DecodeError{StatusCode: http.StatusRequestEntityTooLarge, Message: "request body too large", err: err}
+	}
+
+	var (
+		typeErr *json.UnmarshalTypeError
+		fields  []FieldError
+	)
+
+	if errors.As(err, &typeErr) {
+		fields = []FieldError{{Field: typeErr.Field, Rule: "type", Message: "invalid type, expected " + typeErr.Type.String()}}
+	}
+
+	return &DecodeError{StatusCode: http.StatusBadRequest, Message: "invalid request body", Fields: fields, err: err}
+}
+
+// bindParams binds the path parameters, query parameters and headers.
+func bindParams(r *http.Request, obj any) error {
+	params := httprouter.ParamsFromContext(r.Context())
+	query := r.URL.Query()
+
+	sources := []struct {
+		tag    string
+		values func(name string) []string
+	}{
+		{tag: tagPath, values: func(name string) []string { return pathValues(params, name) }},
+		{tag: tagQuery, values: func(name string) []string { return query[name] }},
+		{tag: tagHeader, values: func(name string) []string { return r.Header.Values(name) }},
+	}
+
+	var err error
+
+	for _, src := range sources {
+		err = multierr.Append(err, bindValues(obj, src.tag, src.values))
+	}
+
+	if err != nil {
+		return &DecodeError{StatusCode: http.StatusBadRequest, Message: "invalid request parameters", Fields: fieldErrors(err), err: err}
+	}
+
+	return nil
+}
+
+func pathValues(params httprouter.Params, name string) []string {
+	for _, p := range params {
+		if p.Key == name {
+			return []string{strings.TrimLeft(p.Value, "/")}
+		}
+	}
+
+	return nil
+}
+
+// bindValues sets the struct fields with the specified tag using the values returned by the provided function.
+func bindValues(obj any, tag string, values func(name string) []string) error {
+	v := reflect.ValueOf(obj).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return bindStructValues(v, tag, values)
+}
+
+func bindStructValues(v reflect.Value, tag string, values func(name string) []string) error {
+	var err error
+
+	t := v.Type()
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			err = multierr.Append(err, bindStructValues(v.Field(i), tag, values))
+			continue
+		}
+
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		vals := values(name)
+		if len(vals) == 0 {
+			continue
+		}
+
+		if setErr := setFieldValue(v.Field(i), vals); setErr != nil {
+			err = multierr.Append(err, &FieldError{
+				Field:   name,
+				Rule:    "type",
+				Message: fmt.Sprintf("%s %s is invalid: %s", tag, name, setErr.Error()),
+			})
+		}
+	}
+
+	return err
+}
+
+func setFieldValue(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 && !fv.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+
+		for i, s := range vals {
+			if err := setScalarValue(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+
+		fv.Set(slice)
+
+		return nil
+	}
+
+	return setScalarValue(fv, vals[0])
+}
+
+//nolint:gocyclo,cyclop
+func setScalarValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+
+		return setScalarValue(fv.Elem(), s)
+	}
+
+	if tu, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s)) //nolint:wrapcheck
+	}
+
+	if fv.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fv.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch fv.Kind() { //nolint:exhaustive
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// fieldErrors extracts the field errors from a multierror.
+func fieldErrors(err error) []FieldError {
+	errs := multierr.Errors(err)
+	fields := make([]FieldError, 0, len(errs))
+
+	for _, e := range errs {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			fields = append(fields, *fe)
+			continue
+		}
+
+		var ve *validator.Error
+		if errors.As(e, &ve) {
+			// the validator already removed the root struct name from the namespace
+			field := ve.Namespace
+			if field == "" {
+				field = ve.Field
+			}
+
+			fields = append(fields, FieldError{Field: field, Rule: ve.Tag, Message: ve.Err})
+			continue
+		}
+
+		fields = append(fields, FieldError{Message: e.Error()})
+	}
+
+	return fields
+}
+
+func validationError(err error) error {
+	return &DecodeError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "request validation failed",
+		Fields:     fieldErrors(err),
+		err:        err,
+	}
+}
@@ -0,0 +1,367 @@
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/validator"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeTestEmbedded struct {
+	Trace string `header:"X-Trace"`
+}
+
+type decodeTestRequest struct {
+	decodeTestEmbedded
+
+	ID      int64          `path:"id"      validate:"required,min=1"`
+	Name    string         `form:"name"    json:"name"               validate:"required,max=5"  xml:"name"`
+	Count   *uint8         `form:"count"   json:"count,omitempty"    xml:"count,omitempty"`
+	Tags    []string       `json:"tags"    query:"tag"               xml:"tag"`
+	Enabled bool           `query:"enabled"`
+	Ratio   float32        `query:"ratio"`
+	Timeout time.Duration  `query:"timeout"`
+	IP      net.IP         `query:"ip"`
+	Nums    []int          `query:"num"`
+	Skip    string         `json:"-"       query:"-"`
+	Any     map[string]any `json:"any,omitempty" query:"any"`
+	private string
+}
+
+type decodeTestAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type decodeTestItem struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type decodeTestNestedRequest struct {
+	Address decodeTestAddress `json:"address"`
+	Items   []decodeTestItem  `json:"items"   validate:"dive"`
+}
+
+type decodeTestValidator struct {
+	err error
+}
+
+func (v *decodeTestValidator) ValidateStructCtx(_ context.Context, _ any) error {
+	return v.err
+}
+
+func newDecodeTestRequest(t *testing.T, target, contentType, body string) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+
+	return r
+}
+
+func withPathParams(r *http.Request, params ...httprouter.Param) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, httprouter.Params(params)))
+}
+
+func TestDecodeRequest(t *testing.T) {
+	t.Parallel()
+
+	val, err := validator.New(validator.WithFieldNameTag("json"))
+	require.NoError(t, err)
+
+	r := newDecodeTestRequest(t,
+		"/items/7?tag=a&tag=b&enabled=true&ratio=0.5&timeout=3s&ip=127.0.0.1&num=1&num=2",
+		"application/json; charset=utf-8",
+		`{"name":"alpha","count":3,"tags":["x"]}`,
+	)
+	r.Header.Set("X-Trace", "trace-123")
+	r = withPathParams(r, httprouter.Param{Key: "id", Value: "7"})
+
+	got, err := DecodeRequest[decodeTestRequest](r, WithDecodeValidator(val), WithDecodeDisallowUnknownFields())
+	require.NoError(t, err)
+	require.Equal(t, int64(7), got.ID)
+	require.Equal(t, "alpha", got.Name)
+	require.Equal(t, uint8(3), *got.Count)
+	require.Equal(t, []string{"a", "b"}, got.Tags)
+	require.True(t, got.Enabled)
+	require.InDelta(t, 0.5, got.Ratio, 0.0001)
+	require.Equal(t, 3*time.Second, got.Timeout)
+	require.Equal(t, "127.0.0.1", got.IP.String())
+	require.Equal(t, []int{1, 2}, got.Nums)
+	require.Equal(t, "trace-123", got.Trace)
+}
+
+func TestDecodeRequest_body(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []DecodeOption
+		wantName    string
+		wantStatus  int
+		wantFields  []FieldError
+	}{
+		{
+			name:     "empty body",
+			wantName: "",
+		},
+		{
+			name:        "JSON",
+			contentType: "application/json",
+			body:        `{"name":"json","unknown":1}`,
+			wantName:    "json",
+		},
+		{
+			name:        "JSON suffix",
+			contentType: "application/vnd.api+json",
+			body:        `{"name":"json"}`,
+			wantName:    "json",
+		},
+		{
+			name:        "XML",
+			contentType: "application/xml",
+			body:        `<request><name>xml</name></request>`,
+			wantName:    "xml",
+		},
+		{
+			name:        "form",
+			contentType: MimeApplicationForm,
+			body:        `name=form&count=2`,
+			wantName:    "form",
+		},
+		{
+			name:        "JSON with unknown field",
+			contentType: "application/json",
+			body:        `{"name":"json","unknown":1}`,
+			opts:        []DecodeOption{WithDecodeDisallowUnknownFields()},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "JSON with multiple objects",
+			contentType: "application/json",
+			body:        `{"name":"a"}{"name":"b"}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "JSON invalid type",
+			contentType: "application/json",
+			body:        `{"name":1}`,
+			wantStatus:  http.StatusBadRequest,
+			wantFields:  []FieldError{{Field: "name", Rule: "type", Message: "invalid type, expected string"}},
+		},
+		{
+			name:        "invalid XML",
+			contentType: "text/xml",
+			body:        `<request><name>`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "invalid form",
+			contentType: MimeApplicationForm,
+			body:        `count=300`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "invalid form encoding",
+			contentType: MimeApplicationForm,
+			body:        `name=%zz`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "body too large",
+			contentType: "application/json",
+			body:        `{"name":"too large"}`,
+			opts:        []DecodeOption{WithDecodeMaxBodySize(5)},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "missing content type",
+			body:       `{"name":"json"}`,
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			body:        `name`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "not allowed content type",
+			contentType: "application/xml",
+			body:        `<request><name>xml</name></request>`,
+			opts:        []DecodeOption{WithDecodeContentTypes("application/json")},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newDecodeTestRequest(t, "/", tt.contentType, tt.body)
+
+			got, err := DecodeRequest[decodeTestRequest](r, tt.opts...)
+			if tt.wantStatus != 0 {
+				require.Error(t, err)
+				require.Nil(t, got)
+
+				de := AsDecodeError(err)
+				require.Equal(t, tt.wantStatus, de.StatusCode)
+				require.Equal(t, tt.wantFields, de.Fields)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, got.Name)
+		})
+	}
+}
+
+func TestDecodeRequest_multipart(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("name", "multi"))
+	require.NoError(t, mw.Close())
+
+	r := newDecodeTestRequest(t, "/", mw.FormDataContentType(), buf.String())
+
+	got, err := DecodeRequest[decodeTestRequest](r)
+	require.NoError(t, err)
+	require.Equal(t, "multi", got.Name)
+
+	r = newDecodeTestRequest(t, "/", MimeMultipartForm, "invalid")
+
+	_, err = DecodeRequest[decodeTestRequest](r)
+	require.Error(t, err)
+}
+
+func TestDecodeRequest_params(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/?enabled=maybe&ratio=x&timeout=1y&ip=bad&num=a&any=1", nil)
+	r = withPathParams(r, httprouter.Param{Key: "id", Value: "x"})
+
+	_, err := DecodeRequest[decodeTestRequest](r)
+	require.Error(t, err)
+
+	de := AsDecodeError(err)
+	require.Equal(t, http.StatusBadRequest, de.StatusCode)
+	require.Equal(t, "invalid request parameters", de.Message)
+	require.Len(t, de.Fields, 7)
+	require.Equal(t, "id", de.Fields[0].Field)
+	require.Equal(t, "type", de.Fields[0].Rule)
+
+	// catch-all path parameters are trimmed
+	type catchAll struct {
+		Path string `path:"path"`
+	}
+
+	r = withPathParams(httptest.NewRequest(http.MethodGet, "/files/a/b", nil), httprouter.Param{Key: "path", Value: "/a/b"})
+
+	got, err := DecodeRequest[catchAll](r)
+	require.NoError(t, err)
+	require.Equal(t, "a/b", got.Path)
+
+	// non-struct types are decoded from the body only
+	r = newDecodeTestRequest(t, "/?a=1", "application/json", `["a","b"]`)
+
+	list, err := DecodeRequest[[]string](r)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, *list)
+}
+
+func TestDecodeRequest_validation(t *testing.T) {
+	t.Parallel()
+
+	val, err := validator.New(validator.WithFieldNameTag("json"))
+	require.NoError(t, err)
+
+	r := newDecodeTestRequest(t, "/", "application/json", `{"name":"toolong"}`)
+
+	_, err = DecodeRequest[decodeTestRequest](r, WithDecodeValidator(val))
+	require.Error(t, err)
+
+	de := AsDecodeError(err)
+	require.Equal(t, http.StatusBadRequest, de.StatusCode)
+	require.Equal(t, "request validation failed", de.Message)
+	require.Len(t, de.Fields, 2)
+	require.Equal(t, "ID", de.Fields[0].Field)
+	require.Equal(t, "required", de.Fields[0].Rule)
+	require.Equal(t, "name", de.Fields[1].Field)
+	require.Equal(t, "max", de.Fields[1].Rule)
+	require.NotEmpty(t, de.Fields[1].Message)
+
+	// nested struct and slice fields
+	r = newDecodeTestRequest(t, "/", "application/json", `{"address":{},"items":[{"name":"a"},{}]}`)
+
+	_, err = DecodeRequest[decodeTestNestedRequest](r, WithDecodeValidator(val))
+	require.Error(t, err)
+
+	de = AsDecodeError(err)
+	require.Len(t, de.Fields, 2)
+	require.Equal(t, "address.city", de.Fields[0].Field)
+	require.Equal(t, "required", de.Fields[0].Rule)
+	require.Contains(t, de.Fields[0].Message, "address.city")
+	require.Equal(t, "items[1].name", de.Fields[1].Field)
+	require.Equal(t, "required", de.Fields[1].Rule)
+	require.Contains(t, de.Fields[1].Message, "items[1].name")
+
+	r = newDecodeTestRequest(t, "/", "", "")
+
+	_, err = DecodeRequest[decodeTestRequest](r, WithDecodeValidator(&decodeTestValidator{err: errors.New("generic")}))
+	require.Error(t, err)
+	require.Equal(t, []FieldError{{Message: "generic"}}, AsDecodeError(err).Fields)
+}
+
+func TestAsDecodeError(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, AsDecodeError(nil))
+
+	err := errors.New("generic")
+	de := AsDecodeError(err)
+	require.Equal(t, http.StatusBadRequest, de.StatusCode)
+	require.ErrorIs(t, de, err)
+	require.Equal(t, "invalid request: generic", de.Error())
+
+	de2 := &DecodeError{StatusCode: http.StatusUnsupportedMediaType, Message: "unsupported"}
+	require.Equal(t, de2, AsDecodeError(de2))
+	require.Equal(t, "unsupported", de2.Error())
+}
+
+func TestSendDecodeError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	SendDecodeError(t.Context(), rr, &DecodeError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "invalid",
+		Fields:     []FieldError{{Field: "id", Rule: "required", Message: "id is required"}},
+	})
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var got DecodeError
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	require.Equal(t, "invalid", got.Message)
+	require.Equal(t, []FieldError{{Field: "id", Rule: "required", Message: "id is required"}}, got.Fields)
+}
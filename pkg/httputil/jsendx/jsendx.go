@@ -94,6 +94,13 @@ func Send(ctx context.Context, w http.ResponseWriter, statusCode int, info *AppI
 	httputil.SendJSON(ctx, w, statusCode, Wrap(statusCode, info, data))
 }
 
+// SendDecodeError sends the error returned by httputil.DecodeRequest wrapped in a JSendX container.
+// The response data contains the error message and the list of invalid fields.
+func SendDecodeError(ctx context.Context, w http.ResponseWriter, info *AppInfo, err error) {
+	de := httputil.AsDecodeError(err)
+	Send(ctx, w, de.StatusCode, info, de)
+}
+
 // DefaultNotFoundHandlerFunc http handler called when no matching route is found.
 func DefaultNotFoundHandlerFunc(info *AppInfo) http.HandlerFunc {
 	return http.HandlerFunc(
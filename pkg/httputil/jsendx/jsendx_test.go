@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/Vonage/gosrvlib/pkg/httputil"
 	"github.com/Vonage/gosrvlib/pkg/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -58,6 +59,44 @@ func TestSend(t *testing.T) {
 	Send(testutil.Context(), mockWriter, http.StatusOK, params, "message")
 }
 
+func TestSendDecodeError(t *testing.T) {
+	t.Parallel()
+
+	params := &AppInfo{
+		ProgramName:    "test",
+		ProgramVersion: "1.2.3",
+		ProgramRelease: "12345",
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/?id=abc", nil)
+
+	type request struct {
+		ID int `query:"id"`
+	}
+
+	_, err := httputil.DecodeRequest[request](req)
+	require.Error(t, err)
+
+	rr := httptest.NewRecorder()
+	SendDecodeError(testutil.Context(), rr, params, err)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp struct {
+		Status string               `json:"status"`
+		Code   int                  `json:"code"`
+		Data   httputil.DecodeError `json:"data"`
+	}
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, httputil.StatusFail, resp.Status)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Equal(t, "invalid request parameters", resp.Data.Message)
+	require.Len(t, resp.Data.Fields, 1)
+	require.Equal(t, "id", resp.Data.Fields[0].Field)
+	require.Equal(t, "type", resp.Data.Fields[0].Rule)
+}
+
 func TestDefaultNotFoundHandlerFunc(t *testing.T) {
 	t.Parallel()
 
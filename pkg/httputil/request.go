@@ -2,6 +2,7 @@ package httputil
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"math"
 	"net/http"
@@ -18,6 +19,37 @@ type timeCtxKey string
 // ReqTimeCtxKey is the Context key to retrieve the request time.
 const ReqTimeCtxKey = timeCtxKey("request_time")
 
+type subjectCtxKey string
+
+// AuthSubjectCtxKey is the Context key to retrieve the authenticated subject.
+const AuthSubjectCtxKey = subjectCtxKey("auth_subject")
+
+type clientIdentityCtxKey string
+
+// ClientIdentityCtxKey is the Context key to retrieve the verified TLS client identity.
+const ClientIdentityCtxKey = clientIdentityCtxKey("client_identity")
+
+// ClientIdentity contains the identity of a client authenticated with a verified TLS certificate (mTLS).
+type ClientIdentity struct {
+	// Subject is the distinguished name of the client certificate subject.
+	Subject string `json:"subject"`
+
+	// CommonName is the common name (CN) of the client certificate subject.
+	CommonName string `json:"common_name"`
+
+	// DNSNames contains the DNS Subject Alternative Names of the client certificate.
+	DNSNames []string `json:"dns_names,omitempty"`
+
+	// URIs contains the URI Subject Alternative Names of the client certificate (e.g.: SPIFFE IDs).
+	URIs []string `json:"uris,omitempty"`
+
+	// SerialNumber is the serial number of the client certificate.
+	SerialNumber string `json:"serial_number"`
+
+	// Issuer is the distinguished name of the client certificate issuer.
+	Issuer string `json:"issuer"`
+}
+
 // AddBasicAuth decorates the provided http.Request with Basic Authorization.
 func AddBasicAuth(apiKey, apiSecret string, r *http.Request) {
 	r.Header.Add("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(apiKey+":"+apiSecret)))
@@ -86,3 +118,62 @@ func GetRequestTimeFromContext(ctx context.Context) (time.Time, bool) {
 func GetRequestTime(r *http.Request) (time.Time, bool) {
 	return GetRequestTimeFromContext(r.Context())
 }
+
+// WithAuthSubject returns a new context with the added authenticated subject (e.g.: user or client identifier).
+func WithAuthSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, AuthSubjectCtxKey, subject)
+}
+
+// GetAuthSubjectFromContext returns the authenticated subject from the context.
+func GetAuthSubjectFromContext(ctx context.Context) (string, bool) {
+	v := ctx.Value(AuthSubjectCtxKey)
+	s, ok := v.(string)
+
+	return s, ok
+}
+
+// GetAuthSubject returns the authenticated subject from the http request.
+func GetAuthSubject(r *http.Request) (string, bool) {
+	return GetAuthSubjectFromContext(r.Context())
+}
+
+// ClientIdentityFromTLS returns the identity of the verified TLS client certificate, if any.
+func ClientIdentityFromTLS(cs *tls.ConnectionState) (*ClientIdentity, bool) {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	cert := cs.VerifiedChains[0][0]
+
+	uris := make([]string, 0, len(cert.URIs))
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+
+	return &ClientIdentity{
+		Subject:      cert.Subject.String(),
+		CommonName:   cert.Subject.CommonName,
+		DNSNames:     cert.DNSNames,
+		URIs:         uris,
+		SerialNumber: cert.SerialNumber.String(),
+		Issuer:       cert.Issuer.String(),
+	}, true
+}
+
+// WithClientIdentity returns a new context with the added verified TLS client identity.
+func WithClientIdentity(ctx context.Context, id *ClientIdentity) context.Context {
+	return context.WithValue(ctx, ClientIdentityCtxKey, id)
+}
+
+// GetClientIdentityFromContext returns the verified TLS client identity from the context.
+func GetClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	v := ctx.Value(ClientIdentityCtxKey)
+	id, ok := v.(*ClientIdentity)
+
+	return id, ok
+}
+
+// GetClientIdentity returns the verified TLS client identity from the http request.
+func GetClientIdentity(r *http.Request) (*ClientIdentity, bool) {
+	return GetClientIdentityFromContext(r.Context())
+}
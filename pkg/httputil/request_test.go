@@ -1,7 +1,11 @@
 package httputil
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -306,3 +310,93 @@ func TestGetRequestTime(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, testTime, outTime)
 }
+
+func TestGetAuthSubjectFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	_, ok := GetAuthSubjectFromContext(ctx)
+	require.False(t, ok)
+
+	ctx = WithAuthSubject(ctx, "user-123")
+
+	subject, ok := GetAuthSubjectFromContext(ctx)
+
+	require.True(t, ok)
+	require.Equal(t, "user-123", subject)
+}
+
+func TestGetAuthSubject(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithAuthSubject(t.Context(), "user-456")
+
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+
+	subject, ok := GetAuthSubject(r)
+
+	require.True(t, ok)
+	require.Equal(t, "user-456", subject)
+}
+
+func TestClientIdentityFromTLS(t *testing.T) {
+	t.Parallel()
+
+	_, ok := ClientIdentityFromTLS(nil)
+	require.False(t, ok)
+
+	_, ok = ClientIdentityFromTLS(&tls.ConnectionState{})
+	require.False(t, ok)
+
+	spiffe, err := url.Parse("spiffe://example.org/service")
+	require.NoError(t, err)
+
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "client", Organization: []string{"Example"}},
+		Issuer:       pkix.Name{CommonName: "ca"},
+		DNSNames:     []string{"client.example.org"},
+		URIs:         []*url.URL{spiffe},
+	}
+
+	id, ok := ClientIdentityFromTLS(&tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}})
+	require.True(t, ok)
+	require.Equal(t, &ClientIdentity{
+		Subject:      "CN=client,O=Example",
+		CommonName:   "client",
+		DNSNames:     []string{"client.example.org"},
+		URIs:         []string{"spiffe://example.org/service"},
+		SerialNumber: "42",
+		Issuer:       "CN=ca",
+	}, id)
+}
+
+func TestGetClientIdentityFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	_, ok := GetClientIdentityFromContext(ctx)
+	require.False(t, ok)
+
+	ctx = WithClientIdentity(ctx, &ClientIdentity{CommonName: "client"})
+
+	id, ok := GetClientIdentityFromContext(ctx)
+
+	require.True(t, ok)
+	require.Equal(t, "client", id.CommonName)
+}
+
+func TestGetClientIdentity(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithClientIdentity(t.Context(), &ClientIdentity{CommonName: "client"})
+
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+
+	id, ok := GetClientIdentity(r)
+
+	require.True(t, ok)
+	require.Equal(t, "client", id.CommonName)
+}
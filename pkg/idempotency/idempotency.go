@@ -0,0 +1,463 @@
+/*
+Package idempotency provides an HTTP middleware to make non-idempotent requests
+(e.g. POST) safely retryable, using the "Idempotency-Key" request header.
+
+The first request with a given key is executed and its full response (status
+code, headers and body) is recorded in a pluggable Store. Any retry with the
+same key receives the recorded response, marked with the "Idempotent-Replayed"
+header, without executing the handler again. A duplicate request received while
+the first one is still in progress is rejected with 409 Conflict, and a request
+reusing a key with a different payload is rejected with 422 Unprocessable
+Entity. Responses with a 5xx status code are not recorded, so the request can be
+retried. The successful responses with a body larger than the recording limit
+are not executed again: the retries are rejected with 409 Conflict.
+
+The lock held while a request is in progress is identified by a unique owner
+token and is periodically extended until the request completes. A request can
+only update or release its own lock, so it can't overwrite or release the lock
+acquired by another request after its own lock expired.
+
+Only the response headers set by the wrapped handler are recorded. The
+Content-Encoding, Content-Length and Vary headers are never recorded, as they
+may be set by outer middlewares (e.g. compression) that transform the body
+after it is recorded. The size of the request body is limited and larger
+requests are rejected with 413 Request Entity Too Large.
+
+The Store interface is implemented for:
+  - local memory (NewMemoryStore), only suitable for a single instance;
+  - Redis (NewRedisStore) on top of github.com/Vonage/gosrvlib/pkg/redis;
+  - Valkey (NewValkeyStore) on top of github.com/Vonage/gosrvlib/pkg/valkey;
+  - SQL databases (NewSQLStore) on top of github.com/Vonage/gosrvlib/pkg/sqlconn.
+
+The Middleware.MiddlewareFn method can be used as httpserver.MiddlewareFn,
+either for a single route or globally.
+*/
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/uidc"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHeader is the default request header containing the idempotency key.
+	DefaultHeader = "Idempotency-Key"
+
+	// ReplayedHeader is the response header set on the replayed responses.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultTTL is the default retention time of the recorded responses.
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultLockTTL is the default expiration time of the lock held while a request is in progress.
+	DefaultLockTTL = 1 * time.Minute
+
+	// DefaultMaxBodySize is the default maximum size in bytes of the recorded response body.
+	DefaultMaxBodySize = 1 << 20 // 1 MiB
+
+	// DefaultMaxRequestBodySize is the default maximum size in bytes of the request body.
+	DefaultMaxRequestBodySize = 1 << 20 // 1 MiB
+
+	// DefaultKeyPrefix is the default prefix of the keys in the store.
+	DefaultKeyPrefix = "idempotency:"
+
+	// maxKeyLength is the maximum length of the idempotency key header value.
+	maxKeyLength = 255
+)
+
+// ErrorHandlerFunc is the type of function called to send an error response.
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, statusCode int)
+
+// Response is the recorded HTTP response.
+type Response struct {
+	// StatusCode is the HTTP status code.
+	StatusCode int `json:"status_code"`
+
+	// Header contains the response headers.
+	Header http.Header `json:"header,omitempty"`
+
+	// Body is the response body.
+	Body []byte `json:"body,omitempty"`
+
+	// BodyTooLarge is true when the request has been completed but the body
+	// was too large to be recorded, so the response cannot be replayed.
+	BodyTooLarge bool `json:"body_too_large,omitempty"`
+}
+
+// unrecordedHeaders are the response headers that are never recorded,
+// as they can be set by outer middlewares transforming the recorded body.
+var unrecordedHeaders = []string{"Content-Encoding", "Content-Length", "Vary"}
+
+// record is the value stored for each key.
+type record struct {
+	// Fingerprint is the hash of the request method, path and body.
+	Fingerprint string `json:"fingerprint"`
+
+	// Response is the recorded response, or nil if the request is still in progress.
+	Response *Response `json:"response,omitempty"`
+}
+
+// Middleware is the idempotency HTTP middleware.
+type Middleware struct {
+	store        Store
+	header       string
+	methods      []string
+	ttl          time.Duration
+	lockTTL      time.Duration
+	maxBodySize  int
+	maxReqSize   int64
+	keyPrefix    string
+	required     bool
+	errorHandler ErrorHandlerFunc
+}
+
+// New creates a new idempotency middleware with the given store.
+// By default the middleware applies only to the POST and PATCH requests containing the Idempotency-Key header.
+func New(store Store, opts ...Option) (*Middleware, error) {
+	if store == nil {
+		return nil, errors.New("the idempotency store is required")
+	}
+
+	m := &Middleware{
+		store:        store,
+		header:       DefaultHeader,
+		methods:      []string{http.MethodPost, http.MethodPatch},
+		ttl:          DefaultTTL,
+		lockTTL:      DefaultLockTTL,
+		maxBodySize:  DefaultMaxBodySize,
+		maxReqSize:   DefaultMaxRequestBodySize,
+		keyPrefix:    DefaultKeyPrefix,
+		errorHandler: defaultErrorHandler,
+	}
+
+	for _, applyOpt := range opts {
+		err := applyOpt(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// MiddlewareFn is the middleware handler function that can be used as httpserver.MiddlewareFn.
+func (m *Middleware) MiddlewareFn(args httpserver.MiddlewareArgs, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.isMethodEnabled(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.Header.Get(m.header)
+
+		if key == "" {
+			if m.required {
+				m.errorHandler(w, r, http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			m.errorHandler(w, r, http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, m.maxReqSize))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				m.errorHandler(w, r, http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			m.errorHandler(w, r, http.StatusBadRequest)
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		m.serve(w, r, next, m.storeKey(args.Path, r, key), fingerprint(r, body))
+	})
+}
+
+func (m *Middleware) serve(w http.ResponseWriter, r *http.Request, next http.Handler, key, fp string) {
+	ctx := r.Context()
+	l := logging.FromContext(ctx)
+
+	lock, _ := json.Marshal(&record{Fingerprint: fp}) //nolint:errchkjson
+
+	// the owner token ensures that only this request can update or release the lock
+	owner := uidc.NewID128()
+
+	acquired, err := m.store.SetNX(ctx, key, owner, lock, m.lockTTL)
+	if err != nil {
+		l.Error("unable to lock the idempotency key", zap.Error(err))
+		m.errorHandler(w, r, http.StatusInternalServerError)
+
+		return
+	}
+
+	if !acquired {
+		m.replay(w, r, key, fp)
+		return
+	}
+
+	// the store operations must complete even if the client disconnects
+	storeCtx := context.WithoutCancel(ctx)
+	completed := false
+
+	// the lock is extended while the request is in progress
+	stopRefresh := m.refreshLock(storeCtx, key, owner, lock)
+
+	defer func() {
+		stopRefresh()
+
+		if completed {
+			return
+		}
+
+		// release the lock on failure so the request can be retried
+		err := m.store.Del(storeCtx, key, owner)
+		if err != nil {
+			l.Error("unable to release the idempotency key", zap.Error(err))
+		}
+	}()
+
+	rw := httputil.NewResponseWriterWrapper(w)
+	buf := &limitedBuffer{limit: m.maxBodySize}
+	rw.Tee(buf)
+
+	before := w.Header().Clone()
+
+	next.ServeHTTP(rw, r)
+
+	stopRefresh()
+
+	status := rw.Status()
+	if status == 0 {
+		// the handler has not written anything
+		status = http.StatusOK
+	}
+
+	if status >= http.StatusInternalServerError {
+		return
+	}
+
+	resp := &Response{
+		StatusCode: status,
+		Header:     handlerHeader(before, rw.Header()),
+		Body:       buf.Bytes(),
+	}
+
+	if buf.exceeded {
+		// the request must not be executed again even if the response can't be replayed
+		resp.Body = nil
+		resp.BodyTooLarge = true
+	}
+
+	rec := &record{
+		Fingerprint: fp,
+		Response:    resp,
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		l.Error("unable to encode the idempotent response", zap.Error(err))
+		return
+	}
+
+	stored, err := m.store.Set(storeCtx, key, owner, data, m.ttl)
+	if err != nil {
+		l.Error("unable to store the idempotent response", zap.Error(err))
+		return
+	}
+
+	if !stored {
+		l.Error("unable to store the idempotent response: the idempotency key lock has been lost")
+		return
+	}
+
+	completed = true
+}
+
+// refreshLock periodically extends the expiration time of the lock held by the owner,
+// until the returned function is called. The returned function waits for the refresh to stop,
+// so the lock is not updated after it returns, and it can be called multiple times.
+func (m *Middleware) refreshLock(ctx context.Context, key, owner string, lock []byte) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(max(m.lockTTL/3, time.Millisecond))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ok, err := m.store.Set(ctx, key, owner, lock, m.lockTTL)
+				if err != nil {
+					logging.FromContext(ctx).Error("unable to refresh the idempotency key lock", zap.Error(err))
+					continue
+				}
+
+				if !ok {
+					logging.FromContext(ctx).Warn("the idempotency key lock has been lost")
+					return
+				}
+			}
+		}
+	}()
+
+	return sync.OnceFunc(func() {
+		close(done)
+		<-stopped
+	})
+}
+
+// replay sends the recorded response or an error if the key is in use.
+func (m *Middleware) replay(w http.ResponseWriter, r *http.Request, key, fp string) {
+	data, found, err := m.store.Get(r.Context(), key)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("unable to retrieve the idempotency key", zap.Error(err))
+		m.errorHandler(w, r, http.StatusInternalServerError)
+
+		return
+	}
+
+	rec := &record{}
+
+	if found {
+		err = json.Unmarshal(data, rec)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("unable to decode the idempotent response", zap.Error(err))
+			m.errorHandler(w, r, http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	switch {
+	case !found || rec.Response == nil:
+		// the original request is still in progress
+		m.errorHandler(w, r, http.StatusConflict)
+	case rec.Fingerprint != fp:
+		// the key has been reused with a different request
+		m.errorHandler(w, r, http.StatusUnprocessableEntity)
+	case rec.Response.BodyTooLarge:
+		// the request has been completed but the response can't be replayed
+		m.errorHandler(w, r, http.StatusConflict)
+	default:
+		h := w.Header()
+
+		for k, v := range rec.Response.Header {
+			h[k] = v
+		}
+
+		h.Set(ReplayedHeader, "true")
+		w.WriteHeader(rec.Response.StatusCode)
+		_, _ = w.Write(rec.Response.Body)
+	}
+}
+
+// limitedBuffer records the written data up to the limit.
+// When the limit is exceeded, the recorded data is discarded and the following writes are ignored.
+type limitedBuffer struct {
+	bytes.Buffer
+
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.exceeded {
+		return len(p), nil
+	}
+
+	if b.Len()+len(p) > b.limit {
+		b.exceeded = true
+		b.Buffer = bytes.Buffer{}
+
+		return len(p), nil
+	}
+
+	return b.Buffer.Write(p) //nolint:wrapcheck
+}
+
+// handlerHeader returns the response headers added or changed by the handler.
+func handlerHeader(before, after http.Header) http.Header {
+	h := make(http.Header, len(after))
+
+	for k, v := range after {
+		if !slices.Equal(before[k], v) {
+			h[k] = slices.Clone(v)
+		}
+	}
+
+	for _, k := range unrecordedHeaders {
+		h.Del(k)
+	}
+
+	return h
+}
+
+func (m *Middleware) isMethodEnabled(method string) bool {
+	for _, v := range m.methods {
+		if v == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// storeKey returns the store key scoped by route and authenticated subject.
+func (m *Middleware) storeKey(path string, r *http.Request, key string) string {
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	subject, _ := httputil.GetAuthSubject(r)
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{r.Method, path, subject, key}, "\n")))
+
+	return m.keyPrefix + hex.EncodeToString(sum[:])
+}
+
+// fingerprint returns the hash of the request method, URL and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n", r.Method, r.URL.RequestURI())
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, statusCode int) {
+	httputil.SendStatus(r.Context(), w, statusCode)
+}
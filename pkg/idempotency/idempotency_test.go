@@ -0,0 +1,624 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/stretchr/testify/require"
+)
+
+type errStore struct {
+	MemoryStore
+
+	setNXErr error
+	setErr   error
+	setLost  bool
+	getErr   error
+	delErr   error
+	getValue []byte
+}
+
+func (s *errStore) SetNX(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	if s.setNXErr != nil {
+		return false, s.setNXErr
+	}
+
+	return s.MemoryStore.SetNX(ctx, key, owner, value, ttl)
+}
+
+func (s *errStore) Set(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	if s.setErr != nil {
+		return false, s.setErr
+	}
+
+	if s.setLost {
+		return false, nil
+	}
+
+	return s.MemoryStore.Set(ctx, key, owner, value, ttl)
+}
+
+func (s *errStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if s.getErr != nil {
+		return nil, false, s.getErr
+	}
+
+	if s.getValue != nil {
+		return s.getValue, true, nil
+	}
+
+	return s.MemoryStore.Get(ctx, key)
+}
+
+func (s *errStore) Del(ctx context.Context, key, owner string) error {
+	if s.delErr != nil {
+		return s.delErr
+	}
+
+	return s.MemoryStore.Del(ctx, key, owner)
+}
+
+func newErrStore() *errStore {
+	return &errStore{MemoryStore: MemoryStore{entries: make(map[string]memoryEntry), lastCleanup: time.Now()}}
+}
+
+func testHandler(calls *int, mux *sync.Mutex, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		*calls++
+		n := *calls
+		mux.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("X-Call", strings.Repeat("#", n))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("response:" + string(body)))
+	})
+}
+
+func doRequest(h http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/items", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(DefaultHeader, key)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	return rr
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	require.Equal(t, DefaultHeader, m.header)
+	require.Equal(t, []string{http.MethodPost, http.MethodPatch}, m.methods)
+	require.Equal(t, DefaultTTL, m.ttl)
+	require.Equal(t, DefaultLockTTL, m.lockTTL)
+	require.Equal(t, DefaultMaxBodySize, m.maxBodySize)
+	require.Equal(t, int64(DefaultMaxRequestBodySize), m.maxReqSize)
+	require.Equal(t, DefaultKeyPrefix, m.keyPrefix)
+	require.False(t, m.required)
+
+	m, err = New(nil)
+	require.Error(t, err)
+	require.Nil(t, m)
+
+	m, err = New(NewMemoryStore(), WithHeader(""))
+	require.Error(t, err)
+	require.Nil(t, m)
+}
+
+func TestMiddleware_MiddlewareFn(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	var (
+		calls int
+		mux   sync.Mutex
+	)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{Path: "/items"}, testHandler(&calls, &mux, http.StatusCreated))
+
+	// first request is executed
+	rr := doRequest(h, http.MethodPost, "key-1", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "response:alpha", rr.Body.String())
+	require.Equal(t, "#", rr.Header().Get("X-Call"))
+	require.Empty(t, rr.Header().Get(ReplayedHeader))
+
+	// retry is replayed
+	rr = doRequest(h, http.MethodPost, "key-1", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "response:alpha", rr.Body.String())
+	require.Equal(t, "#", rr.Header().Get("X-Call"))
+	require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+	require.Equal(t, 1, calls)
+
+	// same key with a different payload
+	rr = doRequest(h, http.MethodPost, "key-1", "beta")
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	require.Equal(t, 1, calls)
+
+	// different key
+	rr = doRequest(h, http.MethodPost, "key-2", "beta")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, 2, calls)
+
+	// same key with a different method is a different scope
+	rr = doRequest(h, http.MethodPatch, "key-1", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, 3, calls)
+
+	// no key
+	rr = doRequest(h, http.MethodPost, "", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	rr = doRequest(h, http.MethodPost, "", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, 5, calls)
+
+	// method not enabled
+	rr = doRequest(h, http.MethodPut, "key-1", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, 6, calls)
+
+	// key too long
+	rr = doRequest(h, http.MethodPost, strings.Repeat("k", maxKeyLength+1), "alpha")
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, 6, calls)
+}
+
+func TestMiddleware_MiddlewareFn_authSubject(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	var (
+		calls int
+		mux   sync.Mutex
+	)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, testHandler(&calls, &mux, http.StatusOK))
+
+	for _, subject := range []string{"alice", "bob", "alice"} {
+		r := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("data"))
+		r.Header.Set(DefaultHeader, "key")
+		r = r.WithContext(httputil.WithAuthSubject(r.Context(), subject))
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	require.Equal(t, 2, calls)
+}
+
+func TestMiddleware_MiddlewareFn_required(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore(), WithRequired())
+	require.NoError(t, err)
+
+	var (
+		calls int
+		mux   sync.Mutex
+	)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, testHandler(&calls, &mux, http.StatusOK))
+
+	rr := doRequest(h, http.MethodPost, "", "alpha")
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, 0, calls)
+
+	rr = doRequest(h, http.MethodGet, "", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, 1, calls)
+}
+
+func TestMiddleware_MiddlewareFn_notRecorded(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	var (
+		calls int
+		mux   sync.Mutex
+	)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, testHandler(&calls, &mux, http.StatusServiceUnavailable))
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	rr = doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.Empty(t, rr.Header().Get(ReplayedHeader))
+	require.Equal(t, 2, calls)
+}
+
+func TestMiddleware_MiddlewareFn_bodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore(), WithMaxBodySize(3))
+	require.NoError(t, err)
+
+	var (
+		calls int
+		mux   sync.Mutex
+	)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, testHandler(&calls, &mux, http.StatusOK))
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "response:alpha", rr.Body.String())
+
+	// the completed request is not executed again
+	rr = doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Empty(t, rr.Header().Get(ReplayedHeader))
+	require.Equal(t, 1, calls)
+
+	rr = doRequest(h, http.MethodPost, "key", "beta")
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	require.Equal(t, 1, calls)
+}
+
+func Test_limitedBuffer(t *testing.T) {
+	t.Parallel()
+
+	b := &limitedBuffer{limit: 5}
+
+	n, err := b.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.False(t, b.exceeded)
+
+	n, err = b.Write([]byte("de"))
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, "abcde", b.String())
+
+	n, err = b.Write([]byte("f"))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.True(t, b.exceeded)
+	require.Zero(t, b.Len())
+
+	n, err = b.Write([]byte("ghi"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Zero(t, b.Len())
+}
+
+func TestMiddleware_MiddlewareFn_noWrite(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	calls := 0
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		calls++
+	}))
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+	require.Empty(t, rr.Body.String())
+	require.Equal(t, 1, calls)
+}
+
+func TestMiddleware_MiddlewareFn_outerHeaders(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Inner", "inner")
+		w.Header().Set("Content-Length", "2")
+		w.Header().Set("Vary", "Accept-Encoding")
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	// the outer middleware sets headers before and after the idempotency middleware
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, inner)
+	outer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Outer", "outer")
+		h.ServeHTTP(&encodingWriter{ResponseWriter: w}, r)
+	})
+
+	rr := doRequest(outer, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+
+	m2 := m.MiddlewareFn(httpserver.MiddlewareArgs{}, http.NotFoundHandler())
+
+	rr = doRequest(m2, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+	require.Equal(t, "inner", rr.Header().Get("X-Inner"))
+	require.Empty(t, rr.Header().Get("X-Outer"))
+	require.Empty(t, rr.Header().Get("Content-Encoding"))
+	require.Empty(t, rr.Header().Get("Content-Length"))
+	require.Empty(t, rr.Header().Get("Vary"))
+	require.Equal(t, "OK", rr.Body.String())
+}
+
+// encodingWriter sets the Content-Encoding header on the first write, like a compression middleware.
+type encodingWriter struct {
+	http.ResponseWriter
+}
+
+func (w *encodingWriter) WriteHeader(statusCode int) {
+	w.Header().Set("Content-Encoding", "gzip")
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *encodingWriter) Write(b []byte) (int, error) {
+	w.Header().Set("Content-Encoding", "gzip")
+	return w.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+func TestMiddleware_MiddlewareFn_requestTooLarge(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore(), WithMaxRequestBodySize(3))
+	require.NoError(t, err)
+
+	var (
+		calls int
+		mux   sync.Mutex
+	)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, testHandler(&calls, &mux, http.StatusOK))
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	require.Equal(t, 0, calls)
+
+	rr = doRequest(h, http.MethodPost, "key", "abc")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, 1, calls)
+}
+
+func TestMiddleware_MiddlewareFn_concurrent(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, next)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		rr := doRequest(h, http.MethodPost, "key", "alpha")
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}()
+
+	<-started
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	close(release)
+	wg.Wait()
+
+	rr = doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_MiddlewareFn_storeErrors(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("store error")
+
+	tests := []struct {
+		name       string
+		setup      func(s *errStore)
+		key        string
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "lock error",
+			setup:      func(s *errStore) { s.setNXErr = errTest },
+			key:        "key",
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+		{
+			name: "set error",
+			setup: func(s *errStore) {
+				s.setErr = errTest
+				s.delErr = errTest
+			},
+			key:        "key-2",
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "lost lock",
+			setup:      func(s *errStore) { s.setLost = true },
+			key:        "key-3",
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "get error",
+			setup:      func(s *errStore) { s.getErr = errTest },
+			key:        "key",
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+		{
+			name:       "invalid record",
+			setup:      func(s *errStore) { s.getValue = []byte("{") },
+			key:        "key",
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newErrStore()
+
+			m, err := New(s)
+			require.NoError(t, err)
+
+			var (
+				calls int
+				mux   sync.Mutex
+			)
+
+			h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, testHandler(&calls, &mux, http.StatusOK))
+
+			_ = doRequest(h, http.MethodPost, "key", "alpha")
+
+			tt.setup(s)
+
+			rr := doRequest(h, http.MethodPost, tt.key, "alpha")
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestMiddleware_MiddlewareFn_lockRefresh(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore(), WithLockTTL(30*time.Millisecond))
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, next)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		rr := doRequest(h, http.MethodPost, "key", "alpha")
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}()
+
+	<-started
+
+	// the lock is still held after several lock TTL periods
+	time.Sleep(100 * time.Millisecond)
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	close(release)
+	wg.Wait()
+
+	rr = doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_MiddlewareFn_foreignLock(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryStore()
+
+	m, err := New(s)
+	require.NoError(t, err)
+
+	var key string
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// simulate the lock expiration and acquisition by another request
+		for k := range s.entries {
+			key = k
+			s.entries[k] = memoryEntry{owner: "other", value: []byte("{}"), expires: time.Now().Add(time.Minute)}
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, next)
+
+	rr := doRequest(h, http.MethodPost, "key", "alpha")
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	// the lock of the other request is not released
+	_, found, err := s.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestMiddleware_MiddlewareFn_bodyError(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(NewMemoryStore())
+	require.NoError(t, err)
+
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, http.NotFoundHandler())
+
+	r := httptest.NewRequest(http.MethodPost, "/", errReader{})
+	r.Header.Set(DefaultHeader, "key")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+type errReader struct{}
+
+func (errReader) Read(_ []byte) (int, error) {
+	return 0, errors.New("read error")
+}
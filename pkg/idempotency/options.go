@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// Option is a type alias for a function that configures the idempotency middleware.
+type Option func(m *Middleware) error
+
+// WithHeader sets the name of the request header containing the idempotency key (default DefaultHeader).
+func WithHeader(name string) Option {
+	return func(m *Middleware) error {
+		if name == "" {
+			return errors.New("the idempotency header name is required")
+		}
+
+		m.header = name
+
+		return nil
+	}
+}
+
+// WithMethods sets the HTTP methods handled by the middleware (default POST and PATCH).
+func WithMethods(methods ...string) Option {
+	return func(m *Middleware) error {
+		if len(methods) == 0 {
+			return errors.New("at least one idempotency method is required")
+		}
+
+		m.methods = methods
+
+		return nil
+	}
+}
+
+// WithTTL sets the retention time of the recorded responses (default DefaultTTL).
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Middleware) error {
+		if ttl <= 0 {
+			return errors.New("invalid idempotency TTL")
+		}
+
+		m.ttl = ttl
+
+		return nil
+	}
+}
+
+// WithLockTTL sets the expiration time of the lock held while a request is in progress (default DefaultLockTTL).
+// The lock is periodically extended while the request is in progress,
+// so this only limits the time a lock can outlive a crashed instance.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(m *Middleware) error {
+		if ttl <= 0 {
+			return errors.New("invalid idempotency lock TTL")
+		}
+
+		m.lockTTL = ttl
+
+		return nil
+	}
+}
+
+// WithMaxBodySize sets the maximum size in bytes of the response body that can be recorded (default DefaultMaxBodySize).
+// The body of larger responses is not recorded (nor buffered beyond the limit) and the retries are rejected with 409 Conflict.
+func WithMaxBodySize(size int) Option {
+	return func(m *Middleware) error {
+		if size < 0 {
+			return errors.New("invalid idempotency max body size")
+		}
+
+		m.maxBodySize = size
+
+		return nil
+	}
+}
+
+// WithMaxRequestBodySize sets the maximum size in bytes of the request body (default DefaultMaxRequestBodySize).
+// Larger requests are rejected with 413 Request Entity Too Large.
+func WithMaxRequestBodySize(size int64) Option {
+	return func(m *Middleware) error {
+		if size <= 0 {
+			return errors.New("invalid idempotency max request body size")
+		}
+
+		m.maxReqSize = size
+
+		return nil
+	}
+}
+
+// WithKeyPrefix sets the prefix of the keys in the store (default DefaultKeyPrefix).
+func WithKeyPrefix(prefix string) Option {
+	return func(m *Middleware) error {
+		m.keyPrefix = prefix
+		return nil
+	}
+}
+
+// WithRequired rejects the requests without the idempotency key header with 400 Bad Request.
+func WithRequired() Option {
+	return func(m *Middleware) error {
+		m.required = true
+		return nil
+	}
+}
+
+// WithErrorHandlerFunc sets the function used to send the error responses
+// (e.g. 409 Conflict for concurrent duplicated requests).
+func WithErrorHandlerFunc(fn ErrorHandlerFunc) Option {
+	return func(m *Middleware) error {
+		if fn == nil {
+			return errors.New("the idempotency error handler function is required")
+		}
+
+		m.errorHandler = fn
+
+		return nil
+	}
+}
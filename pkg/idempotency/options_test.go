@@ -0,0 +1,122 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHeader(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithHeader("X-Request-Key")(m)
+	require.NoError(t, err)
+	require.Equal(t, "X-Request-Key", m.header)
+
+	err = WithHeader("")(m)
+	require.Error(t, err)
+}
+
+func TestWithMethods(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithMethods(http.MethodPost, http.MethodPut)(m)
+	require.NoError(t, err)
+	require.Equal(t, []string{http.MethodPost, http.MethodPut}, m.methods)
+
+	err = WithMethods()(m)
+	require.Error(t, err)
+}
+
+func TestWithTTL(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithTTL(time.Hour)(m)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, m.ttl)
+
+	err = WithTTL(0)(m)
+	require.Error(t, err)
+}
+
+func TestWithLockTTL(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithLockTTL(time.Minute)(m)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, m.lockTTL)
+
+	err = WithLockTTL(-1)(m)
+	require.Error(t, err)
+}
+
+func TestWithMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithMaxBodySize(123)(m)
+	require.NoError(t, err)
+	require.Equal(t, 123, m.maxBodySize)
+
+	err = WithMaxBodySize(-1)(m)
+	require.Error(t, err)
+}
+
+func TestWithMaxRequestBodySize(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithMaxRequestBodySize(123)(m)
+	require.NoError(t, err)
+	require.Equal(t, int64(123), m.maxReqSize)
+
+	err = WithMaxRequestBodySize(0)(m)
+	require.Error(t, err)
+}
+
+func TestWithKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithKeyPrefix("test:")(m)
+	require.NoError(t, err)
+	require.Equal(t, "test:", m.keyPrefix)
+}
+
+func TestWithRequired(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithRequired()(m)
+	require.NoError(t, err)
+	require.True(t, m.required)
+}
+
+func TestWithErrorHandlerFunc(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+
+	err := WithErrorHandlerFunc(func(w http.ResponseWriter, _ *http.Request, statusCode int) {
+		w.WriteHeader(statusCode)
+	})(m)
+	require.NoError(t, err)
+	require.NotNil(t, m.errorHandler)
+
+	err = WithErrorHandlerFunc(nil)(m)
+	require.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	libredis "github.com/redis/go-redis/v9"
+)
+
+// The Redis and Valkey stores save the values as "<owner>:<value>",
+// so the owner can be atomically checked by the following Lua scripts.
+const (
+	// compareAndSetScript sets the value ARGV[2] with the expiration time ARGV[3] in milliseconds,
+	// only if the key KEYS[1] is held by the owner ARGV[1].
+	compareAndSetScript = `local v = redis.call("GET", KEYS[1])
+if v and string.sub(v, 1, string.len(ARGV[1]) + 1) == ARGV[1] .. ":" then
+	redis.call("SET", KEYS[1], ARGV[1] .. ":" .. ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0`
+
+	// compareAndDeleteScript deletes the key KEYS[1], only if it is held by the owner ARGV[1].
+	compareAndDeleteScript = `local v = redis.call("GET", KEYS[1])
+if v and string.sub(v, 1, string.len(ARGV[1]) + 1) == ARGV[1] .. ":" then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+)
+
+// RedisClient contains the methods of the github.com/Vonage/gosrvlib/pkg/redis Client used by the RedisStore.
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, value any, exp time.Duration) (bool, error)
+	Get(ctx context.Context, key string, value any) error
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// RedisStore is a Store based on Redis.
+type RedisStore struct {
+	client RedisClient
+}
+
+// NewRedisStore creates a new Store on top of a github.com/Vonage/gosrvlib/pkg/redis Client.
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// SetNX sets the value and owner for the key with an expiration time, only if the key does not exist.
+func (s *RedisStore) SetNX(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, ownedValue(owner, value), ttl) //nolint:wrapcheck
+}
+
+// Set sets the value for the key with a new expiration time, only if the key exists and is held by the owner.
+func (s *RedisStore) Set(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	res, err := s.client.Eval(ctx, compareAndSetScript, []string{key}, owner, string(value), scriptTTL(ttl))
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	return res == int64(1), nil
+}
+
+// Get returns the value of the key and false if the key does not exist.
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+
+	err := s.client.Get(ctx, key, &value)
+	if errors.Is(err, libredis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck
+	}
+
+	return ownerlessValue(value), true, nil
+}
+
+// Del deletes the key, only if it is held by the owner.
+func (s *RedisStore) Del(ctx context.Context, key, owner string) error {
+	_, err := s.client.Eval(ctx, compareAndDeleteScript, []string{key}, owner)
+
+	return err //nolint:wrapcheck
+}
+
+// ownedValue returns the value prefixed by the owner.
+func ownedValue(owner string, value []byte) string {
+	return owner + ":" + string(value)
+}
+
+// ownerlessValue returns the value without the owner prefix.
+func ownerlessValue(value []byte) []byte {
+	_, v, _ := bytes.Cut(value, []byte(":"))
+
+	return v
+}
+
+// scriptTTL returns the expiration time in milliseconds as a script argument.
+func scriptTTL(ttl time.Duration) string {
+	return strconv.FormatInt(max(ttl.Milliseconds(), 1), 10)
+}
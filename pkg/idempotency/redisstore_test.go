@@ -0,0 +1,139 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	libredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+// evalTestScript emulates the execution of the store Lua scripts on the data map.
+func evalTestScript(data map[string]string, script, key string, args []string) int64 {
+	v, ok := data[key]
+	if !ok || !strings.HasPrefix(v, args[0]+":") {
+		return 0
+	}
+
+	switch script {
+	case compareAndSetScript:
+		data[key] = args[0] + ":" + args[1]
+	case compareAndDeleteScript:
+		delete(data, key)
+	}
+
+	return 1
+}
+
+type testRedisClient struct {
+	data map[string]string
+	err  error
+}
+
+func (c *testRedisClient) SetNX(_ context.Context, key string, value any, _ time.Duration) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+
+	if _, ok := c.data[key]; ok {
+		return false, nil
+	}
+
+	c.data[key] = value.(string) //nolint:forcetypeassert
+
+	return true, nil
+}
+
+func (c *testRedisClient) Get(_ context.Context, key string, value any) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	v, ok := c.data[key]
+	if !ok {
+		return fmt.Errorf("cannot retrieve key %s: %w", key, libredis.Nil)
+	}
+
+	*(value.(*[]byte)) = []byte(v) //nolint:forcetypeassert
+
+	return nil
+}
+
+func (c *testRedisClient) Eval(_ context.Context, script string, keys []string, args ...any) (any, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	sargs := make([]string, 0, len(args))
+
+	for _, a := range args {
+		sargs = append(sargs, a.(string)) //nolint:forcetypeassert
+	}
+
+	return evalTestScript(c.data, script, keys[0], sargs), nil
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &testRedisClient{data: make(map[string]string)}
+	s := NewRedisStore(c)
+
+	ok, err := s.SetNX(ctx, "k", "owner1", []byte("v1"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "owner1:v1", c.data["k"])
+
+	ok, err = s.SetNX(ctx, "k", "owner2", []byte("v2"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = s.Set(ctx, "k", "owner2", []byte("v2"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = s.Set(ctx, "k", "owner1", []byte("v3:x"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	v, found, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("v3:x"), v)
+
+	err = s.Del(ctx, "k", "owner2")
+	require.NoError(t, err)
+	require.Contains(t, c.data, "k")
+
+	err = s.Del(ctx, "k", "owner1")
+	require.NoError(t, err)
+
+	_, found, err = s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	c.err = errors.New("error")
+
+	_, found, err = s.Get(ctx, "k")
+	require.Error(t, err)
+	require.False(t, found)
+
+	ok, err = s.Set(ctx, "k", "owner1", []byte("v"), time.Minute)
+	require.Error(t, err)
+	require.False(t, ok)
+
+	err = s.Del(ctx, "k", "owner1")
+	require.Error(t, err)
+}
+
+func Test_scriptTTL(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "1500", scriptTTL(1500*time.Millisecond))
+	require.Equal(t, "1", scriptTTL(time.Microsecond))
+}
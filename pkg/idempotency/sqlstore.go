@@ -0,0 +1,204 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSQLTable is the default name of the SQL table used by the SQLStore.
+const DefaultSQLTable = "idempotency"
+
+// SQLConn contains the methods of the github.com/Vonage/gosrvlib/pkg/sqlconn SQLConn used by the SQLStore.
+type SQLConn interface {
+	DB() *sql.DB
+}
+
+// SQLStoreOption is a type alias for a function that configures the SQLStore.
+type SQLStoreOption func(s *SQLStore)
+
+// UniqueViolationFn is the type of function used to check if a SQL error is a unique constraint violation.
+type UniqueViolationFn func(err error) bool
+
+// WithSQLTable sets the name of the SQL table (default DefaultSQLTable).
+func WithSQLTable(table string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.table = table
+	}
+}
+
+// WithSQLDollarPlaceholders uses the $1, $2, ... query placeholders (e.g. for PostgreSQL) instead of "?".
+func WithSQLDollarPlaceholders() SQLStoreOption {
+	return func(s *SQLStore) {
+		s.dollar = true
+	}
+}
+
+// WithSQLUniqueViolationFn sets the function used to detect the unique constraint violations
+// of the database driver (default DefaultUniqueViolation).
+func WithSQLUniqueViolationFn(fn UniqueViolationFn) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.isUniqueViolation = fn
+	}
+}
+
+// DefaultUniqueViolation returns true if the error is a unique constraint violation.
+// It checks the SQLSTATE code of the drivers exposing the SQLState() method (e.g. PostgreSQL),
+// and the error messages of the most common databases (MySQL, PostgreSQL, SQLite, SQL Server, Oracle).
+func DefaultUniqueViolation(err error) bool {
+	var se interface{ SQLState() string }
+	if errors.As(err, &se) {
+		switch se.SQLState() {
+		case "23505", "23000":
+			return true
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	return strings.Contains(msg, "duplicate entry") ||
+		strings.Contains(msg, "duplicate key") ||
+		strings.Contains(msg, "unique constraint")
+}
+
+// SQLStore is a Store based on a SQL database.
+//
+// The table must be created in advance, for example (MySQL):
+//
+//	CREATE TABLE idempotency (
+//	  idempotency_key VARCHAR(255) NOT NULL PRIMARY KEY,
+//	  owner           VARCHAR(64) NOT NULL,
+//	  data            BLOB NOT NULL,
+//	  expires_at      BIGINT NOT NULL
+//	);
+//
+// The owner column contains the token of the request holding the key.
+// The expires_at column contains the expiration time in Unix milliseconds.
+// The expired rows are ignored and replaced when the same key is used again.
+type SQLStore struct {
+	conn              SQLConn
+	table             string
+	dollar            bool
+	isUniqueViolation UniqueViolationFn
+}
+
+// NewSQLStore creates a new Store on top of a github.com/Vonage/gosrvlib/pkg/sqlconn SQLConn.
+func NewSQLStore(conn SQLConn, opts ...SQLStoreOption) *SQLStore {
+	s := &SQLStore{
+		conn:              conn,
+		table:             DefaultSQLTable,
+		isUniqueViolation: DefaultUniqueViolation,
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(s)
+	}
+
+	return s
+}
+
+// SetNX sets the value and owner for the key with an expiration time, only if the key does not exist.
+func (s *SQLStore) SetNX(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	db := s.conn.DB()
+
+	_, err := db.ExecContext(ctx, s.query("DELETE FROM %s WHERE idempotency_key = ? AND expires_at <= ?"), key, now.UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("cannot delete expired key %s: %w", key, err)
+	}
+
+	_, err = db.ExecContext(ctx, s.query("INSERT INTO %s (idempotency_key, owner, data, expires_at) VALUES (?, ?, ?, ?)"), key, owner, value, now.Add(ttl).UnixMilli())
+	if err == nil {
+		return true, nil
+	}
+
+	// the insert fails with a unique constraint violation if the key already exists
+	if s.isUniqueViolation(err) {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("cannot set key %s: %w", key, err)
+}
+
+// Set sets the value for the key with a new expiration time, only if the key exists and is held by the owner.
+func (s *SQLStore) Set(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	res, err := s.conn.DB().ExecContext(
+		ctx,
+		s.query("UPDATE %s SET data = ?, expires_at = ? WHERE idempotency_key = ? AND owner = ? AND expires_at > ?"),
+		value,
+		now.Add(ttl).UnixMilli(),
+		key,
+		owner,
+		now.UnixMilli(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("cannot set key %s: %w", key, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cannot set key %s: %w", key, err)
+	}
+
+	return n > 0, nil
+}
+
+// Get returns the value of the key and false if the key does not exist.
+func (s *SQLStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+
+	row := s.conn.DB().QueryRowContext(ctx, s.query("SELECT data FROM %s WHERE idempotency_key = ? AND expires_at > ?"), key, time.Now().UnixMilli())
+
+	err := row.Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot retrieve key %s: %w", key, err)
+	}
+
+	return value, true, nil
+}
+
+// Del deletes the key, only if it is held by the owner.
+func (s *SQLStore) Del(ctx context.Context, key, owner string) error {
+	_, err := s.conn.DB().ExecContext(ctx, s.query("DELETE FROM %s WHERE idempotency_key = ? AND owner = ?"), key, owner)
+	if err != nil {
+		return fmt.Errorf("cannot delete key %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// query returns the SQL query for the configured table and placeholders style.
+func (s *SQLStore) query(format string) string {
+	q := fmt.Sprintf(format, s.table)
+
+	if !s.dollar {
+		return q
+	}
+
+	var b strings.Builder
+
+	n := 0
+
+	for _, c := range q {
+		if c == '?' {
+			n++
+
+			fmt.Fprintf(&b, "$%d", n)
+
+			continue
+		}
+
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,256 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+type testSQLConn struct {
+	db *sql.DB
+}
+
+func (c *testSQLConn) DB() *sql.DB {
+	return c.db
+}
+
+func newTestSQLStore(t *testing.T, opts ...SQLStoreOption) (*SQLStore, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return NewSQLStore(&testSQLConn{db: db}, opts...), mock
+}
+
+func TestNewSQLStore(t *testing.T) {
+	t.Parallel()
+
+	s := NewSQLStore(&testSQLConn{})
+	require.Equal(t, DefaultSQLTable, s.table)
+	require.False(t, s.dollar)
+	require.NotNil(t, s.isUniqueViolation)
+
+	fn := func(_ error) bool { return true }
+
+	s = NewSQLStore(&testSQLConn{}, WithSQLTable("idem"), WithSQLDollarPlaceholders(), WithSQLUniqueViolationFn(fn))
+	require.Equal(t, "idem", s.table)
+	require.True(t, s.dollar)
+	require.True(t, s.isUniqueViolation(errors.New("error")))
+}
+
+type testSQLStateError struct {
+	state string
+}
+
+func (e *testSQLStateError) Error() string {
+	return "sql error"
+}
+
+func (e *testSQLStateError) SQLState() string {
+	return e.state
+}
+
+func TestDefaultUniqueViolation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "sqlstate", err: fmt.Errorf("wrapped: %w", &testSQLStateError{state: "23505"}), want: true},
+		{name: "other sqlstate", err: &testSQLStateError{state: "08006"}, want: false},
+		{name: "mysql", err: errors.New("Error 1062 (23000): Duplicate entry 'k' for key 'PRIMARY'"), want: true},
+		{name: "postgresql", err: errors.New(`ERROR: duplicate key value violates unique constraint "idempotency_pkey"`), want: true},
+		{name: "sqlite", err: errors.New("UNIQUE constraint failed: idempotency.idempotency_key"), want: true},
+		{name: "other error", err: errors.New("connection refused"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, DefaultUniqueViolation(tt.err))
+		})
+	}
+}
+
+func TestSQLStore_query(t *testing.T) {
+	t.Parallel()
+
+	s := NewSQLStore(&testSQLConn{})
+	require.Equal(t, "DELETE FROM idempotency WHERE idempotency_key = ? AND expires_at <= ?", s.query("DELETE FROM %s WHERE idempotency_key = ? AND expires_at <= ?"))
+
+	s = NewSQLStore(&testSQLConn{}, WithSQLTable("idem"), WithSQLDollarPlaceholders())
+	require.Equal(t, "DELETE FROM idem WHERE idempotency_key = $1 AND expires_at <= $2", s.query("DELETE FROM %s WHERE idempotency_key = ? AND expires_at <= ?"))
+}
+
+func TestSQLStore_SetNX(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("db error")
+	errDuplicate := errors.New("Error 1062 (23000): Duplicate entry 'k' for key 'PRIMARY'")
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM idempotency").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO idempotency").WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantOK: true,
+		},
+		{
+			name: "existing key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM idempotency").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO idempotency").WillReturnError(errDuplicate)
+			},
+			wantOK: false,
+		},
+		{
+			name: "insert error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM idempotency").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO idempotency").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+		{
+			name: "delete error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM idempotency").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := newTestSQLStore(t)
+			tt.setup(mock)
+
+			ok, err := s.SetNX(context.Background(), "k", "owner", []byte("v"), time.Minute)
+			require.Equal(t, tt.wantErr, err != nil, "error = %v", err)
+			require.Equal(t, tt.wantOK, ok)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStore_Set(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("db error")
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name: "owned key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE idempotency .* WHERE idempotency_key = \\? AND owner = \\? AND expires_at > \\?").
+					WithArgs([]byte("v"), sqlmock.AnyArg(), "k", "owner", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOK: true,
+		},
+		{
+			name: "not owned or expired key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE idempotency").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantOK: false,
+		},
+		{
+			name: "update error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE idempotency").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+		{
+			name: "rows affected error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE idempotency").WillReturnResult(sqlmock.NewErrorResult(errTest))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, mock := newTestSQLStore(t)
+			tt.setup(mock)
+
+			ok, err := s.Set(context.Background(), "k", "owner", []byte("v"), time.Minute)
+			require.Equal(t, tt.wantErr, err != nil, "error = %v", err)
+			require.Equal(t, tt.wantOK, ok)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLStore_Get(t *testing.T) {
+	t.Parallel()
+
+	s, mock := newTestSQLStore(t)
+
+	mock.ExpectQuery("SELECT data FROM idempotency").WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow([]byte("v")))
+	mock.ExpectQuery("SELECT data FROM idempotency").WillReturnRows(sqlmock.NewRows([]string{"data"}))
+	mock.ExpectQuery("SELECT data FROM idempotency").WillReturnError(errors.New("db error"))
+
+	v, found, err := s.Get(context.Background(), "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("v"), v)
+
+	v, found, err = s.Get(context.Background(), "k")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Nil(t, v)
+
+	_, found, err = s.Get(context.Background(), "k")
+	require.Error(t, err)
+	require.False(t, found)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStore_Del(t *testing.T) {
+	t.Parallel()
+
+	s, mock := newTestSQLStore(t)
+
+	mock.ExpectExec("DELETE FROM idempotency WHERE idempotency_key = \\? AND owner = \\?").WithArgs("k", "owner").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM idempotency").WithArgs("k", "owner").WillReturnError(errors.New("db error"))
+
+	err := s.Del(context.Background(), "k", "owner")
+	require.NoError(t, err)
+
+	err = s.Del(context.Background(), "k", "owner")
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStoreCleanupInterval is the minimum interval between the removal of the expired entries.
+const memoryStoreCleanupInterval = 1 * time.Minute
+
+// Store is the interface for the idempotency records storage.
+// Each key is owned by the request that created it, identified by a unique owner token,
+// so a request can't overwrite or delete a key acquired by another request after its lock expired.
+type Store interface {
+	// SetNX sets the value and owner for the key with an expiration time, only if the key does not exist.
+	// Returns true if the key has been set.
+	SetNX(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error)
+
+	// Set sets the value for the key with a new expiration time, only if the key exists and is held by the owner.
+	// Returns true if the key has been set.
+	Set(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error)
+
+	// Get returns the value of the key and false if the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Del deletes the key, only if it is held by the owner.
+	Del(ctx context.Context, key, owner string) error
+}
+
+type memoryEntry struct {
+	owner   string
+	value   []byte
+	expires time.Time
+}
+
+// MemoryStore is a local in-memory Store.
+// It is only suitable for a single service instance or for testing.
+type MemoryStore struct {
+	mu          sync.Mutex
+	entries     map[string]memoryEntry
+	lastCleanup time.Time
+}
+
+// NewMemoryStore creates a new local in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:     make(map[string]memoryEntry),
+		lastCleanup: time.Now(),
+	}
+}
+
+// SetNX sets the value and owner for the key with an expiration time, only if the key does not exist.
+func (s *MemoryStore) SetNX(_ context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	s.cleanup(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return false, nil
+	}
+
+	s.entries[key] = memoryEntry{owner: owner, value: value, expires: now.Add(ttl)}
+
+	return true, nil
+}
+
+// Set sets the value for the key with a new expiration time, only if the key exists and is held by the owner.
+func (s *MemoryStore) Set(_ context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	e, ok := s.entries[key]
+	if !ok || e.owner != owner || !now.Before(e.expires) {
+		return false, nil
+	}
+
+	s.entries[key] = memoryEntry{owner: owner, value: value, expires: now.Add(ttl)}
+
+	return true, nil
+}
+
+// Get returns the value of the key and false if the key does not exist.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, false, nil
+	}
+
+	return e.value, true, nil
+}
+
+// Del deletes the key, only if it is held by the owner.
+func (s *MemoryStore) Del(_ context.Context, key, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.owner == owner {
+		delete(s.entries, key)
+	}
+
+	return nil
+}
+
+// cleanup removes the expired entries.
+// It must be called with the mutex held.
+func (s *MemoryStore) cleanup(now time.Time) {
+	if now.Sub(s.lastCleanup) < memoryStoreCleanupInterval {
+		return
+	}
+
+	s.lastCleanup = now
+
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+}
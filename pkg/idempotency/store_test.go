@@ -0,0 +1,98 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	ok, err := s.SetNX(ctx, "k", "owner1", []byte("v1"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = s.SetNX(ctx, "k", "owner2", []byte("v2"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	v, found, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("v1"), v)
+
+	ok, err = s.Set(ctx, "k", "owner2", []byte("v2"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = s.Set(ctx, "k", "owner1", []byte("v3"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	v, found, err = s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("v3"), v)
+
+	err = s.Del(ctx, "k", "owner2")
+	require.NoError(t, err)
+
+	_, found, err = s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	err = s.Del(ctx, "k", "owner1")
+	require.NoError(t, err)
+
+	v, found, err = s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Nil(t, v)
+
+	ok, err = s.Set(ctx, "k", "owner1", []byte("v4"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestMemoryStore_expiration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	ok, err := s.SetNX(ctx, "expired", "owner1", []byte("v"), -time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, found, err := s.Get(ctx, "expired")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// the lock can't be extended after expiration
+	ok, err = s.Set(ctx, "expired", "owner1", []byte("v"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = s.SetNX(ctx, "expired", "owner2", []byte("v"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = s.SetNX(ctx, "other", "owner1", []byte("v"), -time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// force the cleanup of the expired entries
+	s.lastCleanup = time.Now().Add(-2 * memoryStoreCleanupInterval)
+
+	ok, err = s.SetNX(ctx, "new", "owner1", []byte("v"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, s.entries, 2)
+	require.NotContains(t, s.entries, "other")
+}
@@ -0,0 +1,62 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+// ValkeyClient contains the methods of the github.com/Vonage/gosrvlib/pkg/valkey Client used by the ValkeyStore.
+type ValkeyClient interface {
+	SetNX(ctx context.Context, key string, value string, exp time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	Eval(ctx context.Context, script string, keys []string, args ...string) (any, error)
+}
+
+// ValkeyStore is a Store based on Valkey.
+type ValkeyStore struct {
+	client ValkeyClient
+}
+
+// NewValkeyStore creates a new Store on top of a github.com/Vonage/gosrvlib/pkg/valkey Client.
+func NewValkeyStore(client ValkeyClient) *ValkeyStore {
+	return &ValkeyStore{client: client}
+}
+
+// SetNX sets the value and owner for the key with an expiration time, only if the key does not exist.
+func (s *ValkeyStore) SetNX(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, ownedValue(owner, value), ttl) //nolint:wrapcheck
+}
+
+// Set sets the value for the key with a new expiration time, only if the key exists and is held by the owner.
+func (s *ValkeyStore) Set(ctx context.Context, key, owner string, value []byte, ttl time.Duration) (bool, error) {
+	res, err := s.client.Eval(ctx, compareAndSetScript, []string{key}, owner, string(value), scriptTTL(ttl))
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	return res == int64(1), nil
+}
+
+// Get returns the value of the key and false if the key does not exist.
+func (s *ValkeyStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, key)
+	if errors.Is(err, libvalkey.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck
+	}
+
+	return ownerlessValue([]byte(value)), true, nil
+}
+
+// Del deletes the key, only if it is held by the owner.
+func (s *ValkeyStore) Del(ctx context.Context, key, owner string) error {
+	_, err := s.client.Eval(ctx, compareAndDeleteScript, []string{key}, owner)
+
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,106 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+type testValkeyClient struct {
+	data map[string]string
+	err  error
+}
+
+func (c *testValkeyClient) SetNX(_ context.Context, key string, value string, _ time.Duration) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+
+	if _, ok := c.data[key]; ok {
+		return false, nil
+	}
+
+	c.data[key] = value
+
+	return true, nil
+}
+
+func (c *testValkeyClient) Get(_ context.Context, key string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	v, ok := c.data[key]
+	if !ok {
+		return "", fmt.Errorf("cannot retrieve key %s: %w", key, libvalkey.Nil)
+	}
+
+	return v, nil
+}
+
+func (c *testValkeyClient) Eval(_ context.Context, script string, keys []string, args ...string) (any, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return evalTestScript(c.data, script, keys[0], args), nil
+}
+
+func TestValkeyStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &testValkeyClient{data: make(map[string]string)}
+	s := NewValkeyStore(c)
+
+	ok, err := s.SetNX(ctx, "k", "owner1", []byte("v1"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "owner1:v1", c.data["k"])
+
+	ok, err = s.SetNX(ctx, "k", "owner2", []byte("v2"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = s.Set(ctx, "k", "owner2", []byte("v2"), time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = s.Set(ctx, "k", "owner1", []byte("v3"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	v, found, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("v3"), v)
+
+	err = s.Del(ctx, "k", "owner2")
+	require.NoError(t, err)
+	require.Contains(t, c.data, "k")
+
+	err = s.Del(ctx, "k", "owner1")
+	require.NoError(t, err)
+
+	_, found, err = s.Get(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	c.err = errors.New("error")
+
+	_, found, err = s.Get(ctx, "k")
+	require.Error(t, err)
+	require.False(t, found)
+
+	ok, err = s.Set(ctx, "k", "owner1", []byte("v"), time.Minute)
+	require.Error(t, err)
+	require.False(t, ok)
+
+	err = s.Del(ctx, "k", "owner1")
+	require.Error(t, err)
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/encode"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 )
 
@@ -28,6 +32,7 @@ type Consumer struct {
 	client  consumerClient
 	checkFn func(ctx context.Context, address string) error
 	brokers []string
+	topic   string
 }
 
 // NewConsumer creates a new instance of Consumer.
@@ -68,6 +73,7 @@ func NewConsumer(brokers []string, topic, groupID string, opts ...Option) (*Cons
 		client:  client,
 		checkFn: checkFn,
 		brokers: brokers,
+		topic:   topic,
 	}, nil
 }
 
@@ -83,12 +89,31 @@ func (c *Consumer) Close() error {
 
 // Receive reads one message from the Kafka; blocks if there are no messages in the queue.
 func (c *Consumer) Receive(ctx context.Context) ([]byte, error) {
+	_, msg, err := c.ReceiveContext(ctx)
+	return msg, err
+}
+
+// ReceiveContext reads one message from the Kafka; blocks if there are no messages in the queue.
+// The returned context contains the consumer span, child of the trace context propagated with the message headers,
+// and a logger tagged with the trace and span IDs (see the tracing package).
+func (c *Consumer) ReceiveContext(ctx context.Context) (context.Context, []byte, error) {
+	start := time.Now()
+
 	msg, err := c.client.ReadMessage(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read a message from Kafka: %w", err)
+		return ctx, nil, fmt.Errorf("failed to read a message from Kafka: %w", err)
 	}
 
-	return msg.Value, nil
+	ctx, span := tracing.Start(
+		tracing.Extract(ctx, headerCarrier{headers: &msg.Headers}),
+		c.topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(spanAttributes(c.topic)...),
+	)
+	span.End()
+
+	return logging.WithSpanContext(ctx), msg.Value, nil
 }
 
 // HealthCheck checks if the consumer is working.
@@ -115,10 +140,17 @@ func DefaultMessageDecodeFunc(_ context.Context, msg []byte, data any) error {
 
 // ReceiveData retrieves a message from the queue and extract its content in the data.
 func (c *Consumer) ReceiveData(ctx context.Context, data any) error {
-	message, err := c.Receive(ctx)
+	_, err := c.ReceiveDataContext(ctx, data)
+	return err
+}
+
+// ReceiveDataContext retrieves a message from the queue and extract its content in the data.
+// The returned context contains the trace context propagated with the message (see ReceiveContext).
+func (c *Consumer) ReceiveDataContext(ctx context.Context, data any) (context.Context, error) {
+	ctx, message, err := c.ReceiveContext(ctx)
 	if err != nil {
-		return err
+		return ctx, err
 	}
 
-	return c.cfg.messageDecodeFunc(ctx, message, data)
+	return ctx, c.cfg.messageDecodeFunc(ctx, message, data)
 }
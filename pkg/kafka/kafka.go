@@ -8,5 +8,9 @@ topics.
 
 It allows to specify custom message encoding and decoding functions, including
 serialization and encryption.
+
+The messages are traced with the OpenTelemetry producer and consumer spans, and
+the W3C trace context is propagated with the message headers (see the
+github.com/Vonage/gosrvlib/pkg/tracing package).
 */
 package kafka
@@ -6,7 +6,9 @@ import (
 	"fmt"
 
 	"github.com/Vonage/gosrvlib/pkg/encode"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TEncodeFunc is the type of function used to replace the default message encoding function used by SendData().
@@ -21,6 +23,7 @@ type producerClient interface {
 type Producer struct {
 	cfg    *config
 	client producerClient
+	topic  string
 }
 
 // NewProducer creates a new instance of Producer.
@@ -44,6 +47,7 @@ func NewProducer(urls []string, topic string, opts ...Option) (*Producer, error)
 	return &Producer{
 		cfg:    cfg,
 		client: producer,
+		topic:  topic,
 	}, nil
 }
 
@@ -58,18 +62,29 @@ func (p *Producer) Close() error {
 }
 
 // Send sends a message to Kafka topic.
+// The W3C trace context is propagated with the message headers (see the tracing package).
 func (p *Producer) Send(ctx context.Context, msg []byte) error {
-	err := p.client.WriteMessages(
+	ctx, span := tracing.Start(
 		ctx,
-		kafka.Message{
-			Value: msg,
-		},
+		p.topic+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(spanAttributes(p.topic)...),
 	)
+
+	message := kafka.Message{
+		Value: msg,
+	}
+
+	tracing.Inject(ctx, headerCarrier{headers: &message.Headers})
+
+	err := p.client.WriteMessages(ctx, message)
 	if err != nil {
-		return fmt.Errorf("failed to send a message to Kafka: %w", err)
+		err = fmt.Errorf("failed to send a message to Kafka: %w", err)
 	}
 
-	return nil
+	tracing.End(span, err)
+
+	return err
 }
 
 // DefaultMessageEncodeFunc is the default function to encode the input data for SendData().
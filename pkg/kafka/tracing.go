@@ -0,0 +1,57 @@
+package kafka
+
+import (
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// messagingSystem is the messaging system name reported in the tracing spans.
+const messagingSystem = "kafka"
+
+// headerCarrier adapts the Kafka message headers to the propagation.TextMapCarrier interface,
+// to propagate the trace context with the messages.
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+// Get returns the value of the first header with the specified key.
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set sets the value of the header with the specified key, replacing the existing one.
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of all the headers.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+
+	for _, h := range *c.headers {
+		keys = append(keys, h.Key)
+	}
+
+	return keys
+}
+
+// spanAttributes returns the messaging attributes of the tracing spans.
+func spanAttributes(topic string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("messaging.system", messagingSystem),
+		attribute.String("messaging.destination.name", topic),
+	}
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_headerCarrier(t *testing.T) {
+	t.Parallel()
+
+	headers := []kafka.Header{{Key: "alpha", Value: []byte("1")}}
+	c := headerCarrier{headers: &headers}
+
+	require.Equal(t, "1", c.Get("alpha"))
+	require.Empty(t, c.Get("beta"))
+
+	c.Set("alpha", "2")
+	c.Set("beta", "3")
+
+	require.Equal(t, "2", c.Get("alpha"))
+	require.Equal(t, "3", c.Get("beta"))
+	require.Equal(t, []string{"alpha", "beta"}, c.Keys())
+	require.Len(t, headers, 2)
+}
+
+func Test_traceContextPropagation(t *testing.T) {
+	t.Parallel()
+
+	var sent kafka.Message
+
+	producer, err := NewProducer([]string{"url"}, "topic")
+	require.NoError(t, err)
+
+	producer.client = produceMock{
+		writeMessages: func(_ context.Context, msg ...kafka.Message) error {
+			sent = msg[0]
+			return nil
+		},
+		close: func() error { return nil },
+	}
+
+	consumer, err := NewConsumer([]string{"url"}, "topic", "group")
+	require.NoError(t, err)
+
+	consumer.client = consumerMock{
+		readMessage: func(_ context.Context) (kafka.Message, error) { return sent, nil },
+		close:       func() error { return nil },
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	err = producer.Send(trace.ContextWithSpanContext(t.Context(), sc), []byte("test"))
+	require.NoError(t, err)
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", headerCarrier{headers: &sent.Headers}.Get("traceparent"))
+
+	ctx, msg, err := consumer.ReceiveContext(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, []byte("test"), msg)
+	require.Equal(t, sc.TraceID(), trace.SpanContextFromContext(ctx).TraceID())
+}
@@ -10,6 +10,7 @@ It includes the following features:
   - Default logger configuration with program name, version, and release.
   - Custom logger configuration with additional fields.
   - Context-based logging with component and method tags.
+  - Context-based logging with OpenTelemetry trace and span IDs.
   - Log level function hook for incrementing log metrics.
   - Log sync function to flush the logger and ignore the error.
   - Log close function to close an object and log an error in case of failure.
@@ -31,10 +32,19 @@ import (
 	"io"
 	"os"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+const (
+	// TraceIDLogKey is the log field key for the OpenTelemetry trace ID.
+	TraceIDLogKey = "trace_id"
+
+	// SpanIDLogKey is the log field key for the OpenTelemetry span ID.
+	SpanIDLogKey = "span_id"
+)
+
 // LogFatal calls the default fatal logger.
 //
 //nolint:gochecknoglobals
@@ -178,6 +188,23 @@ func WithLogger(ctx context.Context, l *zap.Logger) context.Context {
 	return context.WithValue(ctx, ctxKey{}, l)
 }
 
+// WithSpanContext returns a new context with a child logger tagged with the
+// OpenTelemetry trace and span IDs of the span in the context.
+// The context is returned unchanged if it does not contain a valid span.
+func WithSpanContext(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	l := FromContext(ctx).With(
+		zap.String(TraceIDLogKey, sc.TraceID().String()),
+		zap.String(SpanIDLogKey, sc.SpanID().String()),
+	)
+
+	return WithLogger(ctx, l)
+}
+
 // WithLevelFunctionHook registers a function with a level string argument
 // which will be called each time the Logger writes out an Entry.
 func WithLevelFunctionHook(l *zap.Logger, fn IncrementLogMetricsFunc) *zap.Logger {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -172,6 +173,33 @@ func TestWithLogger(t *testing.T) {
 	require.NotEqual(t, ctx3, ctx4)
 }
 
+func TestWithSpanContext(t *testing.T) {
+	t.Parallel()
+
+	// context without span
+	ctx := WithLogger(t.Context(), zap.NewNop())
+	require.Equal(t, ctx, WithSpanContext(ctx))
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx = WithLogger(t.Context(), zap.New(core))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	ctx = WithSpanContext(trace.ContextWithSpanContext(ctx, sc))
+
+	FromContext(ctx).Info("test")
+
+	require.Equal(t, 1, logs.Len())
+
+	fields := logs.All()[0].ContextMap()
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields[TraceIDLogKey])
+	require.Equal(t, "00f067aa0ba902b7", fields[SpanIDLogKey])
+}
+
 func TestFromContext(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,62 @@
+package metrics
+
+// Counter is a custom metric that can only increase.
+type Counter interface {
+	// Inc increments the counter by 1.
+	Inc(labelValues ...string)
+
+	// Add adds the specified non-negative value to the counter.
+	Add(value float64, labelValues ...string)
+}
+
+// Gauge is a custom metric that can arbitrarily go up and down.
+type Gauge interface {
+	// Set sets the gauge to the specified value.
+	Set(value float64, labelValues ...string)
+
+	// Add adds the specified value (positive or negative) to the gauge.
+	Add(value float64, labelValues ...string)
+}
+
+// Histogram is a custom metric that counts the observations in configurable buckets.
+type Histogram interface {
+	// Observe adds a single observation to the histogram.
+	Observe(value float64, labelValues ...string)
+}
+
+// Summary is a custom metric that calculates the quantiles of the observations.
+type Summary interface {
+	// Observe adds a single observation to the summary.
+	Observe(value float64, labelValues ...string)
+}
+
+// Counter returns a no-op counter.
+func (c *Default) Counter(_, _ string, _ ...string) (Counter, error) {
+	return noopMetric{}, nil
+}
+
+// Gauge returns a no-op gauge.
+func (c *Default) Gauge(_, _ string, _ ...string) (Gauge, error) {
+	return noopMetric{}, nil
+}
+
+// Histogram returns a no-op histogram.
+func (c *Default) Histogram(_, _ string, _ []float64, _ ...string) (Histogram, error) {
+	return noopMetric{}, nil
+}
+
+// Summary returns a no-op summary.
+func (c *Default) Summary(_, _ string, _ map[float64]float64, _ ...string) (Summary, error) {
+	return noopMetric{}, nil
+}
+
+// noopMetric is the no-op implementation of all the custom metrics.
+type noopMetric struct{}
+
+func (noopMetric) Inc(_ ...string) {}
+
+func (noopMetric) Add(_ float64, _ ...string) {}
+
+func (noopMetric) Set(_ float64, _ ...string) {}
+
+func (noopMetric) Observe(_ float64, _ ...string) {}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault_CustomMetrics(t *testing.T) {
+	t.Parallel()
+
+	c := &Default{}
+
+	counter, err := c.Counter("counter", "help", "label")
+	require.NoError(t, err)
+	require.NotNil(t, counter)
+
+	counter.Inc("x")
+	counter.Add(2, "x")
+
+	gauge, err := c.Gauge("gauge", "help", "label")
+	require.NoError(t, err)
+	require.NotNil(t, gauge)
+
+	gauge.Set(1, "x")
+	gauge.Add(-1, "x")
+
+	histogram, err := c.Histogram("histogram", "help", nil, "label")
+	require.NoError(t, err)
+	require.NotNil(t, histogram)
+
+	histogram.Observe(1, "x")
+
+	summary, err := c.Summary("summary", "help", nil, "label")
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+
+	summary.Observe(1, "x")
+}
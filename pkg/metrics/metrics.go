@@ -2,9 +2,19 @@
 Package metrics defines a common interface for instrumenting applications and
 components to collect metrics.
 
+Besides the default HTTP, database, log and error metrics, the Client can
+register custom business metrics (Counter, Gauge, Histogram and Summary) with
+labels, independently of the backend. The label values are passed to the
+metric methods in the same order of the label names used to register the
+metric; the values with a different number of labels are discarded.
+
+The Multi client (NewMulti) forwards the metrics to several underlying clients,
+e.g. to publish the same metrics to StatsD and Prometheus during a migration.
+
 See also:
   - github.com/Vonage/gosrvlib/pkg/metrics/statsd
   - github.com/Vonage/gosrvlib/pkg/metrics/prometheus
+  - github.com/Vonage/gosrvlib/pkg/metrics/otel
 */
 package metrics
 
@@ -33,6 +43,20 @@ type Client interface {
 	// IncErrorCounter increments the number of errors by task, operation and error code.
 	IncErrorCounter(task, operation, code string)
 
+	// Counter registers a custom counter metric with the specified label names.
+	Counter(name, help string, labelNames ...string) (Counter, error)
+
+	// Gauge registers a custom gauge metric with the specified label names.
+	Gauge(name, help string, labelNames ...string) (Gauge, error)
+
+	// Histogram registers a custom histogram metric with the specified buckets and label names.
+	// The backend default buckets are used when the buckets are empty.
+	Histogram(name, help string, buckets []float64, labelNames ...string) (Histogram, error)
+
+	// Summary registers a custom summary metric with the specified quantile objectives
+	// (quantile: absolute error) and label names.
+	Summary(name, help string, objectives map[float64]float64, labelNames ...string) (Summary, error)
+
 	// Close method.
 	Close() error
 }
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+)
+
+// Multi is a composite Client that forwards all the metrics to several underlying clients,
+// e.g. to publish the same metrics to StatsD and Prometheus during a migration.
+type Multi struct {
+	clients []Client
+}
+
+// NewMulti returns a composite Client forwarding the metrics to all the specified clients.
+//
+// The MetricsHandlerFunc of the first client is exposed, so the clients serving
+// the metrics endpoint (e.g. Prometheus) should be listed first, before the
+// push-based ones (e.g. StatsD or OpenTelemetry).
+func NewMulti(clients ...Client) *Multi {
+	return &Multi{clients: clients}
+}
+
+// InstrumentDB wraps a sql.DB to collect metrics with all the clients.
+func (c *Multi) InstrumentDB(dbName string, db *sql.DB) error {
+	errs := make([]error, 0, len(c.clients))
+
+	for _, m := range c.clients {
+		errs = append(errs, m.InstrumentDB(dbName, db))
+	}
+
+	return errors.Join(errs...)
+}
+
+// InstrumentHandler wraps a http.Handler to collect metrics with all the clients.
+func (c *Multi) InstrumentHandler(path string, handler http.HandlerFunc) http.Handler {
+	var h http.Handler = handler
+
+	for i := len(c.clients) - 1; i >= 0; i-- {
+		h = c.clients[i].InstrumentHandler(path, h.ServeHTTP)
+	}
+
+	return h
+}
+
+// InstrumentRoundTripper is a middleware that wraps the provided http.RoundTripper to observe the request result with all the clients.
+func (c *Multi) InstrumentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	for i := len(c.clients) - 1; i >= 0; i-- {
+		next = c.clients[i].InstrumentRoundTripper(next)
+	}
+
+	return next
+}
+
+// MetricsHandlerFunc returns the http handler function of the first client to serve the metrics endpoint.
+func (c *Multi) MetricsHandlerFunc() http.HandlerFunc {
+	if len(c.clients) == 0 {
+		return (&Default{}).MetricsHandlerFunc()
+	}
+
+	return c.clients[0].MetricsHandlerFunc()
+}
+
+// IncLogLevelCounter counts the number of errors for each log severity level with all the clients.
+func (c *Multi) IncLogLevelCounter(level string) {
+	for _, m := range c.clients {
+		m.IncLogLevelCounter(level)
+	}
+}
+
+// IncErrorCounter increments the number of errors by task, operation and error code with all the clients.
+func (c *Multi) IncErrorCounter(task, operation, code string) {
+	for _, m := range c.clients {
+		m.IncErrorCounter(task, operation, code)
+	}
+}
+
+// Close closes all the clients and returns the joined errors.
+func (c *Multi) Close() error {
+	errs := make([]error, 0, len(c.clients))
+
+	for _, m := range c.clients {
+		errs = append(errs, m.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Counter registers the custom counter with all the clients.
+func (c *Multi) Counter(name, help string, labelNames ...string) (Counter, error) {
+	ms, err := multiRegister(c.clients, func(m Client) (Counter, error) {
+		return m.Counter(name, help, labelNames...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return multiCounter(ms), nil
+}
+
+// Gauge registers the custom gauge with all the clients.
+func (c *Multi) Gauge(name, help string, labelNames ...string) (Gauge, error) {
+	ms, err := multiRegister(c.clients, func(m Client) (Gauge, error) {
+		return m.Gauge(name, help, labelNames...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return multiGauge(ms), nil
+}
+
+// Histogram registers the custom histogram with all the clients.
+func (c *Multi) Histogram(name, help string, buckets []float64, labelNames ...string) (Histogram, error) {
+	ms, err := multiRegister(c.clients, func(m Client) (Histogram, error) {
+		return m.Histogram(name, help, buckets, labelNames...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return multiObserver[Histogram](ms), nil
+}
+
+// Summary registers the custom summary with all the clients.
+func (c *Multi) Summary(name, help string, objectives map[float64]float64, labelNames ...string) (Summary, error) {
+	ms, err := multiRegister(c.clients, func(m Client) (Summary, error) {
+		return m.Summary(name, help, objectives, labelNames...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return multiObserver[Summary](ms), nil
+}
+
+// multiRegister registers a custom metric with all the clients.
+func multiRegister[T any](clients []Client, register func(m Client) (T, error)) ([]T, error) {
+	ms := make([]T, 0, len(clients))
+
+	for _, m := range clients {
+		metric, err := register(m)
+		if err != nil {
+			return nil, err
+		}
+
+		ms = append(ms, metric)
+	}
+
+	return ms, nil
+}
+
+// multiCounter forwards the counter values to all the underlying counters.
+type multiCounter []Counter
+
+func (ms multiCounter) Inc(labelValues ...string) {
+	for _, m := range ms {
+		m.Inc(labelValues...)
+	}
+}
+
+func (ms multiCounter) Add(value float64, labelValues ...string) {
+	for _, m := range ms {
+		m.Add(value, labelValues...)
+	}
+}
+
+// multiGauge forwards the gauge values to all the underlying gauges.
+type multiGauge []Gauge
+
+func (ms multiGauge) Set(value float64, labelValues ...string) {
+	for _, m := range ms {
+		m.Set(value, labelValues...)
+	}
+}
+
+func (ms multiGauge) Add(value float64, labelValues ...string) {
+	for _, m := range ms {
+		m.Add(value, labelValues...)
+	}
+}
+
+// multiObserver forwards the observations to all the underlying histograms or summaries.
+type multiObserver[T interface{ Observe(float64, ...string) }] []T
+
+func (ms multiObserver[T]) Observe(value float64, labelValues ...string) {
+	for _, m := range ms {
+		m.Observe(value, labelValues...)
+	}
+}
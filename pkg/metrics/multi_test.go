@@ -0,0 +1,324 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a test Client recording the calls.
+type recorder struct {
+	name  string
+	err   error
+	mux   sync.Mutex
+	calls []string
+	order *[]string
+}
+
+func (r *recorder) record(call string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) InstrumentDB(dbName string, _ *sql.DB) error {
+	r.record("db:" + dbName)
+	return r.err
+}
+
+func (r *recorder) InstrumentHandler(path string, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.record("handler:" + path)
+
+		if r.order != nil {
+			*r.order = append(*r.order, r.name)
+		}
+
+		handler(w, req)
+	})
+}
+
+func (r *recorder) InstrumentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		r.record("roundtripper")
+		return next.RoundTrip(req)
+	})
+}
+
+func (r *recorder) MetricsHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte(r.name)) }
+}
+
+func (r *recorder) IncLogLevelCounter(level string) {
+	r.record("level:" + level)
+}
+
+func (r *recorder) IncErrorCounter(task, operation, code string) {
+	r.record("error:" + task + ":" + operation + ":" + code)
+}
+
+func (r *recorder) Counter(name, _ string, _ ...string) (Counter, error) {
+	return r.metric(name)
+}
+
+func (r *recorder) Gauge(name, _ string, _ ...string) (Gauge, error) {
+	return r.metric(name)
+}
+
+func (r *recorder) Histogram(name, _ string, _ []float64, _ ...string) (Histogram, error) {
+	return r.metric(name)
+}
+
+func (r *recorder) Summary(name, _ string, _ map[float64]float64, _ ...string) (Summary, error) {
+	return r.metric(name)
+}
+
+func (r *recorder) metric(name string) (*recorderMetric, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return &recorderMetric{r: r, name: name}, nil
+}
+
+func (r *recorder) Close() error {
+	r.record("close")
+	return r.err
+}
+
+// recorderMetric is a test custom metric recording the calls.
+type recorderMetric struct {
+	r    *recorder
+	name string
+}
+
+func (m *recorderMetric) Inc(labelValues ...string) {
+	m.r.record(m.name + ":inc:" + strings.Join(labelValues, ","))
+}
+
+func (m *recorderMetric) Add(value float64, labelValues ...string) {
+	m.r.record(m.name + ":add:" + strconv.FormatFloat(value, 'f', -1, 64) + ":" + strings.Join(labelValues, ","))
+}
+
+func (m *recorderMetric) Set(value float64, labelValues ...string) {
+	m.r.record(m.name + ":set:" + strconv.FormatFloat(value, 'f', -1, 64) + ":" + strings.Join(labelValues, ","))
+}
+
+func (m *recorderMetric) Observe(value float64, labelValues ...string) {
+	m.r.record(m.name + ":observe:" + strconv.FormatFloat(value, 'f', -1, 64) + ":" + strings.Join(labelValues, ","))
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (rt roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return rt(r)
+}
+
+func TestMulti_InstrumentDB(t *testing.T) {
+	t.Parallel()
+
+	errA := errors.New("error A")
+	a := &recorder{name: "a", err: errA}
+	b := &recorder{name: "b"}
+	c := NewMulti(a, b)
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	err = c.InstrumentDB("db_test", db)
+	require.ErrorIs(t, err, errA)
+	require.Equal(t, []string{"db:db_test"}, a.calls)
+	require.Equal(t, []string{"db:db_test"}, b.calls)
+}
+
+func TestMulti_InstrumentHandler(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+
+	a := &recorder{name: "a", order: &order}
+	b := &recorder{name: "b", order: &order}
+	c := NewMulti(a, b)
+
+	handler := c.InstrumentHandler("/test", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, []string{"a", "b"}, order)
+	require.Equal(t, []string{"handler:/test"}, a.calls)
+	require.Equal(t, []string{"handler:/test"}, b.calls)
+}
+
+func TestMulti_InstrumentRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	a := &recorder{name: "a"}
+	b := &recorder{name: "b"}
+	c := NewMulti(a, b)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Transport = c.InstrumentRoundTripper(client.Transport)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, []string{"roundtripper"}, a.calls)
+	require.Equal(t, []string{"roundtripper"}, b.calls)
+}
+
+func TestMulti_MetricsHandlerFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		clients []Client
+		want    string
+	}{
+		{
+			name: "no clients",
+			want: "OK",
+		},
+		{
+			name:    "first client",
+			clients: []Client{&recorder{name: "a"}, &recorder{name: "b"}},
+			want:    "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+			require.NoError(t, err)
+
+			NewMulti(tt.clients...).MetricsHandlerFunc()(rr, req)
+
+			require.Equal(t, tt.want, rr.Body.String())
+		})
+	}
+}
+
+func TestMulti_IncCounters(t *testing.T) {
+	t.Parallel()
+
+	a := &recorder{name: "a"}
+	b := &recorder{name: "b"}
+	c := NewMulti(a, b)
+
+	c.IncLogLevelCounter("debug")
+	c.IncErrorCounter("task", "operation", "code")
+
+	want := []string{"level:debug", "error:task:operation:code"}
+
+	require.Equal(t, want, a.calls)
+	require.Equal(t, want, b.calls)
+}
+
+func TestMulti_Close(t *testing.T) {
+	t.Parallel()
+
+	errA := errors.New("error A")
+	errB := errors.New("error B")
+	a := &recorder{name: "a", err: errA}
+	b := &recorder{name: "b", err: errB}
+	c := &recorder{name: "c"}
+
+	err := NewMulti(a, b, c).Close()
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.Equal(t, []string{"close"}, c.calls)
+
+	require.NoError(t, NewMulti().Close())
+}
+
+func TestMulti_CustomMetrics(t *testing.T) {
+	t.Parallel()
+
+	a := &recorder{name: "a"}
+	b := &recorder{name: "b"}
+	c := NewMulti(a, b)
+
+	counter, err := c.Counter("counter", "help", "label")
+	require.NoError(t, err)
+
+	counter.Inc("x")
+	counter.Add(2, "y")
+
+	gauge, err := c.Gauge("gauge", "help", "label")
+	require.NoError(t, err)
+
+	gauge.Set(3, "x")
+	gauge.Add(-1, "x")
+
+	histogram, err := c.Histogram("histogram", "help", []float64{1, 2}, "label")
+	require.NoError(t, err)
+
+	histogram.Observe(1.5, "x")
+
+	summary, err := c.Summary("summary", "help", nil, "label")
+	require.NoError(t, err)
+
+	summary.Observe(0.5, "x")
+
+	want := []string{
+		"counter:inc:x",
+		"counter:add:2:y",
+		"gauge:set:3:x",
+		"gauge:add:-1:x",
+		"histogram:observe:1.5:x",
+		"summary:observe:0.5:x",
+	}
+
+	require.Equal(t, want, a.calls)
+	require.Equal(t, want, b.calls)
+}
+
+func TestMulti_CustomMetrics_error(t *testing.T) {
+	t.Parallel()
+
+	errB := errors.New("error B")
+	c := NewMulti(&recorder{name: "a"}, &recorder{name: "b", err: errB})
+
+	counter, err := c.Counter("counter", "help")
+	require.ErrorIs(t, err, errB)
+	require.Nil(t, counter)
+
+	gauge, err := c.Gauge("gauge", "help")
+	require.ErrorIs(t, err, errB)
+	require.Nil(t, gauge)
+
+	histogram, err := c.Histogram("histogram", "help", nil)
+	require.ErrorIs(t, err, errB)
+	require.Nil(t, histogram)
+
+	summary, err := c.Summary("summary", "help", nil)
+	require.ErrorIs(t, err, errB)
+	require.Nil(t, summary)
+}
@@ -0,0 +1,417 @@
+package otel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	libhttputil "github.com/Vonage/gosrvlib/pkg/httputil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	// NameAPIRequests is the name of the instrument that counts the total inbound http requests.
+	NameAPIRequests = "api_requests_total"
+
+	// NameInFlightRequests is the name of the instrument that counts in-flight inbound http requests.
+	NameInFlightRequests = "in_flight_requests"
+
+	// NameRequestDuration is the name of the instrument that measures the inbound http request duration in seconds.
+	NameRequestDuration = "request_duration_seconds"
+
+	// NameRequestSize is the name of the instrument that measures the http request size in bytes.
+	NameRequestSize = "request_size_bytes"
+
+	// NameResponseSize is the name of the instrument that measures the http response size in bytes.
+	NameResponseSize = "response_size_bytes"
+
+	// NameOutboundRequests is the name of the instrument that measures the number of outbound requests.
+	NameOutboundRequests = "outbound_requests_total"
+
+	// NameOutboundRequestsDuration is the name of the instrument that measures the outbound requests duration in seconds.
+	NameOutboundRequestsDuration = "outbound_request_duration_seconds"
+
+	// NameOutboundInFlightRequests is the name of the instrument that counts in-flight outbound http requests.
+	NameOutboundInFlightRequests = "outbound_in_flight_requests"
+
+	// NameErrorLevel is the name of the instrument that counts the number of errors for each log severity level.
+	NameErrorLevel = "error_level_total"
+
+	// NameErrorCode is the name of the instrument that counts the number of errors by task, operation and error code.
+	NameErrorCode = "error_code_total"
+
+	// NameDBPrefix is the name prefix of the instruments that observe the SQL database statistics.
+	NameDBPrefix = "go_sql_stats_connections_"
+
+	// DefaultServiceName is the default service name reported with the metrics.
+	DefaultServiceName = "gosrvlib"
+
+	// DefaultExportInterval is the default interval between two exports to the collector.
+	DefaultExportInterval = 15 * time.Second
+
+	// meterName is the name of the OpenTelemetry meter.
+	meterName = "github.com/Vonage/gosrvlib/pkg/metrics/otel"
+
+	labelCode      = "code"
+	labelDBName    = "db_name"
+	labelHandler   = "handler"
+	labelLevel     = "level"
+	labelMethod    = "method"
+	labelOperation = "operation"
+	labelTask      = "task"
+)
+
+// Client represents the state type of this client.
+type Client struct {
+	provider                       *sdkmetric.MeterProvider
+	meter                          metric.Meter
+	reader                         sdkmetric.Reader
+	otlpOpts                       []otlpmetrichttp.Option
+	exportInterval                 time.Duration
+	serviceName                    string
+	serviceVersion                 string
+	inboundRequestSizeBuckets      []float64
+	inboundResponseSizeBuckets     []float64
+	inboundRequestDurationBuckets  []float64
+	outboundRequestDurationBuckets []float64
+	inFlightRequests               metric.Int64UpDownCounter
+	apiRequests                    metric.Int64Counter
+	requestDuration                metric.Float64Histogram
+	responseSize                   metric.Int64Histogram
+	requestSize                    metric.Int64Histogram
+	outboundRequests               metric.Int64Counter
+	outboundRequestsDuration       metric.Float64Histogram
+	outboundInFlightRequests       metric.Int64UpDownCounter
+	errorLevel                     metric.Int64Counter
+	errorCode                      metric.Int64Counter
+	db                             dbInstruments
+}
+
+// New creates a new metrics instance with the default instruments.
+//
+// Unless configured with WithReader, the metrics are periodically exported to
+// the OTLP/HTTP collector configured with WithEndpointURL, or with the
+// standard OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT
+// environment variables (default https://localhost:4318/v1/metrics).
+//
+// The Close method must be called before exiting the program to export the pending metrics.
+func New(opts ...Option) (*Client, error) {
+	ctx := context.Background()
+	c := initClient()
+
+	for _, applyOpt := range opts {
+		err := applyOpt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.reader == nil {
+		exporter, err := otlpmetrichttp.New(ctx, c.otlpOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+		}
+
+		c.reader = sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(c.exportInterval))
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", c.serviceName)}
+
+	if c.serviceVersion != "" {
+		attrs = append(attrs, attribute.String("service.version", c.serviceVersion))
+	}
+
+	c.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(c.reader),
+		sdkmetric.WithResource(resource.NewSchemaless(attrs...)),
+	)
+
+	c.meter = c.provider.Meter(meterName)
+
+	err := c.defaultInstruments()
+	if err != nil {
+		return nil, errors.Join(err, c.provider.Shutdown(ctx))
+	}
+
+	return c, nil
+}
+
+func initClient() *Client {
+	return &Client{
+		exportInterval:                 DefaultExportInterval,
+		serviceName:                    DefaultServiceName,
+		inboundRequestSizeBuckets:      exponentialBuckets(100, 10, 6),
+		inboundResponseSizeBuckets:     exponentialBuckets(100, 10, 6),
+		inboundRequestDurationBuckets:  exponentialBuckets(0.001, 10, 6),
+		outboundRequestDurationBuckets: exponentialBuckets(0.001, 10, 6),
+	}
+}
+
+// InstrumentDB wraps a sql.DB to collect metrics.
+func (c *Client) InstrumentDB(dbName string, db *sql.DB) error {
+	_, err := c.meter.RegisterCallback(
+		func(_ context.Context, o metric.Observer) error {
+			c.db.observe(o, db.Stats(), metric.WithAttributes(attribute.String(labelDBName, dbName)))
+			return nil
+		},
+		c.db.observables()...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed registering the database metrics: %w", err)
+	}
+
+	return nil
+}
+
+// InstrumentHandler wraps an http.Handler to collect OpenTelemetry metrics.
+func (c *Client) InstrumentHandler(path string, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		start := time.Now()
+
+		c.inFlightRequests.Add(ctx, 1)
+		defer c.inFlightRequests.Add(ctx, -1)
+
+		reqSize := requestSize(r)
+		rw := libhttputil.NewResponseWriterWrapper(w)
+
+		defer func() {
+			status := rw.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			attrs := metric.WithAttributes(
+				attribute.String(labelHandler, path),
+				attribute.String(labelMethod, r.Method),
+			)
+
+			c.apiRequests.Add(ctx, 1, metric.WithAttributes(
+				attribute.String(labelHandler, path),
+				attribute.String(labelCode, strconv.Itoa(status)),
+				attribute.String(labelMethod, r.Method),
+			))
+			c.requestDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+			c.requestSize.Record(ctx, reqSize, attrs)
+			c.responseSize.Record(ctx, int64(rw.Size()), attrs)
+		}()
+
+		handler.ServeHTTP(rw, r)
+	})
+}
+
+// InstrumentRoundTripper is a middleware that wraps the provided http.RoundTripper to observe the request result with default metrics.
+func (c *Client) InstrumentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		ctx := r.Context()
+		start := time.Now()
+
+		c.outboundInFlightRequests.Add(ctx, 1)
+		defer c.outboundInFlightRequests.Add(ctx, -1)
+
+		resp, err := next.RoundTrip(r)
+		if err == nil {
+			attrs := metric.WithAttributes(
+				attribute.String(labelCode, strconv.Itoa(resp.StatusCode)),
+				attribute.String(labelMethod, r.Method),
+			)
+
+			c.outboundRequests.Add(ctx, 1, attrs)
+			c.outboundRequestsDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+		}
+
+		return resp, err //nolint:wrapcheck
+	})
+}
+
+// MetricsHandlerFunc returns an http handler function to serve the metrics endpoint.
+// This is not used for the OpenTelemetry implementation as the metrics are pushed to the collector.
+func (c *Client) MetricsHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		status := http.StatusNotImplemented
+		http.Error(w, http.StatusText(status), status)
+	}
+}
+
+// IncLogLevelCounter counts the number of errors for each log severity level.
+func (c *Client) IncLogLevelCounter(level string) {
+	c.errorLevel.Add(context.Background(), 1, metric.WithAttributes(attribute.String(labelLevel, level)))
+}
+
+// IncErrorCounter increments the number of errors by task, operation and error code.
+func (c *Client) IncErrorCounter(task, operation, code string) {
+	c.errorCode.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String(labelTask, task),
+		attribute.String(labelOperation, operation),
+		attribute.String(labelCode, code),
+	))
+}
+
+// ForceFlush exports all the pending metrics.
+func (c *Client) ForceFlush(ctx context.Context) error {
+	err := c.provider.ForceFlush(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to flush the metrics: %w", err)
+	}
+
+	return nil
+}
+
+// Close exports the pending metrics and stops the exporter.
+func (c *Client) Close() error {
+	err := c.provider.Shutdown(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to shutdown the meter provider: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func (c *Client) defaultInstruments() error {
+	var err error
+
+	c.inFlightRequests, err = c.meter.Int64UpDownCounter(
+		NameInFlightRequests,
+		metric.WithDescription("Number of In-flight http requests."),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.apiRequests, err = c.meter.Int64Counter(
+		NameAPIRequests,
+		metric.WithDescription("Total number of http requests."),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.requestDuration, err = c.meter.Float64Histogram(
+		NameRequestDuration,
+		metric.WithDescription("Requests duration in seconds."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(c.inboundRequestDurationBuckets...),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.responseSize, err = c.meter.Int64Histogram(
+		NameResponseSize,
+		metric.WithDescription("Response size in bytes."),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(c.inboundResponseSizeBuckets...),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.requestSize, err = c.meter.Int64Histogram(
+		NameRequestSize,
+		metric.WithDescription("Requests size in bytes."),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(c.inboundRequestSizeBuckets...),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.outboundRequests, err = c.meter.Int64Counter(
+		NameOutboundRequests,
+		metric.WithDescription("Total number of outbound http requests."),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.outboundRequestsDuration, err = c.meter.Float64Histogram(
+		NameOutboundRequestsDuration,
+		metric.WithDescription("Outbound requests duration in seconds."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(c.outboundRequestDurationBuckets...),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.outboundInFlightRequests, err = c.meter.Int64UpDownCounter(
+		NameOutboundInFlightRequests,
+		metric.WithDescription("Number of outbound In-flight http requests."),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.errorLevel, err = c.meter.Int64Counter(
+		NameErrorLevel,
+		metric.WithDescription("Number of errors by severity level."),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	c.errorCode, err = c.meter.Int64Counter(
+		NameErrorCode,
+		metric.WithDescription("Number of errors by task, operation and error code."),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	return c.db.init(c.meter)
+}
+
+func instrumentError(err error) error {
+	return fmt.Errorf("failed creating instrument: %w", err)
+}
+
+// requestSize returns the approximate size of the request in bytes.
+func requestSize(r *http.Request) int64 {
+	size := len(r.Method) + len(r.Proto) + len(r.Host)
+
+	if r.URL != nil {
+		size += len(r.URL.String())
+	}
+
+	for name, values := range r.Header {
+		size += len(name)
+
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+
+	if r.ContentLength > 0 {
+		size += int(r.ContentLength)
+	}
+
+	return int64(size)
+}
+
+// exponentialBuckets returns count buckets, where the lowest bucket has an upper bound of start
+// and each following bucket's upper bound is factor times the previous one.
+func exponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+
+	return buckets
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (rt roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return rt(r)
+}
@@ -0,0 +1,340 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collect reads all the metrics from the manual reader and returns them by name.
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+
+	out := make(map[string]metricdata.Metrics)
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out[m.Name] = m
+		}
+	}
+
+	return out
+}
+
+func sumValue[N int64 | float64](t *testing.T, m metricdata.Metrics) N {
+	t.Helper()
+
+	sum, ok := m.Data.(metricdata.Sum[N])
+	require.True(t, ok, "unexpected data type %T", m.Data)
+
+	var v N
+	for _, dp := range sum.DataPoints {
+		v += dp.Value
+	}
+
+	return v
+}
+
+func histogramCount[N int64 | float64](t *testing.T, m metricdata.Metrics) uint64 {
+	t.Helper()
+
+	h, ok := m.Data.(metricdata.Histogram[N])
+	require.True(t, ok, "unexpected data type %T", m.Data)
+
+	var v uint64
+	for _, dp := range h.DataPoints {
+		v += dp.Count
+	}
+
+	return v
+}
+
+func newTestClient(t *testing.T, opts ...Option) (*Client, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+
+	c, err := New(append([]Option{WithReader(reader)}, opts...)...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c, reader
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name:    "succeeds with empty options",
+			wantErr: false,
+		},
+		{
+			name: "succeeds with custom options",
+			opts: []Option{
+				WithReader(sdkmetric.NewManualReader()),
+				WithService("test", "1.2.3"),
+				WithInboundRequestDurationBuckets([]float64{0.1, 1}),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "fails with invalid option",
+			opts:    []Option{func(_ *Client) error { return errors.New("Error") }},
+			wantErr: true,
+		},
+		{
+			name: "fails with invalid buckets",
+			opts: []Option{
+				WithReader(sdkmetric.NewManualReader()),
+				WithInboundRequestSizeBuckets([]float64{3, 2, 1}),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := New(tt.opts...)
+
+			if tt.wantErr {
+				require.Error(t, err, "New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err, "New() unexpected error = %v", err)
+			require.NotNil(t, c)
+		})
+	}
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	handler := c.InstrumentHandler("/test", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	m := collect(t, reader)
+
+	require.Equal(t, int64(1), sumValue[int64](t, m[NameAPIRequests]))
+	require.Equal(t, int64(0), sumValue[int64](t, m[NameInFlightRequests]))
+	require.Equal(t, uint64(1), histogramCount[float64](t, m[NameRequestDuration]))
+	require.Equal(t, uint64(1), histogramCount[int64](t, m[NameRequestSize]))
+	require.Equal(t, uint64(1), histogramCount[int64](t, m[NameResponseSize]))
+
+	sum, ok := m[NameAPIRequests].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	attrs := sum.DataPoints[0].Attributes
+
+	code, _ := attrs.Value(labelCode)
+	require.Equal(t, "201", code.AsString())
+
+	path, _ := attrs.Value(labelHandler)
+	require.Equal(t, "/test", path.AsString())
+
+	method, _ := attrs.Value(labelMethod)
+	require.Equal(t, http.MethodGet, method.AsString())
+
+	resp, ok := m[NameResponseSize].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+
+	maxSize, _ := resp.DataPoints[0].Max.Value()
+	require.Equal(t, int64(2), maxSize)
+}
+
+func TestInstrumentRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: c.InstrumentRoundTripper(http.DefaultTransport)}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	// failed requests are not counted
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodGet, "http://127.0.0.1:1", nil)
+	require.NoError(t, err)
+
+	resp, err = client.Do(req) //nolint:bodyclose
+	require.Error(t, err)
+	require.Nil(t, resp)
+
+	m := collect(t, reader)
+
+	require.Equal(t, int64(1), sumValue[int64](t, m[NameOutboundRequests]))
+	require.Equal(t, int64(0), sumValue[int64](t, m[NameOutboundInFlightRequests]))
+	require.Equal(t, uint64(1), histogramCount[float64](t, m[NameOutboundRequestsDuration]))
+
+	sum, ok := m[NameOutboundRequests].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	code, _ := sum.DataPoints[0].Attributes.Value(labelCode)
+	require.Equal(t, "202", code.AsString())
+}
+
+func TestMetricsHandlerFunc(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestClient(t)
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+
+	c.MetricsHandlerFunc().ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNotImplemented, rr.Code)
+}
+
+func TestIncLogLevelCounter(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	c.IncLogLevelCounter("debug")
+	c.IncLogLevelCounter("debug")
+
+	m := collect(t, reader)
+
+	require.Equal(t, int64(2), sumValue[int64](t, m[NameErrorLevel]))
+}
+
+func TestIncErrorCounter(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	c.IncErrorCounter("test_task", "test_operation", "3791")
+
+	m := collect(t, reader)
+
+	require.Equal(t, int64(1), sumValue[int64](t, m[NameErrorCode]))
+
+	sum, ok := m[NameErrorCode].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	task, _ := sum.DataPoints[0].Attributes.Value(labelTask)
+	require.Equal(t, "test_task", task.AsString())
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(WithReader(sdkmetric.NewManualReader()))
+	require.NoError(t, err)
+
+	require.NoError(t, c.Close())
+	require.Error(t, c.Close(), "expected error when closing twice")
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux   sync.Mutex
+		names []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var req colmetricpb.ExportMetricsServiceRequest
+
+		if err := proto.Unmarshal(body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mux.Lock()
+
+		for _, rm := range req.GetResourceMetrics() {
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, m := range sm.GetMetrics() {
+					names = append(names, m.GetName())
+				}
+			}
+		}
+
+		mux.Unlock()
+
+		resp, _ := proto.Marshal(&colmetricpb.ExportMetricsServiceResponse{})
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	c, err := New(
+		WithEndpointURL(server.URL+"/v1/metrics"),
+		WithHeaders(map[string]string{"Authorization": "Bearer test"}),
+		WithHTTPClient(server.Client()),
+		WithExportInterval(time.Hour),
+		WithService("test", "1.0.0"),
+	)
+	require.NoError(t, err)
+
+	c.IncErrorCounter("task", "operation", "code")
+
+	require.NoError(t, c.ForceFlush(t.Context()))
+	require.NoError(t, c.Close())
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	require.Contains(t, names, NameErrorCode)
+}
+
+func Test_exponentialBuckets(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []float64{100, 1000, 10000}, exponentialBuckets(100, 10, 3))
+}
@@ -0,0 +1,166 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Counter registers a custom counter instrument with the specified label names.
+func (c *Client) Counter(name, help string, labelNames ...string) (metrics.Counter, error) {
+	if name == "" {
+		return nil, errors.New("the metric name is required")
+	}
+
+	inst, err := c.meter.Float64Counter(name, metric.WithDescription(help))
+	if err != nil {
+		return nil, customInstrumentError(name, err)
+	}
+
+	return &counter{labels: labels(labelNames), inst: inst}, nil
+}
+
+// Gauge registers a custom gauge instrument with the specified label names.
+func (c *Client) Gauge(name, help string, labelNames ...string) (metrics.Gauge, error) {
+	if name == "" {
+		return nil, errors.New("the metric name is required")
+	}
+
+	inst, err := c.meter.Float64Gauge(name, metric.WithDescription(help))
+	if err != nil {
+		return nil, customInstrumentError(name, err)
+	}
+
+	return &gauge{labels: labels(labelNames), inst: inst, values: make(map[string]float64)}, nil
+}
+
+// Histogram registers a custom histogram instrument with the specified buckets and label names.
+// The OpenTelemetry SDK default buckets are used when the buckets are empty.
+func (c *Client) Histogram(name, help string, buckets []float64, labelNames ...string) (metrics.Histogram, error) {
+	if name == "" {
+		return nil, errors.New("the metric name is required")
+	}
+
+	opts := []metric.Float64HistogramOption{metric.WithDescription(help)}
+
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+
+	inst, err := c.meter.Float64Histogram(name, opts...)
+	if err != nil {
+		return nil, customInstrumentError(name, err)
+	}
+
+	return &histogram{labels: labels(labelNames), inst: inst}, nil
+}
+
+// Summary registers a custom summary metric with the specified label names.
+// OpenTelemetry has no summary instrument, so the values are recorded in a
+// histogram with the SDK default buckets and the objectives are ignored.
+func (c *Client) Summary(name, help string, _ map[float64]float64, labelNames ...string) (metrics.Summary, error) {
+	return c.Histogram(name, help, nil, labelNames...)
+}
+
+func customInstrumentError(name string, err error) error {
+	return fmt.Errorf("failed creating the %s instrument: %w", name, err)
+}
+
+// labels contains the label names of a custom metric.
+type labels []string
+
+// attributes returns the attribute option with the label values,
+// or false if the number of label values doesn't match the label names.
+func (l labels) attributes(labelValues []string) (metric.MeasurementOption, bool) {
+	if len(labelValues) != len(l) {
+		return nil, false
+	}
+
+	attrs := make([]attribute.KeyValue, len(l))
+
+	for i, name := range l {
+		attrs[i] = attribute.String(name, labelValues[i])
+	}
+
+	return metric.WithAttributes(attrs...), true
+}
+
+// counter is the OpenTelemetry implementation of metrics.Counter.
+type counter struct {
+	labels
+	inst metric.Float64Counter
+}
+
+// Inc increments the counter by 1.
+func (m *counter) Inc(labelValues ...string) {
+	m.Add(1, labelValues...)
+}
+
+// Add adds the specified non-negative value to the counter.
+func (m *counter) Add(value float64, labelValues ...string) {
+	attrs, ok := m.attributes(labelValues)
+	if !ok || value < 0 {
+		return
+	}
+
+	m.inst.Add(context.Background(), value, attrs)
+}
+
+// gauge is the OpenTelemetry implementation of metrics.Gauge.
+// The current values are tracked locally to support the Add method.
+type gauge struct {
+	labels
+	inst metric.Float64Gauge
+
+	mux    sync.Mutex
+	values map[string]float64
+}
+
+// Set sets the gauge to the specified value.
+func (m *gauge) Set(value float64, labelValues ...string) {
+	m.update(labelValues, func(float64) float64 { return value })
+}
+
+// Add adds the specified value (positive or negative) to the gauge.
+func (m *gauge) Add(value float64, labelValues ...string) {
+	m.update(labelValues, func(v float64) float64 { return v + value })
+}
+
+func (m *gauge) update(labelValues []string, fn func(v float64) float64) {
+	attrs, ok := m.attributes(labelValues)
+	if !ok {
+		return
+	}
+
+	k := strings.Join(labelValues, "\xff")
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	v := fn(m.values[k])
+	m.values[k] = v
+
+	m.inst.Record(context.Background(), v, attrs)
+}
+
+// histogram is the OpenTelemetry implementation of metrics.Histogram and metrics.Summary.
+type histogram struct {
+	labels
+	inst metric.Float64Histogram
+}
+
+// Observe records a single observation.
+func (m *histogram) Observe(value float64, labelValues ...string) {
+	attrs, ok := m.attributes(labelValues)
+	if !ok {
+		return
+	}
+
+	m.inst.Record(context.Background(), value, attrs)
+}
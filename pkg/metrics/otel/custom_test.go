@@ -0,0 +1,103 @@
+package otel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	m, err := c.Counter("test_orders_total", "Number of orders.", "status")
+	require.NoError(t, err)
+
+	m.Inc("ok")
+	m.Add(2, "ok")
+	m.Add(-1, "ok")      // ignored
+	m.Inc("ok", "extra") // ignored
+	m.Inc("failed")
+
+	got := collect(t, reader)
+
+	require.InDelta(t, 4.0, sumValue[float64](t, got["test_orders_total"]), 0)
+
+	sum, ok := got["test_orders_total"].Data.(metricdata.Sum[float64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 2)
+
+	_, err = c.Counter("", "Invalid.")
+	require.Error(t, err)
+}
+
+func TestGauge(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	m, err := c.Gauge("test_queue_size", "Queue size.", "queue")
+	require.NoError(t, err)
+
+	m.Set(5, "a")
+	m.Add(-2, "a")
+	m.Set(1) // ignored
+
+	got := collect(t, reader)
+
+	gauge, ok := got["test_queue_size"].Data.(metricdata.Gauge[float64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	require.InDelta(t, 3.0, gauge.DataPoints[0].Value, 0)
+
+	queue, _ := gauge.DataPoints[0].Attributes.Value("queue")
+	require.Equal(t, "a", queue.AsString())
+
+	_, err = c.Gauge("", "Invalid.")
+	require.Error(t, err)
+}
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	m, err := c.Histogram("test_amount", "Order amount.", []float64{10, 100}, "currency")
+	require.NoError(t, err)
+
+	m.Observe(5, "EUR")
+	m.Observe(50, "EUR")
+	m.Observe(500) // ignored
+
+	got := collect(t, reader)
+
+	h, ok := got["test_amount"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, h.DataPoints, 1)
+	require.Equal(t, []float64{10, 100}, h.DataPoints[0].Bounds)
+	require.Equal(t, []uint64{1, 1, 0}, h.DataPoints[0].BucketCounts)
+
+	_, err = c.Histogram("", "Invalid.", nil)
+	require.Error(t, err)
+
+	_, err = c.Histogram("test_invalid_buckets", "Invalid.", []float64{2, 1})
+	require.Error(t, err)
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	m, err := c.Summary("test_latency", "Processing latency.", map[float64]float64{0.5: 0.05}, "task")
+	require.NoError(t, err)
+
+	m.Observe(1, "sync")
+	m.Observe(3, "sync")
+
+	got := collect(t, reader)
+
+	require.Equal(t, uint64(2), histogramCount[float64](t, got["test_latency"]))
+}
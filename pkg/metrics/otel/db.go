@@ -0,0 +1,98 @@
+package otel
+
+import (
+	"database/sql"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// dbInstruments contains the asynchronous instruments observing the sql.DBStats,
+// with the same names of the github.com/dlmiddlecote/sqlstats Prometheus collector.
+type dbInstruments struct {
+	maxOpen           metric.Int64ObservableGauge
+	open              metric.Int64ObservableGauge
+	inUse             metric.Int64ObservableGauge
+	idle              metric.Int64ObservableGauge
+	waitedFor         metric.Int64ObservableCounter
+	blockedSeconds    metric.Float64ObservableCounter
+	closedMaxIdle     metric.Int64ObservableCounter
+	closedMaxLifetime metric.Int64ObservableCounter
+	closedMaxIdleTime metric.Int64ObservableCounter
+}
+
+func (d *dbInstruments) init(m metric.Meter) error {
+	var err error
+
+	gauges := []struct {
+		inst *metric.Int64ObservableGauge
+		name string
+		desc string
+	}{
+		{&d.maxOpen, "max_open", "Maximum number of open connections to the database."},
+		{&d.open, "open", "The number of established connections both in use and idle."},
+		{&d.inUse, "in_use", "The number of connections currently in use."},
+		{&d.idle, "idle", "The number of idle connections."},
+	}
+
+	for _, g := range gauges {
+		*g.inst, err = m.Int64ObservableGauge(NameDBPrefix+g.name, metric.WithDescription(g.desc))
+		if err != nil {
+			return instrumentError(err)
+		}
+	}
+
+	counters := []struct {
+		inst *metric.Int64ObservableCounter
+		name string
+		desc string
+	}{
+		{&d.waitedFor, "waited_for", "The total number of connections waited for."},
+		{&d.closedMaxIdle, "closed_max_idle", "The total number of connections closed due to SetMaxIdleConns."},
+		{&d.closedMaxLifetime, "closed_max_lifetime", "The total number of connections closed due to SetConnMaxLifetime."},
+		{&d.closedMaxIdleTime, "closed_max_idle_time", "The total number of connections closed due to SetConnMaxIdleTime."},
+	}
+
+	for _, c := range counters {
+		*c.inst, err = m.Int64ObservableCounter(NameDBPrefix+c.name, metric.WithDescription(c.desc))
+		if err != nil {
+			return instrumentError(err)
+		}
+	}
+
+	d.blockedSeconds, err = m.Float64ObservableCounter(
+		NameDBPrefix+"blocked_seconds",
+		metric.WithDescription("The total time blocked waiting for a new connection."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return instrumentError(err)
+	}
+
+	return nil
+}
+
+func (d *dbInstruments) observables() []metric.Observable {
+	return []metric.Observable{
+		d.maxOpen,
+		d.open,
+		d.inUse,
+		d.idle,
+		d.waitedFor,
+		d.blockedSeconds,
+		d.closedMaxIdle,
+		d.closedMaxLifetime,
+		d.closedMaxIdleTime,
+	}
+}
+
+func (d *dbInstruments) observe(o metric.Observer, s sql.DBStats, opt metric.ObserveOption) {
+	o.ObserveInt64(d.maxOpen, int64(s.MaxOpenConnections), opt)
+	o.ObserveInt64(d.open, int64(s.OpenConnections), opt)
+	o.ObserveInt64(d.inUse, int64(s.InUse), opt)
+	o.ObserveInt64(d.idle, int64(s.Idle), opt)
+	o.ObserveInt64(d.waitedFor, s.WaitCount, opt)
+	o.ObserveFloat64(d.blockedSeconds, s.WaitDuration.Seconds(), opt)
+	o.ObserveInt64(d.closedMaxIdle, s.MaxIdleClosed, opt)
+	o.ObserveInt64(d.closedMaxLifetime, s.MaxLifetimeClosed, opt)
+	o.ObserveInt64(d.closedMaxIdleTime, s.MaxIdleTimeClosed, opt)
+}
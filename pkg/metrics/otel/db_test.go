@@ -0,0 +1,42 @@
+package otel
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestInstrumentDB(t *testing.T) {
+	t.Parallel()
+
+	c, reader := newTestClient(t)
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer func() { _ = db.Close() }()
+
+	db.SetMaxOpenConns(7)
+
+	err = c.InstrumentDB("db_test", db)
+	require.NoError(t, err)
+
+	m := collect(t, reader)
+
+	for _, name := range []string{
+		"max_open", "open", "in_use", "idle",
+		"waited_for", "blocked_seconds", "closed_max_idle", "closed_max_lifetime", "closed_max_idle_time",
+	} {
+		require.Contains(t, m, NameDBPrefix+name)
+	}
+
+	gauge, ok := m[NameDBPrefix+"max_open"].Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	require.Equal(t, int64(7), gauge.DataPoints[0].Value)
+
+	dbName, _ := gauge.DataPoints[0].Attributes.Value(labelDBName)
+	require.Equal(t, "db_test", dbName.AsString())
+}
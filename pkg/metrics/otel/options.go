@@ -0,0 +1,114 @@
+package otel
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Option is the interface that allows to set client options.
+type Option func(c *Client) error
+
+// WithService sets the name and version of the service reported with the metrics.
+func WithService(name, version string) Option {
+	return func(c *Client) error {
+		if name == "" {
+			return errors.New("the service name is required")
+		}
+
+		c.serviceName = name
+		c.serviceVersion = version
+
+		return nil
+	}
+}
+
+// WithEndpointURL sets the OTLP/HTTP collector URL (e.g. "http://localhost:4318/v1/metrics").
+func WithEndpointURL(url string) Option {
+	return func(c *Client) error {
+		if url == "" {
+			return errors.New("the endpoint URL is required")
+		}
+
+		c.otlpOpts = append(c.otlpOpts, otlpmetrichttp.WithEndpointURL(url))
+
+		return nil
+	}
+}
+
+// WithHeaders sets additional HTTP headers sent to the OTLP/HTTP collector (e.g. for authentication).
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) error {
+		c.otlpOpts = append(c.otlpOpts, otlpmetrichttp.WithHeaders(headers))
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send the metrics to the OTLP/HTTP collector.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) error {
+		c.otlpOpts = append(c.otlpOpts, otlpmetrichttp.WithHTTPClient(client))
+		return nil
+	}
+}
+
+// WithExportInterval sets the interval between two exports to the OTLP/HTTP collector.
+func WithExportInterval(interval time.Duration) Option {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return errors.New("the export interval must be positive")
+		}
+
+		c.exportInterval = interval
+
+		return nil
+	}
+}
+
+// WithReader sets a custom metrics reader (e.g. sdkmetric.NewManualReader) in place of the periodic OTLP/HTTP exporter.
+func WithReader(reader sdkmetric.Reader) Option {
+	return func(c *Client) error {
+		if reader == nil {
+			return errors.New("the reader is required")
+		}
+
+		c.reader = reader
+
+		return nil
+	}
+}
+
+// WithInboundRequestSizeBuckets set the buckets size in bytes for the inbound requests.
+func WithInboundRequestSizeBuckets(buckets []float64) Option {
+	return func(c *Client) error {
+		c.inboundRequestSizeBuckets = buckets
+		return nil
+	}
+}
+
+// WithInboundResponseSizeBuckets set the buckets size in bytes for the inbound response.
+func WithInboundResponseSizeBuckets(buckets []float64) Option {
+	return func(c *Client) error {
+		c.inboundResponseSizeBuckets = buckets
+		return nil
+	}
+}
+
+// WithInboundRequestDurationBuckets set the buckets size in seconds for the inbound requests duration.
+func WithInboundRequestDurationBuckets(buckets []float64) Option {
+	return func(c *Client) error {
+		c.inboundRequestDurationBuckets = buckets
+		return nil
+	}
+}
+
+// WithOutboundRequestDurationBuckets set the buckets size in seconds for the outbound requests duration.
+func WithOutboundRequestDurationBuckets(buckets []float64) Option {
+	return func(c *Client) error {
+		c.outboundRequestDurationBuckets = buckets
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package otel
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestWithService(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	err := WithService("test", "1.2.3")(c)
+	require.NoError(t, err)
+	require.Equal(t, "test", c.serviceName)
+	require.Equal(t, "1.2.3", c.serviceVersion)
+
+	err = WithService("", "1.2.3")(c)
+	require.Error(t, err)
+}
+
+func TestWithEndpointURL(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	err := WithEndpointURL("http://localhost:4318/v1/metrics")(c)
+	require.NoError(t, err)
+	require.Len(t, c.otlpOpts, 1)
+
+	err = WithEndpointURL("")(c)
+	require.Error(t, err)
+}
+
+func TestWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	err := WithHeaders(map[string]string{"key": "value"})(c)
+	require.NoError(t, err)
+	require.Len(t, c.otlpOpts, 1)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	err := WithHTTPClient(&http.Client{})(c)
+	require.NoError(t, err)
+	require.Len(t, c.otlpOpts, 1)
+}
+
+func TestWithExportInterval(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	err := WithExportInterval(time.Minute)(c)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, c.exportInterval)
+
+	err = WithExportInterval(0)(c)
+	require.Error(t, err)
+}
+
+func TestWithReader(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	reader := sdkmetric.NewManualReader()
+	err := WithReader(reader)(c)
+	require.NoError(t, err)
+	require.Equal(t, reader, c.reader)
+
+	err = WithReader(nil)(c)
+	require.Error(t, err)
+}
+
+func TestWithInboundRequestSizeBuckets(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	opt := []float64{1, 2, 3}
+	err := WithInboundRequestSizeBuckets(opt)(c)
+	require.NoError(t, err)
+	require.Equal(t, opt, c.inboundRequestSizeBuckets, "expecting %v, got %v", opt, c.inboundRequestSizeBuckets)
+}
+
+func TestWithInboundResponseSizeBuckets(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	opt := []float64{4, 5, 6}
+	err := WithInboundResponseSizeBuckets(opt)(c)
+	require.NoError(t, err)
+	require.Equal(t, opt, c.inboundResponseSizeBuckets, "expecting %v, got %v", opt, c.inboundResponseSizeBuckets)
+}
+
+func TestWithInboundRequestDurationBuckets(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	opt := []float64{7, 8, 9}
+	err := WithInboundRequestDurationBuckets(opt)(c)
+	require.NoError(t, err)
+	require.Equal(t, opt, c.inboundRequestDurationBuckets, "expecting %v, got %v", opt, c.inboundRequestDurationBuckets)
+}
+
+func TestWithOutboundRequestDurationBuckets(t *testing.T) {
+	t.Parallel()
+
+	c := initClient()
+	opt := []float64{10, 11, 12}
+	err := WithOutboundRequestDurationBuckets(opt)(c)
+	require.NoError(t, err)
+	require.Equal(t, opt, c.outboundRequestDurationBuckets, "expecting %v, got %v", opt, c.outboundRequestDurationBuckets)
+}
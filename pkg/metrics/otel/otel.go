@@ -0,0 +1,21 @@
+/*
+Package otel implements the metrics interface for OpenTelemetry.
+
+The metrics are recorded with the OpenTelemetry metrics SDK and periodically
+pushed to an OpenTelemetry collector via OTLP/HTTP, so the services migrating
+to OpenTelemetry keep the same instrumentation surface of the Prometheus and
+StatsD implementations.
+
+This package is based on go.opentelemetry.io/otel/sdk/metric and provides the
+same default metrics (with the same names and attributes) of the
+github.com/Vonage/gosrvlib/pkg/metrics/prometheus package for the following
+components:
+  - HTTP Server
+  - HTTP Client
+  - SQL Database
+
+The custom metrics (Counter, Gauge, Histogram and Summary) are recorded with
+the corresponding synchronous instruments. As OpenTelemetry has no summary
+instrument, the Summary values are recorded in a histogram.
+*/
+package otel
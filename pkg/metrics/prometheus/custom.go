@@ -0,0 +1,133 @@
+package prometheus
+
+import (
+	"fmt"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Counter registers a custom counter metric with the specified label names.
+func (c *Client) Counter(name, help string, labelNames ...string) (metrics.Counter, error) {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames)
+
+	err := c.register(name, vec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &counter{vec: vec}, nil
+}
+
+// Gauge registers a custom gauge metric with the specified label names.
+func (c *Client) Gauge(name, help string, labelNames ...string) (metrics.Gauge, error) {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames)
+
+	err := c.register(name, vec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gauge{vec: vec}, nil
+}
+
+// Histogram registers a custom histogram metric with the specified buckets and label names.
+// The prometheus.DefBuckets are used when the buckets are empty.
+func (c *Client) Histogram(name, help string, buckets []float64, labelNames ...string) (metrics.Histogram, error) {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labelNames)
+
+	err := c.register(name, vec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &observer{vec: vec}, nil
+}
+
+// Summary registers a custom summary metric with the specified quantile objectives
+// (quantile: absolute error) and label names.
+func (c *Client) Summary(name, help string, objectives map[float64]float64, labelNames ...string) (metrics.Summary, error) {
+	vec := prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: name, Help: help, Objectives: objectives}, labelNames)
+
+	err := c.register(name, vec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &observer{vec: vec}, nil
+}
+
+func (c *Client) register(name string, coll prometheus.Collector) error {
+	err := c.registry.Register(coll)
+	if err != nil {
+		return fmt.Errorf("failed registering the %s collector: %w", name, err)
+	}
+
+	return nil
+}
+
+// counter is the Prometheus implementation of metrics.Counter.
+type counter struct {
+	vec *prometheus.CounterVec
+}
+
+// Inc increments the counter by 1.
+func (m *counter) Inc(labelValues ...string) {
+	m.Add(1, labelValues...)
+}
+
+// Add adds the specified non-negative value to the counter.
+func (m *counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+
+	c, err := m.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		return
+	}
+
+	c.Add(value)
+}
+
+// gauge is the Prometheus implementation of metrics.Gauge.
+type gauge struct {
+	vec *prometheus.GaugeVec
+}
+
+// Set sets the gauge to the specified value.
+func (m *gauge) Set(value float64, labelValues ...string) {
+	g, err := m.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		return
+	}
+
+	g.Set(value)
+}
+
+// Add adds the specified value (positive or negative) to the gauge.
+func (m *gauge) Add(value float64, labelValues ...string) {
+	g, err := m.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		return
+	}
+
+	g.Add(value)
+}
+
+// observer is the Prometheus implementation of metrics.Histogram and metrics.Summary.
+type observer struct {
+	vec interface {
+		GetMetricWithLabelValues(lvs ...string) (prometheus.Observer, error)
+	}
+}
+
+// Observe adds a single observation.
+func (m *observer) Observe(value float64, labelValues ...string) {
+	o, err := m.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		return
+	}
+
+	o.Observe(value)
+}
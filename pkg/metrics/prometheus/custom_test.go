@@ -0,0 +1,114 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err)
+
+	m, err := c.Counter("test_orders_total", "Number of orders.", "status")
+	require.NoError(t, err)
+
+	m.Inc("ok")
+	m.Add(2, "ok")
+	m.Add(-1, "ok")      // ignored
+	m.Inc("ok", "extra") // ignored
+	m.Inc("failed")
+
+	exp := `
+# HELP test_orders_total Number of orders.
+# TYPE test_orders_total counter
+test_orders_total{status="failed"} 1
+test_orders_total{status="ok"} 3
+`
+	err = testutil.GatherAndCompare(c.registry, strings.NewReader(exp), "test_orders_total")
+	require.NoError(t, err)
+
+	_, err = c.Counter("test_orders_total", "Duplicate.", "status")
+	require.Error(t, err, "expected error with duplicate collector")
+
+	_, err = c.Counter("", "Invalid.")
+	require.Error(t, err, "expected error with invalid name")
+}
+
+func TestGauge(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err)
+
+	m, err := c.Gauge("test_queue_size", "Queue size.", "queue")
+	require.NoError(t, err)
+
+	m.Set(5, "a")
+	m.Add(-2, "a")
+	m.Add(1, "b")
+	m.Set(1) // ignored
+
+	exp := `
+# HELP test_queue_size Queue size.
+# TYPE test_queue_size gauge
+test_queue_size{queue="a"} 3
+test_queue_size{queue="b"} 1
+`
+	err = testutil.GatherAndCompare(c.registry, strings.NewReader(exp), "test_queue_size")
+	require.NoError(t, err)
+
+	_, err = c.Gauge("test_queue_size", "Duplicate.")
+	require.Error(t, err)
+}
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err)
+
+	m, err := c.Histogram("test_amount", "Order amount.", []float64{10, 100}, "currency")
+	require.NoError(t, err)
+
+	m.Observe(5, "EUR")
+	m.Observe(50, "EUR")
+	m.Observe(500) // ignored
+
+	exp := `
+# HELP test_amount Order amount.
+# TYPE test_amount histogram
+test_amount_bucket{currency="EUR",le="10"} 1
+test_amount_bucket{currency="EUR",le="100"} 2
+test_amount_bucket{currency="EUR",le="+Inf"} 2
+test_amount_sum{currency="EUR"} 55
+test_amount_count{currency="EUR"} 2
+`
+	err = testutil.GatherAndCompare(c.registry, strings.NewReader(exp), "test_amount")
+	require.NoError(t, err)
+
+	_, err = c.Histogram("test_amount", "Duplicate.", nil)
+	require.Error(t, err)
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err)
+
+	m, err := c.Summary("test_latency", "Processing latency.", map[float64]float64{0.5: 0.05}, "task")
+	require.NoError(t, err)
+
+	m.Observe(1, "sync")
+	m.Observe(3, "sync")
+
+	require.Equal(t, 1, testutil.CollectAndCount(c.registry, "test_latency"))
+
+	_, err = c.Summary("test_latency", "Duplicate.", nil)
+	require.Error(t, err)
+}
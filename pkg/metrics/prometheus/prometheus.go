@@ -10,5 +10,8 @@ provides default metrics for the following components:
   - HTTP Server
   - HTTP Client
   - SQL Database
+
+The custom metrics (Counter, Gauge, Histogram and Summary) are registered as
+the corresponding Prometheus vectors in the same registry.
 */
 package prometheus
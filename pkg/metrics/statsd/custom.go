@@ -0,0 +1,169 @@
+package statsd
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+)
+
+// Counter registers a custom counter metric with the specified label names.
+// The label values are appended to the bucket name (e.g. "name.value1.value2").
+func (c *Client) Counter(name, _ string, labelNames ...string) (metrics.Counter, error) {
+	b, err := c.newBucket(name, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &counter{bucket: b}, nil
+}
+
+// Gauge registers a custom gauge metric with the specified label names.
+// The label values are appended to the bucket name (e.g. "name.value1.value2").
+func (c *Client) Gauge(name, _ string, labelNames ...string) (metrics.Gauge, error) {
+	b, err := c.newBucket(name, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gauge{bucket: b, values: make(map[string]float64)}, nil
+}
+
+// Histogram registers a custom histogram metric with the specified label names.
+// The label values are appended to the bucket name (e.g. "name.value1.value2").
+// The buckets are ignored as the values are aggregated by the StatsD server.
+func (c *Client) Histogram(name, _ string, _ []float64, labelNames ...string) (metrics.Histogram, error) {
+	b, err := c.newBucket(name, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &histogram{bucket: b}, nil
+}
+
+// Summary registers a custom summary metric with the specified label names.
+// The label values are appended to the bucket name (e.g. "name.value1.value2").
+// The values are sent as timers, and the objectives are ignored as the
+// quantiles are calculated by the StatsD server.
+func (c *Client) Summary(name, _ string, _ map[float64]float64, labelNames ...string) (metrics.Summary, error) {
+	b, err := c.newBucket(name, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary{bucket: b}, nil
+}
+
+// bucket builds the StatsD bucket names of a custom metric.
+type bucket struct {
+	c      *Client
+	name   string
+	labels int
+}
+
+func (c *Client) newBucket(name string, labelNames []string) (bucket, error) {
+	if name == "" {
+		return bucket{}, errors.New("the metric name is required")
+	}
+
+	return bucket{c: c, name: name, labels: len(labelNames)}, nil
+}
+
+// key returns the bucket name with the label values,
+// or false if the number of label values doesn't match the label names.
+func (b bucket) key(labelValues []string) (string, bool) {
+	if len(labelValues) != b.labels {
+		return "", false
+	}
+
+	if b.labels == 0 {
+		return b.name, true
+	}
+
+	return b.name + labelSeparator + strings.Join(labelValues, labelSeparator), true
+}
+
+// counter is the StatsD implementation of metrics.Counter.
+type counter struct {
+	bucket
+}
+
+// Inc increments the counter by 1.
+func (m *counter) Inc(labelValues ...string) {
+	m.Add(1, labelValues...)
+}
+
+// Add adds the specified non-negative value to the counter.
+func (m *counter) Add(value float64, labelValues ...string) {
+	k, ok := m.key(labelValues)
+	if !ok || value < 0 {
+		return
+	}
+
+	m.c.statsd.Count(k, value)
+}
+
+// gauge is the StatsD implementation of metrics.Gauge.
+// The current values are tracked locally to always send the absolute value.
+type gauge struct {
+	bucket
+
+	mux    sync.Mutex
+	values map[string]float64
+}
+
+// Set sets the gauge to the specified value.
+func (m *gauge) Set(value float64, labelValues ...string) {
+	m.update(labelValues, func(float64) float64 { return value })
+}
+
+// Add adds the specified value (positive or negative) to the gauge.
+func (m *gauge) Add(value float64, labelValues ...string) {
+	m.update(labelValues, func(v float64) float64 { return v + value })
+}
+
+func (m *gauge) update(labelValues []string, fn func(v float64) float64) {
+	k, ok := m.key(labelValues)
+	if !ok {
+		return
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	v := fn(m.values[k])
+	m.values[k] = v
+
+	m.c.statsd.Gauge(k, v)
+}
+
+// histogram is the StatsD implementation of metrics.Histogram.
+type histogram struct {
+	bucket
+}
+
+// Observe sends a single observation.
+func (m *histogram) Observe(value float64, labelValues ...string) {
+	k, ok := m.key(labelValues)
+	if !ok {
+		return
+	}
+
+	m.c.statsd.Histogram(k, value)
+}
+
+// summary is the StatsD implementation of metrics.Summary.
+type summary struct {
+	bucket
+}
+
+// Observe sends a single observation as timer.
+func (m *summary) Observe(value float64, labelValues ...string) {
+	k, ok := m.key(labelValues)
+	if !ok {
+		return
+	}
+
+	m.c.statsd.Timing(k, value)
+}
@@ -0,0 +1,97 @@
+package statsd
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomMetrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux sync.Mutex
+		got string
+	)
+
+	srv, err := newTestStatsdServer(t, func(p []byte) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		got += string(p)
+	})
+	require.NoError(t, err, "newTestStatsdServer() unexpected error = %v", err)
+
+	c, err := New(
+		WithPrefix("TEST"),
+		WithNetwork(statsdTestNetwork),
+		WithAddress(srv.addr),
+		WithFlushPeriod(time.Hour),
+	)
+	require.NoError(t, err, "New() unexpected error = %v", err)
+
+	counter, err := c.Counter("orders", "Number of orders.", "status")
+	require.NoError(t, err)
+
+	counter.Inc("ok")
+	counter.Add(2, "ok")
+	counter.Add(-1, "ok")      // ignored
+	counter.Inc("ok", "extra") // ignored
+
+	gauge, err := c.Gauge("queue", "Queue size.")
+	require.NoError(t, err)
+
+	gauge.Set(5)
+	gauge.Add(-2)
+	gauge.Set(1, "extra") // ignored
+
+	histogram, err := c.Histogram("amount", "Order amount.", []float64{10, 100}, "currency")
+	require.NoError(t, err)
+
+	histogram.Observe(50, "EUR")
+	histogram.Observe(50) // ignored
+
+	summary, err := c.Summary("latency", "Processing latency.", nil, "task", "step")
+	require.NoError(t, err)
+
+	summary.Observe(3, "sync", "a")
+
+	c.Close()
+
+	exp := `TEST.orders.ok:1|c
+TEST.orders.ok:2|c
+TEST.queue:5|g
+TEST.queue:3|g
+TEST.amount.EUR:50|h
+TEST.latency.sync.a:3|ms`
+
+	require.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+
+		return got == exp
+	}, time.Second, 10*time.Millisecond, "expected: %v", exp)
+
+	srv.Close()
+}
+
+func TestCustomMetrics_error(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err, "unexpected error = %v", err)
+
+	_, err = c.Counter("", "")
+	require.Error(t, err)
+
+	_, err = c.Gauge("", "")
+	require.Error(t, err)
+
+	_, err = c.Histogram("", "", nil)
+	require.Error(t, err)
+
+	_, err = c.Summary("", "", nil)
+	require.Error(t, err)
+}
@@ -5,6 +5,10 @@ The metrics (counters and timers) are actively sent over UDP or TCP to a central
 StatsD server. StatsD is a network daemon that listens for statistics and
 aggregates them to one or more pluggable backend services (e.g., Graphite).
 
+The custom metrics are sent as counters, gauges, histograms (Histogram) and
+timers (Summary). As StatsD has no labels, the label values are appended to the
+bucket name (e.g. "name.value1.value2").
+
 This package is based on github.com/tecnickcom/statsd.
 */
 package statsd
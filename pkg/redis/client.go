@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/encode"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
 	libredis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TEncodeFunc is the type of function used to replace the default message encoding function used by SendData().
@@ -29,10 +32,12 @@ type ChannelOption = libredis.ChannelOption
 type RClient interface {
 	Close() error
 	Del(ctx context.Context, keys ...string) *libredis.IntCmd
+	Eval(ctx context.Context, script string, keys []string, args ...any) *libredis.Cmd
 	Get(ctx context.Context, key string) *libredis.StringCmd
 	Ping(ctx context.Context) *libredis.StatusCmd // this function is used by the HealthCheck
 	Publish(ctx context.Context, channel string, message any) *libredis.IntCmd
 	Set(ctx context.Context, key string, value any, expiration time.Duration) *libredis.StatusCmd
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *libredis.BoolCmd
 	Subscribe(ctx context.Context, channels ...string) *libredis.PubSub
 }
 
@@ -61,6 +66,9 @@ type Client struct {
 	// to decode a message encoded with messageEncodeFunc to the provided data object.
 	// The value underlying data must be a pointer to the correct type for the next data item received.
 	messageDecodeFunc TDecodeFunc
+
+	// tracePropagation enables the propagation of the trace context with the sent messages.
+	tracePropagation bool
 }
 
 // New creates a new instance of the Redis client wrapper.
@@ -80,6 +88,7 @@ func New(ctx context.Context, srvopt *SrvOptions, opts ...Option) (*Client, erro
 		subch:             subch,
 		messageEncodeFunc: cfg.messageEncodeFunc,
 		messageDecodeFunc: cfg.messageDecodeFunc,
+		tracePropagation:  cfg.tracePropagation,
 	}, nil
 }
 
@@ -109,6 +118,18 @@ func (c *Client) Set(ctx context.Context, key string, value any, exp time.Durati
 	return nil
 }
 
+// SetNX sets a raw value for the specified key with an expiration time, only if the key does not exist.
+// Returns true if the key has been set.
+// Zero expiration means the key has no expiration time.
+func (c *Client) SetNX(ctx context.Context, key string, value any, exp time.Duration) (bool, error) {
+	ok, err := c.rclient.SetNX(ctx, key, value, exp).Result()
+	if err != nil {
+		return false, fmt.Errorf("cannot set key %s: %w", key, err)
+	}
+
+	return ok, nil
+}
+
 // Get retrieves the raw value of the specified key and extract its content in the value parameter.
 func (c *Client) Get(ctx context.Context, key string, value any) error {
 	err := c.rclient.Get(ctx, key).Scan(value)
@@ -129,29 +150,76 @@ func (c *Client) Del(ctx context.Context, key string) error {
 	return nil
 }
 
+// Eval executes a Lua script on the server with the specified keys and arguments, and returns its result.
+// This can be used to atomically execute multiple operations (e.g. compare-and-set).
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	res, err := c.rclient.Eval(ctx, script, keys, args...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute script: %w", err)
+	}
+
+	return res, nil
+}
+
 // Send publish a raw value to the specified channel.
+// If enabled with WithTracePropagation, the W3C trace context is propagated with the string messages.
 func (c *Client) Send(ctx context.Context, channel string, message any) error {
+	ctx, span := tracing.Start(
+		ctx,
+		channel+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(spanAttributes(channel)...),
+	)
+
+	if c.tracePropagation {
+		message = wrapTraceContext(ctx, message)
+	}
+
 	err := c.rclient.Publish(ctx, channel, message).Err()
 	if err != nil {
-		return fmt.Errorf("cannot send message to %s channel: %w", channel, err)
+		err = fmt.Errorf("cannot send message to %s channel: %w", channel, err)
 	}
 
-	return nil
+	tracing.End(span, err)
+
+	return err
 }
 
 // Receive receives a raw string message from a subscribed channel.
 // Returns the channel name and the message value.
 func (c *Client) Receive(ctx context.Context) (string, string, error) {
+	_, channel, message, err := c.ReceiveContext(ctx)
+	return channel, message, err
+}
+
+// ReceiveContext receives a raw string message from a subscribed channel.
+// Returns the channel name and the message value.
+// The returned context contains the consumer span, child of the trace context propagated with the message, if any,
+// and a logger tagged with the trace and span IDs (see the tracing package).
+func (c *Client) ReceiveContext(ctx context.Context) (context.Context, string, string, error) {
+	start := time.Now()
+
 	select {
 	case <-ctx.Done():
-		return "", "", fmt.Errorf("context has been canceled: %w", ctx.Err())
+		return ctx, "", "", fmt.Errorf("context has been canceled: %w", ctx.Err())
 	case msg, ok := <-c.subch:
 		if ok && (msg != nil) {
-			return msg.Channel, msg.Payload, nil
+			tctx, payload := unwrapTraceContext(ctx, msg.Payload)
+
+			tctx, span := tracing.Start(
+				tctx,
+				msg.Channel+" receive",
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithTimestamp(start),
+				trace.WithAttributes(spanAttributes(msg.Channel)...),
+			)
+			span.End()
+
+			return logging.WithSpanContext(tctx), msg.Channel, payload, nil
 		}
 	}
 
-	return "", "", errors.New("the receiving channel is closed")
+	return ctx, "", "", errors.New("the receiving channel is closed")
 }
 
 // MessageEncode encodes and serialize the input data to a string.
@@ -213,12 +281,21 @@ func (c *Client) SendData(ctx context.Context, channel string, data any) error {
 // and extract its content in the data parameter.
 // Returns the channel name in case of success.
 func (c *Client) ReceiveData(ctx context.Context, data any) (string, error) {
-	channel, value, err := c.Receive(ctx)
+	_, channel, err := c.ReceiveDataContext(ctx, data)
+	return channel, err
+}
+
+// ReceiveDataContext receives an encoded message from a subscribed channel,
+// and extract its content in the data parameter.
+// Returns the channel name in case of success.
+// The returned context contains the trace context propagated with the message (see ReceiveContext).
+func (c *Client) ReceiveDataContext(ctx context.Context, data any) (context.Context, string, error) {
+	ctx, channel, value, err := c.ReceiveContext(ctx)
 	if err != nil {
-		return "", err
+		return ctx, "", err
 	}
 
-	return channel, c.messageDecodeFunc(ctx, value, data)
+	return ctx, channel, c.messageDecodeFunc(ctx, value, data)
 }
 
 // HealthCheck checks if the current data-store is alive.
@@ -41,10 +41,12 @@ func TestNew(t *testing.T) {
 type redisClientMock struct {
 	closeFn     func() error
 	delFn       func(ctx context.Context, keys ...string) *libredis.IntCmd
+	evalFn      func(ctx context.Context, script string, keys []string, args ...any) *libredis.Cmd
 	getFn       func(ctx context.Context, key string) *libredis.StringCmd
 	pingFn      func(ctx context.Context) *libredis.StatusCmd
 	publishFn   func(ctx context.Context, channel string, message any) *libredis.IntCmd
 	setFn       func(ctx context.Context, key string, value any, expiration time.Duration) *libredis.StatusCmd
+	setNXFn     func(ctx context.Context, key string, value any, expiration time.Duration) *libredis.BoolCmd
 	subscribeFn func(ctx context.Context, channels ...string) *libredis.PubSub
 }
 
@@ -56,6 +58,10 @@ func (m redisClientMock) Del(ctx context.Context, keys ...string) *libredis.IntC
 	return m.delFn(ctx, keys...)
 }
 
+func (m redisClientMock) Eval(ctx context.Context, script string, keys []string, args ...any) *libredis.Cmd {
+	return m.evalFn(ctx, script, keys, args...)
+}
+
 func (m redisClientMock) Get(ctx context.Context, key string) *libredis.StringCmd {
 	return m.getFn(ctx, key)
 }
@@ -72,6 +78,10 @@ func (m redisClientMock) Set(ctx context.Context, key string, value any, expirat
 	return m.setFn(ctx, key, value, expiration)
 }
 
+func (m redisClientMock) SetNX(ctx context.Context, key string, value any, expiration time.Duration) *libredis.BoolCmd {
+	return m.setNXFn(ctx, key, value, expiration)
+}
+
 func (m redisClientMock) Subscribe(ctx context.Context, channels ...string) *libredis.PubSub {
 	return m.subscribeFn(ctx, channels...)
 }
@@ -213,6 +223,126 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestSetNX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rClientMock RClient
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "success",
+			rClientMock: redisClientMock{setNXFn: func(_ context.Context, _ string, _ any, _ time.Duration) *libredis.BoolCmd {
+				return libredis.NewBoolResult(true, nil)
+			}},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "key already exists",
+			rClientMock: redisClientMock{setNXFn: func(_ context.Context, _ string, _ any, _ time.Duration) *libredis.BoolCmd {
+				return libredis.NewBoolResult(false, nil)
+			}},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			rClientMock: redisClientMock{setNXFn: func(_ context.Context, _ string, _ any, _ time.Duration) *libredis.BoolCmd {
+				return libredis.NewBoolResult(false, errors.New("test error"))
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := &SrvOptions{
+				Addr:     "test.redis.invalid:6379",
+				Username: "test_user",
+				Password: "test_password",
+				DB:       0,
+			}
+
+			ctx := t.Context()
+			cli, err := New(ctx, srvOpts)
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			cli.rclient = tt.rClientMock
+
+			got, err := cli.SetNX(ctx, "key_1", "value_1", time.Second)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rClientMock RClient
+		want        any
+		wantErr     bool
+	}{
+		{
+			name: "success",
+			rClientMock: redisClientMock{evalFn: func(_ context.Context, _ string, _ []string, _ ...any) *libredis.Cmd {
+				return libredis.NewCmdResult(int64(1), nil)
+			}},
+			want:    int64(1),
+			wantErr: false,
+		},
+		{
+			name: "error",
+			rClientMock: redisClientMock{evalFn: func(_ context.Context, _ string, _ []string, _ ...any) *libredis.Cmd {
+				return libredis.NewCmdResult(nil, errors.New("test error"))
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := &SrvOptions{
+				Addr:     "test.redis.invalid:6379",
+				Username: "test_user",
+				Password: "test_password",
+				DB:       0,
+			}
+
+			ctx := t.Context()
+			cli, err := New(ctx, srvOpts)
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			cli.rclient = tt.rClientMock
+
+			got, err := cli.Eval(ctx, "return 1", []string{"key_1"}, "value_1")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestGet(t *testing.T) {
 	t.Parallel()
 
@@ -18,6 +18,7 @@ type cfg struct {
 	srvOpts           *SrvOptions
 	subChannels       []string
 	subChannelOpts    []ChannelOption
+	tracePropagation  bool
 }
 
 func loadConfig(_ context.Context, srvOpts *SrvOptions, opts ...Option) (*cfg, error) {
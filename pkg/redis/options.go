@@ -33,3 +33,13 @@ func WithSubscrChannelOptions(opts ...ChannelOption) Option {
 		c.subChannelOpts = opts
 	}
 }
+
+// WithTracePropagation enables the propagation of the W3C trace context with the messages sent by Send() and SendData().
+// Redis messages have no headers, so the string messages are prefixed with the trace context;
+// the prefix is removed by Receive() and ReceiveData().
+// All the subscribers of the channels must use this package to receive the messages.
+func WithTracePropagation() Option {
+	return func(c *cfg) {
+		c.tracePropagation = true
+	}
+}
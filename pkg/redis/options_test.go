@@ -57,3 +57,11 @@ func Test_WithSubscrChannelOptions(t *testing.T) {
 	WithSubscrChannelOptions(opts...)(conf)
 	require.Len(t, conf.subChannelOpts, 1)
 }
+
+func Test_WithTracePropagation(t *testing.T) {
+	t.Parallel()
+
+	conf := &cfg{}
+	WithTracePropagation()(conf)
+	require.True(t, conf.tracePropagation)
+}
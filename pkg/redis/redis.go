@@ -10,5 +10,9 @@ entries. Additionally, it supports sending and receiving messages from channels.
 
 It allows to specify custom message encoding and decoding functions, including
 serialization and encryption.
+
+The messages are traced with the OpenTelemetry producer and consumer spans. The
+W3C trace context can be propagated with the messages (see
+WithTracePropagation and the github.com/Vonage/gosrvlib/pkg/tracing package).
 */
 package redis
@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Vonage/gosrvlib/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// messagingSystem is the messaging system name reported in the tracing spans.
+	messagingSystem = "redis"
+
+	// traceEnvelopePrefix marks the messages prefixed with the trace context.
+	traceEnvelopePrefix = "\x00trace\x00"
+)
+
+// wrapTraceContext prefixes the string message with the URL-encoded trace context of ctx,
+// followed by a new line. Other message types, or messages without a trace context, are returned unchanged.
+func wrapTraceContext(ctx context.Context, message any) any {
+	var payload string
+
+	switch m := message.(type) {
+	case string:
+		payload = m
+	case []byte:
+		payload = string(m)
+	default:
+		return message
+	}
+
+	carrier := propagation.MapCarrier{}
+	tracing.Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		return message
+	}
+
+	header := url.Values{}
+
+	for k, v := range carrier {
+		header.Set(k, v)
+	}
+
+	return traceEnvelopePrefix + header.Encode() + "\n" + payload
+}
+
+// unwrapTraceContext extracts the trace context from a message wrapped by wrapTraceContext,
+// and returns the original message. Other messages are returned unchanged.
+func unwrapTraceContext(ctx context.Context, message string) (context.Context, string) {
+	envelope, ok := strings.CutPrefix(message, traceEnvelopePrefix)
+	if !ok {
+		return ctx, message
+	}
+
+	header, payload, ok := strings.Cut(envelope, "\n")
+	if !ok {
+		return ctx, message
+	}
+
+	values, err := url.ParseQuery(header)
+	if err != nil {
+		return ctx, message
+	}
+
+	carrier := propagation.MapCarrier{}
+
+	for k := range values {
+		carrier.Set(k, values.Get(k))
+	}
+
+	return tracing.Extract(ctx, carrier), payload
+}
+
+// spanAttributes returns the messaging attributes of the tracing spans.
+func spanAttributes(channel string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("messaging.system", messagingSystem),
+		attribute.String("messaging.destination.name", channel),
+	}
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	libredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func testSpanContext() trace.SpanContext {
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func Test_wrapTraceContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := trace.ContextWithSpanContext(t.Context(), testSpanContext())
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		message any
+		want    any
+	}{
+		{
+			name:    "no trace context",
+			ctx:     t.Context(),
+			message: "message",
+			want:    "message",
+		},
+		{
+			name:    "not a string",
+			ctx:     ctx,
+			message: 123,
+			want:    123,
+		},
+		{
+			name:    "string",
+			ctx:     ctx,
+			message: "message\nwith new line",
+			want:    traceEnvelopePrefix + "traceparent=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01\nmessage\nwith new line",
+		},
+		{
+			name:    "bytes",
+			ctx:     ctx,
+			message: []byte("message"),
+			want:    traceEnvelopePrefix + "traceparent=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01\nmessage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, wrapTraceContext(tt.ctx, tt.message))
+		})
+	}
+}
+
+func Test_unwrapTraceContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		message   string
+		want      string
+		wantTrace bool
+	}{
+		{
+			name:    "plain",
+			message: "message",
+			want:    "message",
+		},
+		{
+			name:    "missing new line",
+			message: traceEnvelopePrefix + "traceparent=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:    traceEnvelopePrefix + "traceparent=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:    "invalid header",
+			message: traceEnvelopePrefix + "%zz\nmessage",
+			want:    traceEnvelopePrefix + "%zz\nmessage",
+		},
+		{
+			name:      "wrapped",
+			message:   traceEnvelopePrefix + "traceparent=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01\nmessage\nwith new line",
+			want:      "message\nwith new line",
+			wantTrace: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, got := unwrapTraceContext(t.Context(), tt.message)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantTrace, trace.SpanContextFromContext(ctx).IsValid())
+		})
+	}
+}
+
+func Test_traceContextPropagation(t *testing.T) {
+	t.Parallel()
+
+	srvOpts := &SrvOptions{
+		Addr: "test.redis.invalid:6379",
+	}
+
+	ctx := t.Context()
+	cli, err := New(ctx, srvOpts, WithTracePropagation())
+	require.NoError(t, err)
+
+	ch := make(chan *libredis.Message, 1)
+
+	cli.rclient = redisClientMock{publishFn: func(_ context.Context, channel string, message any) *libredis.IntCmd {
+		ch <- &libredis.Message{Channel: channel, Payload: message.(string)} //nolint:forcetypeassert
+
+		return libredis.NewIntResult(1, nil)
+	}}
+	cli.subch = ch
+
+	sc := testSpanContext()
+
+	err = cli.SendData(trace.ContextWithSpanContext(ctx, sc), "channel_1", "data")
+	require.NoError(t, err)
+
+	var data string
+
+	rctx, channel, err := cli.ReceiveDataContext(ctx, &data)
+	require.NoError(t, err)
+	require.Equal(t, "channel_1", channel)
+	require.Equal(t, "data", data)
+	require.Equal(t, sc.TraceID(), trace.SpanContextFromContext(rctx).TraceID())
+}
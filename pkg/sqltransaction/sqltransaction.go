@@ -16,9 +16,14 @@ import (
 	"fmt"
 
 	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// spanName is the name of the tracing span of the transaction.
+const spanName = "SQL transaction"
+
 // ExecFunc is the type of the function to be executed inside a SQL Transaction.
 type ExecFunc func(ctx context.Context, tx *sql.Tx) error
 
@@ -33,7 +38,18 @@ func Exec(ctx context.Context, db DB, run ExecFunc) error {
 }
 
 // ExecWithOptions executes the specified function inside a SQL transaction.
+// The transaction is traced with a span of the OpenTelemetry global TracerProvider (see the tracing package).
 func ExecWithOptions(ctx context.Context, db DB, run ExecFunc, opts *sql.TxOptions) error {
+	ctx, span := tracing.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient))
+
+	err := execWithOptions(ctx, db, run, opts)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func execWithOptions(ctx context.Context, db DB, run ExecFunc, opts *sql.TxOptions) error {
 	var committed bool
 
 	tx, err := db.BeginTx(ctx, opts)
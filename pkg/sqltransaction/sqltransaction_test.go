@@ -9,6 +9,11 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Vonage/gosrvlib/pkg/testutil"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func Test_Exec(t *testing.T) {
@@ -151,3 +156,27 @@ func Test_ExecWithOptions(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest
+func Test_ExecWithOptions_span(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer func() { _ = mockDB.Close() }()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = Exec(testutil.Context(), mockDB, func(_ context.Context, _ *sql.Tx) error { return errors.New("db error") })
+	require.Error(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, spanName, spans[0].Name())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/encode"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/tracing"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -111,19 +115,37 @@ type Message struct {
 }
 
 // Send delivers a raw string message to the queue.
+// The W3C trace context is propagated with the message attributes (see the tracing package).
 func (c *Client) Send(ctx context.Context, message string) error {
+	ctx, span := tracing.Start(
+		ctx,
+		spanName(c.queueURL, "send"),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(spanAttributes(c.queueURL)...),
+	)
+
+	attrs := attributeCarrier{}
+	tracing.Inject(ctx, attrs)
+
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+
 	_, err := c.sqs.SendMessage(
 		ctx,
 		&sqs.SendMessageInput{
-			QueueUrl:       c.queueURL,
-			MessageGroupId: c.messageGroupID,
-			MessageBody:    aws.String(message),
+			QueueUrl:          c.queueURL,
+			MessageGroupId:    c.messageGroupID,
+			MessageBody:       aws.String(message),
+			MessageAttributes: attrs,
 		})
 	if err != nil {
-		return fmt.Errorf("cannot send message to the queue: %w", err)
+		err = fmt.Errorf("cannot send message to the queue: %w", err)
 	}
 
-	return nil
+	tracing.End(span, err)
+
+	return err
 }
 
 // Receive retrieves a raw string message from the queue.
@@ -131,22 +153,42 @@ func (c *Client) Send(ctx context.Context, message string) error {
 // Once retrieved, a message will not be visible for up to VisibilityTimeout seconds.
 // Once processed the message should be removed from the queue by calling the Delete method.
 func (c *Client) Receive(ctx context.Context) (*Message, error) {
+	_, msg, err := c.ReceiveContext(ctx)
+	return msg, err
+}
+
+// ReceiveContext retrieves a raw string message from the queue (see Receive).
+// The returned context contains the consumer span, child of the trace context propagated with the message attributes,
+// and a logger tagged with the trace and span IDs (see the tracing package).
+func (c *Client) ReceiveContext(ctx context.Context) (context.Context, *Message, error) {
+	start := time.Now()
+
 	resp, err := c.sqs.ReceiveMessage(
 		ctx,
 		&sqs.ReceiveMessageInput{
-			QueueUrl:          c.queueURL,
-			WaitTimeSeconds:   c.waitTimeSeconds,
-			VisibilityTimeout: c.visibilityTimeout,
+			QueueUrl:              c.queueURL,
+			WaitTimeSeconds:       c.waitTimeSeconds,
+			VisibilityTimeout:     c.visibilityTimeout,
+			MessageAttributeNames: tracing.Propagator().Fields(),
 		})
 	if err != nil {
-		return nil, fmt.Errorf("cannot retrieve message from the queue: %w", err)
+		return ctx, nil, fmt.Errorf("cannot retrieve message from the queue: %w", err)
 	}
 
 	if len(resp.Messages) < 1 {
-		return nil, nil //nolint:nilnil
+		return ctx, nil, nil
 	}
 
-	return &Message{
+	ctx, span := tracing.Start(
+		tracing.Extract(ctx, attributeCarrier(resp.Messages[0].MessageAttributes)),
+		spanName(c.queueURL, "receive"),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(spanAttributes(c.queueURL)...),
+	)
+	span.End()
+
+	return logging.WithSpanContext(ctx), &Message{
 		Body:          aws.ToString(resp.Messages[0].Body),
 		ReceiptHandle: aws.ToString(resp.Messages[0].ReceiptHandle),
 	}, nil
@@ -210,18 +252,25 @@ func (c *Client) SendData(ctx context.Context, data any) error {
 // Once processed the message should be removed from the queue by calling the Delete method.
 // In case of decoding error the returned receipt handle will be not empty, so it can be used to delete the message.
 func (c *Client) ReceiveData(ctx context.Context, data any) (string, error) {
-	message, err := c.Receive(ctx)
+	_, receiptHandle, err := c.ReceiveDataContext(ctx, data)
+	return receiptHandle, err
+}
+
+// ReceiveDataContext retrieves a message from the queue, extract its content in the data and returns the ReceiptHandle (see ReceiveData).
+// The returned context contains the trace context propagated with the message (see ReceiveContext).
+func (c *Client) ReceiveDataContext(ctx context.Context, data any) (context.Context, string, error) {
+	ctx, message, err := c.ReceiveContext(ctx)
 	if err != nil {
-		return "", err
+		return ctx, "", err
 	}
 
 	if message == nil {
-		return "", nil
+		return ctx, "", nil
 	}
 
 	err = c.messageDecodeFunc(ctx, message.Body, data)
 
-	return message.ReceiptHandle, err
+	return ctx, message.ReceiptHandle, err
 }
 
 // HealthCheck checks if the current queue is present in the current region and returns an error otherwise.
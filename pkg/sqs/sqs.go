@@ -9,5 +9,9 @@ This package includes functions for sending, receiving, and deleting messages.
 
 It allows to specify custom message encoding and decoding functions, including
 serialization and encryption.
+
+The messages are traced with the OpenTelemetry producer and consumer spans, and
+the W3C trace context is propagated with the message attributes (see the
+github.com/Vonage/gosrvlib/pkg/tracing package).
 */
 package sqs
@@ -0,0 +1,63 @@
+package sqs
+
+import (
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// messagingSystem is the messaging system name reported in the tracing spans.
+	messagingSystem = "aws_sqs"
+
+	// attributeDataTypeString is the data type of the string message attributes.
+	attributeDataTypeString = "String"
+)
+
+// attributeCarrier adapts the SQS message attributes to the propagation.TextMapCarrier interface,
+// to propagate the trace context with the messages.
+type attributeCarrier map[string]types.MessageAttributeValue
+
+// Get returns the value of the string attribute with the specified key.
+func (c attributeCarrier) Get(key string) string {
+	v, ok := c[key]
+	if !ok || aws.ToString(v.DataType) != attributeDataTypeString {
+		return ""
+	}
+
+	return aws.ToString(v.StringValue)
+}
+
+// Set sets the value of the string attribute with the specified key.
+func (c attributeCarrier) Set(key, value string) {
+	c[key] = types.MessageAttributeValue{
+		DataType:    aws.String(attributeDataTypeString),
+		StringValue: aws.String(value),
+	}
+}
+
+// Keys returns the keys of all the attributes.
+func (c attributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// spanName returns the name of the tracing span for the queue and operation.
+func spanName(queueURL *string, operation string) string {
+	return path.Base(aws.ToString(queueURL)) + " " + operation
+}
+
+// spanAttributes returns the messaging attributes of the tracing spans.
+func spanAttributes(queueURL *string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("messaging.system", messagingSystem),
+		attribute.String("messaging.destination.name", path.Base(aws.ToString(queueURL))),
+	}
+}
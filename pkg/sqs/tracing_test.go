@@ -0,0 +1,85 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_attributeCarrier(t *testing.T) {
+	t.Parallel()
+
+	c := attributeCarrier{
+		"number": types.MessageAttributeValue{DataType: aws.String("Number"), StringValue: aws.String("1")},
+	}
+
+	require.Empty(t, c.Get("number"))
+	require.Empty(t, c.Get("missing"))
+
+	c.Set("alpha", "2")
+
+	require.Equal(t, "2", c.Get("alpha"))
+	require.ElementsMatch(t, []string{"alpha", "number"}, c.Keys())
+}
+
+func Test_spanName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "queue1.fifo send", spanName(aws.String("https://test_queue.invalid/queue1.fifo"), "send"))
+}
+
+func Test_traceContextPropagation(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	cli, err := New(ctx, "https://test_queue.invalid/queue5", "")
+	require.NoError(t, err)
+
+	var sent *sqs.SendMessageInput
+
+	cli.sqs = sqsmock{
+		sendFn: func(_ context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+			sent = params
+			return &sqs.SendMessageOutput{}, nil
+		},
+		receiveFn: func(_ context.Context, params *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+			require.Contains(t, params.MessageAttributeNames, "traceparent")
+
+			return &sqs.ReceiveMessageOutput{
+				Messages: []types.Message{
+					{
+						Body:              sent.MessageBody,
+						ReceiptHandle:     aws.String("handle"),
+						MessageAttributes: sent.MessageAttributes,
+					},
+				},
+			}, nil
+		},
+	}
+
+	// no trace context
+	err = cli.Send(ctx, "test")
+	require.NoError(t, err)
+	require.Nil(t, sent.MessageAttributes)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	err = cli.Send(trace.ContextWithSpanContext(ctx, sc), "test")
+	require.NoError(t, err)
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", attributeCarrier(sent.MessageAttributes).Get("traceparent"))
+
+	rctx, msg, err := cli.ReceiveContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "test", msg.Body)
+	require.Equal(t, sc.TraceID(), trace.SpanContextFromContext(rctx).TraceID())
+}
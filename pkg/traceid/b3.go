@@ -0,0 +1,270 @@
+package traceid
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// B3 propagation header names (https://github.com/openzipkin/b3-propagation).
+const (
+	// B3Header is the B3 single header name.
+	B3Header = "b3"
+
+	// B3TraceIDHeader is the B3 multi-header trace ID header name.
+	B3TraceIDHeader = "X-B3-TraceId"
+
+	// B3SpanIDHeader is the B3 multi-header span ID header name.
+	B3SpanIDHeader = "X-B3-SpanId"
+
+	// B3ParentSpanIDHeader is the B3 multi-header parent span ID header name.
+	B3ParentSpanIDHeader = "X-B3-ParentSpanId"
+
+	// B3SampledHeader is the B3 multi-header sampling decision header name.
+	B3SampledHeader = "X-B3-Sampled"
+
+	// B3FlagsHeader is the B3 multi-header debug flag header name.
+	B3FlagsHeader = "X-B3-Flags"
+)
+
+const b3TraceIDShortLen = 16
+
+// ErrInvalidB3 is returned when the B3 header values are not valid.
+var ErrInvalidB3 = errors.New("invalid B3 value")
+
+// B3Sampling is the B3 sampling state.
+type B3Sampling int
+
+// B3 sampling states.
+const (
+	// B3SamplingDefer leaves the sampling decision to the receiver.
+	B3SamplingDefer B3Sampling = iota
+
+	// B3SamplingDeny means the trace is not sampled.
+	B3SamplingDeny
+
+	// B3SamplingAccept means the trace is sampled.
+	B3SamplingAccept
+
+	// B3SamplingDebug means the trace is sampled in debug mode.
+	B3SamplingDebug
+)
+
+// String returns the B3 single header value of the sampling state.
+func (s B3Sampling) String() string {
+	switch s {
+	case B3SamplingDeny:
+		return "0"
+	case B3SamplingAccept:
+		return "1"
+	case B3SamplingDebug:
+		return "d"
+	case B3SamplingDefer:
+	}
+
+	return ""
+}
+
+// B3 contains the fields of the B3 propagation headers.
+type B3 struct {
+	// TraceID is the 16 or 32 lowercase hex characters ID of the whole trace.
+	// It is empty when only the sampling state is propagated.
+	TraceID string
+
+	// SpanID is the 16 lowercase hex characters ID of the caller span.
+	SpanID string
+
+	// ParentSpanID is the optional 16 lowercase hex characters ID of the parent of the caller span.
+	ParentSpanID string
+
+	// Sampling is the sampling state.
+	Sampling B3Sampling
+}
+
+// NewB3 generates a new B3 with a random 128-bit trace ID and a random span ID.
+func NewB3(sampling B3Sampling) B3 {
+	return B3{
+		TraceID:  randomHexID(traceIDLen),
+		SpanID:   randomHexID(spanIDLen),
+		Sampling: sampling,
+	}
+}
+
+// ParseB3 parses a B3 single header value: "{TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}",
+// where the last two fields are optional, or only "{SamplingState}".
+func ParseB3(s string) (B3, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+
+	if len(parts) == 1 {
+		sampling, ok := parseB3Sampling(parts[0])
+		if !ok {
+			return B3{}, ErrInvalidB3
+		}
+
+		return B3{Sampling: sampling}, nil
+	}
+
+	if len(parts) > 4 {
+		return B3{}, ErrInvalidB3
+	}
+
+	b := B3{
+		TraceID: parts[0],
+		SpanID:  parts[1],
+	}
+
+	if len(parts) > 2 {
+		sampling, ok := parseB3Sampling(parts[2])
+		if !ok {
+			return B3{}, ErrInvalidB3
+		}
+
+		b.Sampling = sampling
+	}
+
+	if len(parts) > 3 {
+		b.ParentSpanID = parts[3]
+	}
+
+	return b, b.validate()
+}
+
+// ParseB3Headers parses the B3 multi-header values.
+func ParseB3Headers(h http.Header) (B3, error) {
+	b := B3{
+		TraceID:      h.Get(B3TraceIDHeader),
+		SpanID:       h.Get(B3SpanIDHeader),
+		ParentSpanID: h.Get(B3ParentSpanIDHeader),
+	}
+
+	switch strings.ToLower(h.Get(B3SampledHeader)) {
+	case "":
+	case "1", "true":
+		b.Sampling = B3SamplingAccept
+	case "0", "false":
+		b.Sampling = B3SamplingDeny
+	default:
+		return B3{}, ErrInvalidB3
+	}
+
+	if h.Get(B3FlagsHeader) == "1" {
+		b.Sampling = B3SamplingDebug
+	}
+
+	if b.TraceID == "" && b.SpanID == "" && b.ParentSpanID == "" {
+		if b.Sampling == B3SamplingDefer {
+			return B3{}, ErrInvalidB3
+		}
+
+		return b, nil
+	}
+
+	return b, b.validate()
+}
+
+// Child returns a copy of the B3 with a new random span ID, child of the current one,
+// to be propagated to the downstream services.
+func (b B3) Child() B3 {
+	b.ParentSpanID = b.SpanID
+	b.SpanID = randomHexID(spanIDLen)
+
+	return b
+}
+
+// String returns the B3 single header value.
+func (b B3) String() string {
+	if b.TraceID == "" {
+		return b.Sampling.String()
+	}
+
+	s := b.TraceID + "-" + b.SpanID
+
+	if b.Sampling != B3SamplingDefer {
+		s += "-" + b.Sampling.String()
+	}
+
+	if b.ParentSpanID != "" {
+		if b.Sampling == B3SamplingDefer {
+			// the sampling state is required before the parent span ID
+			return s
+		}
+
+		s += "-" + b.ParentSpanID
+	}
+
+	return s
+}
+
+// B3FromHTTPRequestHeader parses the B3 single header of an HTTP Request,
+// or the B3 multi-header values if the single header is not set.
+func B3FromHTTPRequestHeader(r *http.Request) (B3, error) {
+	if s := r.Header.Get(B3Header); s != "" {
+		return ParseB3(s)
+	}
+
+	return ParseB3Headers(r.Header)
+}
+
+// SetHTTPRequestB3Header sets the B3 single header of an HTTP Request.
+func SetHTTPRequestB3Header(r *http.Request, b B3) {
+	r.Header.Set(B3Header, b.String())
+}
+
+// SetHTTPRequestB3Headers sets the B3 multi-header values of an HTTP Request.
+func SetHTTPRequestB3Headers(r *http.Request, b B3) {
+	setHeader := func(key, value string) {
+		if value == "" {
+			r.Header.Del(key)
+			return
+		}
+
+		r.Header.Set(key, value)
+	}
+
+	setHeader(B3TraceIDHeader, b.TraceID)
+	setHeader(B3SpanIDHeader, b.SpanID)
+	setHeader(B3ParentSpanIDHeader, b.ParentSpanID)
+
+	switch b.Sampling {
+	case B3SamplingDefer:
+		setHeader(B3SampledHeader, "")
+		setHeader(B3FlagsHeader, "")
+	case B3SamplingDeny, B3SamplingAccept:
+		setHeader(B3SampledHeader, b.Sampling.String())
+		setHeader(B3FlagsHeader, "")
+	case B3SamplingDebug:
+		setHeader(B3SampledHeader, "")
+		setHeader(B3FlagsHeader, "1")
+	}
+}
+
+// validate checks the B3 IDs.
+func (b B3) validate() error {
+	if !isValidHexID(b.TraceID, traceIDLen) && !isValidHexID(b.TraceID, b3TraceIDShortLen) {
+		return ErrInvalidB3
+	}
+
+	if !isValidHexID(b.SpanID, spanIDLen) {
+		return ErrInvalidB3
+	}
+
+	if b.ParentSpanID != "" && !isValidHexID(b.ParentSpanID, spanIDLen) {
+		return ErrInvalidB3
+	}
+
+	return nil
+}
+
+// parseB3Sampling parses the B3 single header sampling state.
+func parseB3Sampling(s string) (B3Sampling, bool) {
+	switch s {
+	case "0":
+		return B3SamplingDeny, true
+	case "1":
+		return B3SamplingAccept, true
+	case "d":
+		return B3SamplingDebug, true
+	}
+
+	return B3SamplingDefer, false
+}
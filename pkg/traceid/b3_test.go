@@ -0,0 +1,250 @@
+package traceid
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestB3Sampling_String(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, B3SamplingDefer.String())
+	require.Equal(t, "0", B3SamplingDeny.String())
+	require.Equal(t, "1", B3SamplingAccept.String())
+	require.Equal(t, "d", B3SamplingDebug.String())
+}
+
+func TestParseB3(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    B3
+		wantErr bool
+	}{
+		{
+			name:  "trace and span IDs",
+			value: "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1",
+			want:  B3{TraceID: "80f198ee56343ba864fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1"},
+		},
+		{
+			name:  "64-bit trace ID and sampling",
+			value: "64fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			want:  B3{TraceID: "64fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1", Sampling: B3SamplingAccept},
+		},
+		{
+			name:  "all fields",
+			value: "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-d-05e3ac9a4f6e3b90",
+			want: B3{
+				TraceID:      "80f198ee56343ba864fe8b2a57d3eff7",
+				SpanID:       "e457b5a2e4d86bd1",
+				ParentSpanID: "05e3ac9a4f6e3b90",
+				Sampling:     B3SamplingDebug,
+			},
+		},
+		{
+			name:  "sampling only",
+			value: "0",
+			want:  B3{Sampling: B3SamplingDeny},
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid sampling only",
+			value:   "x",
+			wantErr: true,
+		},
+		{
+			name:    "invalid sampling",
+			value:   "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-x",
+			wantErr: true,
+		},
+		{
+			name:    "invalid trace ID",
+			value:   "80f198ee56343ba8-64fe8b2a57d3eff7-e457b5a2e4d86bd1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid span ID",
+			value:   "80f198ee56343ba864fe8b2a57d3eff7-0000000000000000",
+			wantErr: true,
+		},
+		{
+			name:    "invalid parent span ID",
+			value:   "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05E3AC9A4F6E3B90",
+			wantErr: true,
+		},
+		{
+			name:    "too many fields",
+			value:   "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90-1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseB3(tt.value)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidB3)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.value, got.String())
+		})
+	}
+}
+
+func TestParseB3Headers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    B3
+		wantErr bool
+	}{
+		{
+			name: "all fields",
+			headers: map[string]string{
+				B3TraceIDHeader:      "80f198ee56343ba864fe8b2a57d3eff7",
+				B3SpanIDHeader:       "e457b5a2e4d86bd1",
+				B3ParentSpanIDHeader: "05e3ac9a4f6e3b90",
+				B3SampledHeader:      "1",
+			},
+			want: B3{
+				TraceID:      "80f198ee56343ba864fe8b2a57d3eff7",
+				SpanID:       "e457b5a2e4d86bd1",
+				ParentSpanID: "05e3ac9a4f6e3b90",
+				Sampling:     B3SamplingAccept,
+			},
+		},
+		{
+			name: "legacy sampled value",
+			headers: map[string]string{
+				B3TraceIDHeader: "64fe8b2a57d3eff7",
+				B3SpanIDHeader:  "e457b5a2e4d86bd1",
+				B3SampledHeader: "false",
+			},
+			want: B3{TraceID: "64fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1", Sampling: B3SamplingDeny},
+		},
+		{
+			name: "debug",
+			headers: map[string]string{
+				B3TraceIDHeader: "64fe8b2a57d3eff7",
+				B3SpanIDHeader:  "e457b5a2e4d86bd1",
+				B3FlagsHeader:   "1",
+			},
+			want: B3{TraceID: "64fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1", Sampling: B3SamplingDebug},
+		},
+		{
+			name:    "sampling only",
+			headers: map[string]string{B3SampledHeader: "0"},
+			want:    B3{Sampling: B3SamplingDeny},
+		},
+		{
+			name:    "empty",
+			headers: map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "invalid sampled",
+			headers: map[string]string{
+				B3TraceIDHeader: "64fe8b2a57d3eff7",
+				B3SpanIDHeader:  "e457b5a2e4d86bd1",
+				B3SampledHeader: "yes",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing span ID",
+			headers: map[string]string{B3TraceIDHeader: "64fe8b2a57d3eff7"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+
+			got, err := ParseB3Headers(h)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidB3)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewB3(t *testing.T) {
+	t.Parallel()
+
+	b := NewB3(B3SamplingAccept)
+	require.Regexp(t, `^[0-9a-f]{32}-[0-9a-f]{16}-1$`, b.String())
+
+	got, err := ParseB3(b.String())
+	require.NoError(t, err)
+	require.Equal(t, b, got)
+
+	// the parent span ID is omitted without the sampling state
+	b = NewB3(B3SamplingDefer).Child()
+	require.NotEmpty(t, b.ParentSpanID)
+	require.Regexp(t, `^[0-9a-f]{32}-[0-9a-f]{16}$`, b.String())
+}
+
+func TestB3_Child(t *testing.T) {
+	t.Parallel()
+
+	b := NewB3(B3SamplingAccept)
+	child := b.Child()
+
+	require.Equal(t, b.TraceID, child.TraceID)
+	require.Equal(t, b.SpanID, child.ParentSpanID)
+	require.Equal(t, b.Sampling, child.Sampling)
+	require.NotEqual(t, b.SpanID, child.SpanID)
+}
+
+func TestB3HTTPRequestHeader(t *testing.T) {
+	t.Parallel()
+
+	samplings := []B3Sampling{B3SamplingDefer, B3SamplingDeny, B3SamplingAccept, B3SamplingDebug}
+
+	for _, sampling := range samplings {
+		b := NewB3(sampling).Child()
+
+		// multi-header
+		r, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+		require.NoError(t, err)
+
+		SetHTTPRequestB3Headers(r, b)
+
+		got, err := B3FromHTTPRequestHeader(r)
+		require.NoError(t, err)
+		require.Equal(t, b, got)
+
+		// single header takes precedence
+		s := NewB3(B3SamplingAccept)
+		SetHTTPRequestB3Header(r, s)
+
+		got, err = B3FromHTTPRequestHeader(r)
+		require.NoError(t, err)
+		require.Equal(t, s, got)
+	}
+}
@@ -109,3 +109,53 @@ func ExampleFromHTTPRequestHeader() {
 	// default-1-103993
 	// test-1-413579
 }
+
+func ExampleFromHTTPRequest() {
+	r, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r.Header.Set(traceid.TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	v := traceid.FromHTTPRequest(r, traceid.DefaultHeader, "default-1-103993")
+
+	fmt.Println(v)
+
+	// Output:
+	// 4bf92f3577b34da6a3ce929d0e0e4736
+}
+
+func ExampleParseTraceParent() {
+	tp, err := traceid.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(tp.TraceID)
+	fmt.Println(tp.ParentID)
+	fmt.Println(tp.IsSampled())
+
+	// Output:
+	// 4bf92f3577b34da6a3ce929d0e0e4736
+	// 00f067aa0ba902b7
+	// true
+}
+
+func ExampleParseB3() {
+	b, err := traceid.ParseB3("80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(b.TraceID)
+	fmt.Println(b.SpanID)
+	fmt.Println(b.ParentSpanID)
+	fmt.Println(b.Sampling == traceid.B3SamplingAccept)
+
+	// Output:
+	// 80f198ee56343ba864fe8b2a57d3eff7
+	// e457b5a2e4d86bd1
+	// 05e3ac9a4f6e3b90
+	// true
+}
@@ -12,6 +12,13 @@ monitoring, and troubleshooting purposes.
 The Trace ID is expected to be a string that follows the regex pattern
 "^[0-9A-Za-z\-\_\.]{1,64}$". If the Trace ID does not match this pattern, the
 default value is used instead.
+
+The W3C Trace Context "traceparent" header (TraceParent) and the B3 single and
+multi-header formats (B3), used by Envoy, Istio and Zipkin, can be parsed and
+generated without a full tracing stack. The FromHTTPRequest function retrieves
+the trace ID from the configured header, falling back to the trace ID of the
+traceparent or B3 headers, so the services behind a proxy or service mesh get
+correlated IDs.
 */
 package traceid
 
@@ -79,3 +86,23 @@ func FromHTTPRequestHeader(r *http.Request, header, defaultValue string) string
 
 	return id
 }
+
+// FromHTTPRequest retrieves the trace ID from an HTTP Request, looking in order at:
+// the specified header (see FromHTTPRequestHeader), the trace ID of the W3C traceparent header,
+// and the trace ID of the B3 single or multi-header values.
+// If not found the default value is returned instead.
+func FromHTTPRequest(r *http.Request, header, defaultValue string) string {
+	if id := FromHTTPRequestHeader(r, header, ""); id != "" {
+		return id
+	}
+
+	if tp, err := TraceParentFromHTTPRequestHeader(r); err == nil {
+		return tp.TraceID
+	}
+
+	if b, err := B3FromHTTPRequestHeader(r); err == nil && b.TraceID != "" {
+		return b.TraceID
+	}
+
+	return defaultValue
+}
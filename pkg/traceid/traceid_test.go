@@ -115,3 +115,73 @@ func TestFromHTTPRequestHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestFromHTTPRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name: "default",
+			want: "default-1-563011",
+		},
+		{
+			name: "header has precedence",
+			headers: map[string]string{
+				DefaultHeader:     "test-1-218549",
+				TraceParentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				B3Header:          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			},
+			want: "test-1-218549",
+		},
+		{
+			name: "traceparent",
+			headers: map[string]string{
+				TraceParentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				B3Header:          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			},
+			want: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "b3 single",
+			headers: map[string]string{
+				TraceParentHeader: "invalid",
+				B3Header:          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+			},
+			want: "80f198ee56343ba864fe8b2a57d3eff7",
+		},
+		{
+			name: "b3 multi",
+			headers: map[string]string{
+				B3TraceIDHeader: "64fe8b2a57d3eff7",
+				B3SpanIDHeader:  "e457b5a2e4d86bd1",
+			},
+			want: "64fe8b2a57d3eff7",
+		},
+		{
+			name: "b3 sampling only",
+			headers: map[string]string{
+				B3Header: "1",
+			},
+			want: "default-1-563011",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			require.NoError(t, err)
+
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			require.Equal(t, tt.want, FromHTTPRequest(r, DefaultHeader, "default-1-563011"))
+		})
+	}
+}
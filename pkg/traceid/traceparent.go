@@ -0,0 +1,157 @@
+package traceid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header name.
+	TraceParentHeader = "traceparent"
+
+	// TraceParentVersion is the supported W3C Trace Context version.
+	TraceParentVersion = "00"
+
+	// traceParentFlagSampled is the W3C Trace Context sampled flag.
+	traceParentFlagSampled = 0x01
+
+	traceIDLen     = 32
+	spanIDLen      = 16
+	flagsLen       = 2
+	versionLen     = 2
+	traceParentLen = versionLen + 1 + traceIDLen + 1 + spanIDLen + 1 + flagsLen
+)
+
+// ErrInvalidTraceParent is returned when a W3C traceparent header value is not valid.
+var ErrInvalidTraceParent = errors.New("invalid traceparent value")
+
+// TraceParent contains the fields of the W3C Trace Context traceparent header
+// (https://www.w3.org/TR/trace-context/#traceparent-header).
+type TraceParent struct {
+	// TraceID is the 32 lowercase hex characters ID of the whole trace.
+	TraceID string
+
+	// ParentID is the 16 lowercase hex characters ID of the caller span.
+	ParentID string
+
+	// Flags are the trace flags (bit 0 is the sampled flag).
+	Flags byte
+}
+
+// NewTraceParent generates a new sampled or unsampled TraceParent with random IDs.
+func NewTraceParent(sampled bool) TraceParent {
+	tp := TraceParent{
+		TraceID:  randomHexID(traceIDLen),
+		ParentID: randomHexID(spanIDLen),
+	}
+
+	if sampled {
+		tp.Flags = traceParentFlagSampled
+	}
+
+	return tp
+}
+
+// ParseTraceParent parses a W3C traceparent header value.
+// The values with a higher version are parsed as version 00, as required by the specification.
+func ParseTraceParent(s string) (TraceParent, error) {
+	s = strings.TrimSpace(s)
+
+	if len(s) < traceParentLen {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+
+	version := s[:versionLen]
+
+	if !isHex(version) || version == "ff" || (version == TraceParentVersion && len(s) != traceParentLen) {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+
+	if len(s) > traceParentLen && s[traceParentLen] != '-' {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+
+	parts := strings.Split(s[:traceParentLen], "-")
+	if len(parts) != 4 || parts[0] != version {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+
+	traceID, parentID, flags := parts[1], parts[2], parts[3]
+
+	if !isValidHexID(traceID, traceIDLen) || !isValidHexID(parentID, spanIDLen) || !isHex(flags) {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+
+	f, _ := hex.DecodeString(flags) // already validated
+
+	return TraceParent{
+		TraceID:  traceID,
+		ParentID: parentID,
+		Flags:    f[0],
+	}, nil
+}
+
+// IsSampled returns true if the sampled flag is set.
+func (tp TraceParent) IsSampled() bool {
+	return tp.Flags&traceParentFlagSampled != 0
+}
+
+// Child returns a copy of the TraceParent with a new random parent ID,
+// to be propagated to the downstream services.
+func (tp TraceParent) Child() TraceParent {
+	tp.ParentID = randomHexID(spanIDLen)
+	return tp
+}
+
+// String returns the traceparent header value.
+func (tp TraceParent) String() string {
+	return TraceParentVersion + "-" + tp.TraceID + "-" + tp.ParentID + "-" + hex.EncodeToString([]byte{tp.Flags})
+}
+
+// TraceParentFromHTTPRequestHeader parses the W3C traceparent header of an HTTP Request.
+func TraceParentFromHTTPRequestHeader(r *http.Request) (TraceParent, error) {
+	return ParseTraceParent(r.Header.Get(TraceParentHeader))
+}
+
+// SetHTTPRequestTraceParentHeader sets the W3C traceparent header of an HTTP Request.
+func SetHTTPRequestTraceParentHeader(r *http.Request, tp TraceParent) {
+	r.Header.Set(TraceParentHeader, tp.String())
+}
+
+// randomHexID returns a random non-zero lowercase hex ID with the specified length.
+func randomHexID(n int) string {
+	b := make([]byte, n/2)
+
+	for {
+		_, _ = rand.Read(b) // never returns an error
+
+		id := hex.EncodeToString(b)
+		if !isZeroID(id) {
+			return id
+		}
+	}
+}
+
+// isValidHexID returns true if the ID is a non-zero lowercase hex string with the specified length.
+func isValidHexID(id string, n int) bool {
+	return len(id) == n && isHex(id) && !isZeroID(id)
+}
+
+// isHex returns true if the string only contains lowercase hex characters.
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+// isZeroID returns true if the ID only contains zeros.
+func isZeroID(id string) bool {
+	return strings.Trim(id, "0") == ""
+}
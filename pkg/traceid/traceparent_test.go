@@ -0,0 +1,139 @@
+package traceid
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    TraceParent
+		wantErr bool
+	}{
+		{
+			name:  "valid sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:  TraceParent{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", ParentID: "00f067aa0ba902b7", Flags: 0x01},
+		},
+		{
+			name:  "valid not sampled with spaces",
+			value: " 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00 ",
+			want:  TraceParent{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", ParentID: "00f067aa0ba902b7", Flags: 0x00},
+		},
+		{
+			name:  "future version with extra fields",
+			value: "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-what-the-future-will-be-like",
+			want:  TraceParent{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", ParentID: "00f067aa0ba902b7", Flags: 0x01},
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+		{
+			name:    "version 00 with extra fields",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			wantErr: true,
+		},
+		{
+			name:    "future version with invalid separator",
+			value:   "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.extra",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			value:   "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantErr: true,
+		},
+		{
+			name:    "uppercase trace ID",
+			value:   "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			wantErr: true,
+		},
+		{
+			name:    "zero trace ID",
+			value:   "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			wantErr: true,
+		},
+		{
+			name:    "zero parent ID",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			wantErr: true,
+		},
+		{
+			name:    "invalid flags",
+			value:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x",
+			wantErr: true,
+		},
+		{
+			name:    "invalid separators",
+			value:   "00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTraceParent(tt.value)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTraceParent)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewTraceParent(t *testing.T) {
+	t.Parallel()
+
+	tp := NewTraceParent(true)
+	require.True(t, tp.IsSampled())
+	require.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, tp.String())
+
+	got, err := ParseTraceParent(tp.String())
+	require.NoError(t, err)
+	require.Equal(t, tp, got)
+
+	tp = NewTraceParent(false)
+	require.False(t, tp.IsSampled())
+	require.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-00$`, tp.String())
+}
+
+func TestTraceParent_Child(t *testing.T) {
+	t.Parallel()
+
+	tp := NewTraceParent(true)
+	child := tp.Child()
+
+	require.Equal(t, tp.TraceID, child.TraceID)
+	require.Equal(t, tp.Flags, child.Flags)
+	require.NotEqual(t, tp.ParentID, child.ParentID)
+}
+
+func TestTraceParentHTTPRequestHeader(t *testing.T) {
+	t.Parallel()
+
+	r, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	_, err = TraceParentFromHTTPRequestHeader(r)
+	require.Error(t, err)
+
+	tp := NewTraceParent(true)
+	SetHTTPRequestTraceParentHeader(r, tp)
+
+	got, err := TraceParentFromHTTPRequestHeader(r)
+	require.NoError(t, err)
+	require.Equal(t, tp, got)
+}
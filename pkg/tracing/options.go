@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// DefaultServiceName is the default service name reported with the spans.
+	DefaultServiceName = "gosrvlib"
+
+	// DefaultSampleRatio is the default ratio of the root traces to sample.
+	DefaultSampleRatio = 1.0
+)
+
+// Option is the interface that allows to set the provider options.
+type Option func(c *config) error
+
+type config struct {
+	serviceName    string
+	serviceVersion string
+	sampleRatio    float64
+	exporter       sdktrace.SpanExporter
+	otlpOpts       []otlptracehttp.Option
+}
+
+func defaultConfig() *config {
+	return &config{
+		serviceName: DefaultServiceName,
+		sampleRatio: DefaultSampleRatio,
+	}
+}
+
+// WithService sets the name and version of the service reported with the spans.
+func WithService(name, version string) Option {
+	return func(c *config) error {
+		if name == "" {
+			return errors.New("the service name is required")
+		}
+
+		c.serviceName = name
+		c.serviceVersion = version
+
+		return nil
+	}
+}
+
+// WithSampleRatio sets the ratio of the root traces to sample, between 0 and 1 (default 1).
+// The sampling decision of the remote parent is always respected.
+func WithSampleRatio(ratio float64) Option {
+	return func(c *config) error {
+		if ratio < 0 || ratio > 1 {
+			return errors.New("the sample ratio must be between 0 and 1")
+		}
+
+		c.sampleRatio = ratio
+
+		return nil
+	}
+}
+
+// WithEndpointURL sets the OTLP/HTTP collector URL (e.g. "http://localhost:4318/v1/traces").
+func WithEndpointURL(url string) Option {
+	return func(c *config) error {
+		if url == "" {
+			return errors.New("the endpoint URL is required")
+		}
+
+		c.otlpOpts = append(c.otlpOpts, otlptracehttp.WithEndpointURL(url))
+
+		return nil
+	}
+}
+
+// WithHeaders sets additional HTTP headers sent to the OTLP/HTTP collector (e.g. for authentication).
+func WithHeaders(headers map[string]string) Option {
+	return func(c *config) error {
+		c.otlpOpts = append(c.otlpOpts, otlptracehttp.WithHeaders(headers))
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send the spans to the OTLP/HTTP collector.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) error {
+		c.otlpOpts = append(c.otlpOpts, otlptracehttp.WithHTTPClient(client))
+		return nil
+	}
+}
+
+// WithExporter sets a custom span exporter in place of the OTLP/HTTP one.
+func WithExporter(exporter sdktrace.SpanExporter) Option {
+	return func(c *config) error {
+		if exporter == nil {
+			return errors.New("the exporter is required")
+		}
+
+		c.exporter = exporter
+
+		return nil
+	}
+}
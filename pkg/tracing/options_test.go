@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithService(t *testing.T) {
+	t.Parallel()
+
+	c := defaultConfig()
+
+	err := WithService("test", "1.2.3")(c)
+	require.NoError(t, err)
+	require.Equal(t, "test", c.serviceName)
+	require.Equal(t, "1.2.3", c.serviceVersion)
+
+	err = WithService("", "1.2.3")(c)
+	require.Error(t, err)
+}
+
+func TestWithSampleRatio(t *testing.T) {
+	t.Parallel()
+
+	c := defaultConfig()
+
+	err := WithSampleRatio(0.5)(c)
+	require.NoError(t, err)
+	require.InDelta(t, 0.5, c.sampleRatio, 0.001)
+
+	err = WithSampleRatio(-0.1)(c)
+	require.Error(t, err)
+
+	err = WithSampleRatio(1.1)(c)
+	require.Error(t, err)
+}
+
+func TestWithEndpointURL(t *testing.T) {
+	t.Parallel()
+
+	c := defaultConfig()
+
+	err := WithEndpointURL("http://localhost:4318/v1/traces")(c)
+	require.NoError(t, err)
+	require.Len(t, c.otlpOpts, 1)
+
+	err = WithEndpointURL("")(c)
+	require.Error(t, err)
+}
+
+func TestWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	c := defaultConfig()
+
+	err := WithHeaders(map[string]string{"Authorization": "Bearer test"})(c)
+	require.NoError(t, err)
+	require.Len(t, c.otlpOpts, 1)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	c := defaultConfig()
+
+	err := WithHTTPClient(&http.Client{})(c)
+	require.NoError(t, err)
+	require.Len(t, c.otlpOpts, 1)
+}
+
+func TestWithExporter(t *testing.T) {
+	t.Parallel()
+
+	c := defaultConfig()
+	exp := tracetest.NewInMemoryExporter()
+
+	err := WithExporter(exp)(c)
+	require.NoError(t, err)
+	require.Equal(t, exp, c.exporter)
+
+	err = WithExporter(nil)(c)
+	require.Error(t, err)
+}
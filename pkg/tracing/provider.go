@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Provider is an OpenTelemetry TracerProvider exporting the spans in batches.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// New creates a new TracerProvider exporting the spans to an OTLP/HTTP collector,
+// and registers it as the global OpenTelemetry TracerProvider, together with the
+// W3C Trace Context and Baggage propagator.
+//
+// Unless configured with WithEndpointURL, the collector endpoint is read from
+// the standard OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT
+// environment variables, or defaults to https://localhost:4318/v1/traces.
+//
+// The Shutdown method must be called before exiting the program to flush the pending spans.
+func New(ctx context.Context, opts ...Option) (*Provider, error) {
+	cfg := defaultConfig()
+
+	for _, applyOpt := range opts {
+		err := applyOpt(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exporter := cfg.exporter
+
+	if exporter == nil {
+		var err error
+
+		exporter, err = otlptracehttp.New(ctx, cfg.otlpOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+		}
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", cfg.serviceName)}
+
+	if cfg.serviceVersion != "" {
+		attrs = append(attrs, attribute.String("service.version", cfg.serviceVersion))
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.sampleRatio))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+
+	return &Provider{tp: tp}, nil
+}
+
+// TracerProvider returns the underlying OpenTelemetry SDK TracerProvider.
+func (p *Provider) TracerProvider() *sdktrace.TracerProvider {
+	return p.tp
+}
+
+// ForceFlush exports all the ended spans that have not yet been exported.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	err := p.tp.ForceFlush(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to flush the spans: %w", err)
+	}
+
+	return nil
+}
+
+// Shutdown flushes the pending spans and stops the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.tp.Shutdown(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to shutdown the tracer provider: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// testCollector is an in-process OTLP/HTTP collector.
+type testCollector struct {
+	mux   sync.Mutex
+	spans map[string]string // span name -> service name
+}
+
+func (c *testCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req := &coltracepb.ExportTraceServiceRequest{}
+
+	err = proto.Unmarshal(body, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, rs := range req.GetResourceSpans() {
+		var service string
+
+		for _, attr := range rs.GetResource().GetAttributes() {
+			if attr.GetKey() == "service.name" {
+				service = attr.GetValue().GetStringValue()
+			}
+		}
+
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				c.spans[span.GetName()] = service
+			}
+		}
+	}
+
+	resp, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+//nolint:paralleltest
+func TestNew(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	collector := &testCollector{spans: make(map[string]string)}
+
+	server := httptest.NewServer(collector)
+	t.Cleanup(server.Close)
+
+	ctx := t.Context()
+
+	p, err := New(
+		ctx,
+		WithEndpointURL(server.URL+"/v1/traces"),
+		WithService("test-service", "1.2.3"),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, p.TracerProvider())
+
+	_, span := Start(ctx, "test-span")
+	require.True(t, span.IsRecording())
+	End(span, nil)
+
+	err = p.ForceFlush(ctx)
+	require.NoError(t, err)
+
+	collector.mux.Lock()
+	require.Equal(t, "test-service", collector.spans["test-span"])
+	collector.mux.Unlock()
+
+	err = p.Shutdown(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest
+func TestNew_exporter(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	ctx := t.Context()
+	exp := tracetest.NewInMemoryExporter()
+
+	p, err := New(ctx, WithExporter(exp), WithSampleRatio(0))
+	require.NoError(t, err)
+
+	// not sampled
+	_, span := Start(ctx, "test-span")
+	End(span, nil)
+
+	err = p.ForceFlush(ctx)
+	require.NoError(t, err)
+	require.Empty(t, exp.GetSpans())
+
+	err = p.Shutdown(ctx)
+	require.NoError(t, err)
+}
+
+func TestNew_error(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(t.Context(), WithSampleRatio(2))
+	require.Error(t, err)
+	require.Nil(t, p)
+}
@@ -0,0 +1,87 @@
+/*
+Package tracing provides the OpenTelemetry distributed tracing support shared by
+the other gosrvlib packages.
+
+The spans are created with the global OpenTelemetry TracerProvider, so they are
+no-op until a provider is registered (e.g. with New). The trace context is
+always propagated using the W3C Trace Context ("traceparent" and "tracestate")
+and Baggage formats, even when no provider is registered, so the incoming trace
+is passed on to the downstream services.
+
+The following packages are instrumented:
+
+  - github.com/Vonage/gosrvlib/pkg/httpserver extracts the incoming trace
+    context and starts a server span for each request;
+
+  - github.com/Vonage/gosrvlib/pkg/httpclient starts a client span and injects
+    the trace context in the outgoing request headers;
+
+  - github.com/Vonage/gosrvlib/pkg/sqltransaction starts a span around each
+    transaction;
+
+  - github.com/Vonage/gosrvlib/pkg/kafka, github.com/Vonage/gosrvlib/pkg/sqs
+    and github.com/Vonage/gosrvlib/pkg/redis start producer and consumer spans,
+    and propagate the trace context with the messages.
+
+The trace and span IDs are added to the context logger by
+github.com/Vonage/gosrvlib/pkg/logging.WithSpanContext.
+
+The optional OTLP/HTTP exporter is configured with New.
+*/
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the OpenTelemetry tracer used by the gosrvlib packages.
+const InstrumentationName = "github.com/Vonage/gosrvlib"
+
+// propagator is the W3C Trace Context and Baggage propagator.
+//
+//nolint:gochecknoglobals
+var propagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Propagator returns the W3C Trace Context and Baggage propagator used by the gosrvlib packages.
+func Propagator() propagation.TextMapPropagator {
+	return propagator
+}
+
+// Tracer returns the gosrvlib tracer from the global OpenTelemetry TracerProvider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Start creates a span and a context containing the newly-created span.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...) //nolint:spancheck
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Inject sets the trace context of ctx into the carrier (e.g. HTTP or message headers).
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+}
+
+// Extract returns a copy of ctx with the trace context read from the carrier (e.g. HTTP or message headers).
+// The extracted span context is set as remote parent of the spans created with the returned context.
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return propagator.Extract(ctx, carrier)
+}
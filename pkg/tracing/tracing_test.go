@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagator(t *testing.T) {
+	t.Parallel()
+
+	require.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, Propagator().Fields())
+}
+
+func TestInjectExtract(t *testing.T) {
+	t.Parallel()
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	h := http.Header{}
+	h.Set("traceparent", traceparent)
+	h.Set("tracestate", "vendor=value")
+
+	ctx := Extract(t.Context(), propagation.HeaderCarrier(h))
+
+	sc := trace.SpanContextFromContext(ctx)
+	require.True(t, sc.IsValid())
+	require.True(t, sc.IsRemote())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+	require.Equal(t, "vendor=value", sc.TraceState().String())
+
+	out := http.Header{}
+	Inject(ctx, propagation.HeaderCarrier(out))
+
+	require.Equal(t, traceparent, out.Get("traceparent"))
+	require.Equal(t, "vendor=value", out.Get("tracestate"))
+
+	// no trace context
+	empty := http.Header{}
+	Inject(t.Context(), propagation.HeaderCarrier(empty))
+	require.Empty(t, empty)
+}
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	// the global provider is no-op by default
+	ctx, span := Start(t.Context(), "test")
+	require.NotNil(t, ctx)
+	require.False(t, span.IsRecording())
+
+	End(span, errors.New("error"))
+}
+
+func TestEnd(t *testing.T) {
+	t.Parallel()
+
+	sr := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)).Tracer("test")
+
+	_, span := tracer.Start(t.Context(), "success")
+	End(span, nil)
+
+	_, span = tracer.Start(t.Context(), "failure")
+	End(span, errors.New("test error"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Empty(t, spans[0].Events())
+
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Equal(t, "test error", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+}
@@ -85,6 +85,21 @@ func (c *Client) Set(ctx context.Context, key string, value string, exp time.Dur
 	return nil
 }
 
+// SetNX sets a raw string value for the specified key with an expiration time, only if the key does not exist.
+// Returns true if the key has been set.
+func (c *Client) SetNX(ctx context.Context, key string, value string, exp time.Duration) (bool, error) {
+	err := c.vkclient.Do(ctx, c.vkclient.B().Set().Key(key).Value(value).Nx().Ex(exp).Build()).Error()
+	if libvalkey.IsValkeyNil(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("cannot set key: %s %w", key, err)
+	}
+
+	return true, nil
+}
+
 // Get retrieves the raw string value of the specified key.
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	value, err := c.vkclient.Do(ctx, c.vkclient.B().Get().Key(key).Build()).ToString()
@@ -105,6 +120,19 @@ func (c *Client) Del(ctx context.Context, key string) error {
 	return nil
 }
 
+// Eval executes a Lua script on the server with the specified keys and arguments, and returns its result.
+// This can be used to atomically execute multiple operations (e.g. compare-and-set).
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...string) (any, error) {
+	cmd := c.vkclient.B().Eval().Script(script).Numkeys(int64(len(keys))).Key(keys...).Arg(args...).Build()
+
+	res, err := c.vkclient.Do(ctx, cmd).ToAny()
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute script: %w", err)
+	}
+
+	return res, nil
+}
+
 // Send publish a raw string value to the specified channel.
 func (c *Client) Send(ctx context.Context, channel string, message string) error {
 	err := c.vkclient.Do(ctx, c.vkclient.B().Publish().Channel(channel).Message(message).Build()).Error()
@@ -135,6 +135,165 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestSetNX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		val     string
+		exp     time.Duration
+		mock    func(ctx context.Context, vkc *mock.Client)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "success",
+			key:  "key1",
+			val:  "val1",
+			exp:  time.Second,
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SET", "key1", "val1", "NX", "EX", "1"),
+				).Return(mock.Result(mock.ValkeyString("OK")))
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "key already exists",
+			key:  "key2",
+			val:  "val2",
+			exp:  time.Second,
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SET", "key2", "val2", "NX", "EX", "1"),
+				).Return(mock.Result(mock.ValkeyNil()))
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			key:  "key3",
+			val:  "val3",
+			exp:  2 * time.Second,
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SET", "key3", "val3", "NX", "EX", "2"),
+				).Return(mock.ErrorResult(errors.New("error")))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := getTestSrvOptions()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() { ctrl.Finish() })
+
+			vkc := mock.NewClient(ctrl)
+			ctx := t.Context()
+
+			cli, err := New(
+				ctx,
+				srvOpts,
+				WithValkeyClient(vkc),
+			)
+
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			tt.mock(ctx, vkc)
+
+			got, err := cli.SetNX(ctx, tt.key, tt.val, tt.exp)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mock    func(ctx context.Context, vkc *mock.Client)
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "success",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", "return 1", "1", "key1", "val1"),
+				).Return(mock.Result(mock.ValkeyInt64(1)))
+			},
+			want:    int64(1),
+			wantErr: false,
+		},
+		{
+			name: "error",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", "return 1", "1", "key1", "val1"),
+				).Return(mock.ErrorResult(errors.New("error")))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := getTestSrvOptions()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() { ctrl.Finish() })
+
+			vkc := mock.NewClient(ctrl)
+			ctx := t.Context()
+
+			cli, err := New(
+				ctx,
+				srvOpts,
+				WithValkeyClient(vkc),
+			)
+
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			tt.mock(ctx, vkc)
+
+			got, err := cli.Eval(ctx, "return 1", []string{"key1"}, "val1")
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestGet(t *testing.T) {
 	t.Parallel()
 